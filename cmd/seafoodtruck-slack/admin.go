@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	s "github.com/appsbyram/pkg/http"
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/slack-go/slack/slackevents"
+	"go.uber.org/zap"
+)
+
+const (
+	authorizationHeader = "Authorization"
+	bearerPrefix        = "Bearer "
+)
+
+//AdminResponse is returned by the admin endpoints
+type AdminResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+//authorized accepts a client certificate verified against admin-client-ca or checks the
+//bearer token of r against ADMIN_TOKEN, admin endpoints are disabled when neither is configured
+func authorized(r *http.Request) bool {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return true
+	}
+	if len(adminToken) == 0 {
+		return false
+	}
+	h := r.Header.Get(authorizationHeader)
+	if !strings.HasPrefix(h, bearerPrefix) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(h[len(bearerPrefix):]), []byte(adminToken)) == 1
+}
+
+//adminPostHandler immediately posts events for ?channel= and optional ?day=
+func adminPostHandler(w http.ResponseWriter, r *http.Request) {
+	p := s.NewPayload()
+	if !authorized(r) {
+		p.WriteResponse(s.ContentTypeJSON, http.StatusUnauthorized, &AdminResponse{Status: "unauthorized"}, w)
+		return
+	}
+	ch := r.URL.Query().Get("channel")
+	day := r.URL.Query().Get("day")
+	if len(ch) == 0 {
+		p.WriteResponse(s.ContentTypeJSON, http.StatusBadRequest, &AdminResponse{Status: "error", Message: "channel is required"}, w)
+		return
+	}
+	if err := postEvents(ch, day, locationsFor(ch)); err != nil {
+		logger.Errorw("Error posting events on admin request", zap.Error(err))
+		p.WriteResponse(s.ContentTypeJSON, http.StatusBadGateway, &AdminResponse{Status: "error", Message: err.Error()}, w)
+		return
+	}
+	p.WriteResponse(s.ContentTypeJSON, http.StatusOK, &AdminResponse{Status: "posted"}, w)
+}
+
+//isAdmin reports whether the Slack user is listed in ADMIN_USERS
+func isAdmin(user string) bool {
+	for _, u := range commands.SplitIDs(adminUsers) {
+		if u == user {
+			return true
+		}
+	}
+	return false
+}
+
+//postSchedule handles the admin command, posting today's or tomorrow's schedule right away
+func postSchedule(event *slackevents.AppMentionEvent, args string) {
+	if !isAdmin(event.User) {
+		postEphemeral(event, tr(event, i18n.PostAdminOnly))
+		return
+	}
+	day := today
+	if strings.Contains(args, tomorrow) {
+		day = tomorrow
+	}
+	publisher.Find(event.Channel, day, locationsFor(event.Channel), "")
+}
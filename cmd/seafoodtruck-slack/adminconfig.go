@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	s "github.com/appsbyram/pkg/http"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+//withStoredGroups applies the location groups set through the admin api over configured,
+//they take precedence like stored schedules do. An empty stored group deletes the group.
+func withStoredGroups(configured map[string][]string) map[string][]string {
+	groups := make(map[string][]string)
+	for name, ids := range configured {
+		groups[name] = ids
+	}
+	values, err := kv.List(store.Groups)
+	if err != nil {
+		logger.Warnw("Error loading stored location groups", zap.Error(err))
+		return groups
+	}
+	for name, data := range values {
+		var ids []string
+		if err := json.Unmarshal(data, &ids); err != nil {
+			logger.Warnw("Ignoring stored location group "+name, zap.Error(err))
+			continue
+		}
+		if len(ids) == 0 {
+			delete(groups, name)
+			continue
+		}
+		groups[name] = ids
+	}
+	return groups
+}
+
+//saveGroup persists the locations of group and applies them, no locations delete it
+func saveGroup(name string, ids []string) error {
+	name = strings.ToLower(name)
+	if ids == nil {
+		ids = []string{}
+	}
+	if err := store.PutJSON(kv, store.Groups, name, ids); err != nil {
+		return err
+	}
+
+	groupsMu.Lock()
+	groups := make(map[string][]string)
+	for n, g := range locationGroups {
+		groups[n] = g
+	}
+	if len(ids) == 0 {
+		delete(groups, name)
+	} else {
+		groups[name] = ids
+	}
+	locationGroups = groups
+	groupsMu.Unlock()
+
+	//schedules may name the group, their jobs captured the old locations
+	runner.Restart(nil)
+	return nil
+}
+
+//adminGroupsHandler lists the location groups
+func adminGroupsHandler(w http.ResponseWriter, r *http.Request) {
+	p := s.NewPayload()
+	if !authorized(r) {
+		p.WriteResponse(s.ContentTypeJSON, http.StatusUnauthorized, &AdminResponse{Status: "unauthorized"}, w)
+		return
+	}
+	groups := currentLocationGroups()
+	p.WriteResponse(s.ContentTypeJSON, http.StatusOK, &groups, w)
+}
+
+//adminGroupHandler sets the group {name} to the json list of location ids in the body
+//on PUT and deletes it on DELETE
+func adminGroupHandler(w http.ResponseWriter, r *http.Request) {
+	p := s.NewPayload()
+	if !authorized(r) {
+		p.WriteResponse(s.ContentTypeJSON, http.StatusUnauthorized, &AdminResponse{Status: "unauthorized"}, w)
+		return
+	}
+	name := mux.Vars(r)["name"]
+	var ids []string
+	if r.Method == http.MethodPut {
+		r.Body = http.MaxBytesReader(w, r.Body, maxPayloadBytes)
+		if err := json.NewDecoder(r.Body).Decode(&ids); err != nil || len(ids) == 0 {
+			p.WriteResponse(s.ContentTypeJSON, http.StatusBadRequest, &AdminResponse{Status: "error", Message: "body must be a non empty list of location ids"}, w)
+			return
+		}
+	}
+	if err := saveGroup(name, ids); err != nil {
+		requestLogger(r.Context()).Errorw("Error saving location group", zap.Error(err))
+		p.WriteResponse(s.ContentTypeJSON, http.StatusInternalServerError, &AdminResponse{Status: "error", Message: err.Error()}, w)
+		return
+	}
+	p.WriteResponse(s.ContentTypeJSON, http.StatusOK, &AdminResponse{Status: "ok"}, w)
+}
+
+//adminSchedulesHandler lists the schedules of all channels, configured, subscribed and discovered
+func adminSchedulesHandler(w http.ResponseWriter, r *http.Request) {
+	p := s.NewPayload()
+	if !authorized(r) {
+		p.WriteResponse(s.ContentTypeJSON, http.StatusUnauthorized, &AdminResponse{Status: "unauthorized"}, w)
+		return
+	}
+	schedulesMu.RLock()
+	all := make(map[string]Schedule, len(schedules))
+	for ch, sch := range schedules {
+		all[ch] = sch
+	}
+	schedulesMu.RUnlock()
+	p.WriteResponse(s.ContentTypeJSON, http.StatusOK, &all, w)
+}
+
+//adminScheduleHandler subscribes {channel} with the json schedule in the body on PUT and
+//unsubscribes it on DELETE, persisting like the subscribe command does
+func adminScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	p := s.NewPayload()
+	if !authorized(r) {
+		p.WriteResponse(s.ContentTypeJSON, http.StatusUnauthorized, &AdminResponse{Status: "unauthorized"}, w)
+		return
+	}
+	ch := mux.Vars(r)["channel"]
+	var err error
+	if r.Method == http.MethodDelete {
+		err = deleteSchedule(ch)
+	} else {
+		var sch Schedule
+		r.Body = http.MaxBytesReader(w, r.Body, maxPayloadBytes)
+		if derr := json.NewDecoder(r.Body).Decode(&sch); derr != nil || len(sch.Locations) == 0 {
+			p.WriteResponse(s.ContentTypeJSON, http.StatusBadRequest, &AdminResponse{Status: "error", Message: "body must be a schedule with locations"}, w)
+			return
+		}
+		sch.Channel = ch
+		err = saveSchedule(sch)
+	}
+	if err != nil {
+		requestLogger(r.Context()).Errorw("Error saving schedule", zap.Error(err))
+		p.WriteResponse(s.ContentTypeJSON, http.StatusBadRequest, &AdminResponse{Status: "error", Message: err.Error()}, w)
+		return
+	}
+	p.WriteResponse(s.ContentTypeJSON, http.StatusOK, &AdminResponse{Status: "ok"}, w)
+}
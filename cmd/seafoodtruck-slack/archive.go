@@ -0,0 +1,349 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	topTrucks = 5
+	//defaultNewTruckMonths is how long a truck must have been away from a location to
+	//be badged new there
+	defaultNewTruckMonths = 6
+	//defaultArchiveDays is how long archived days are kept, covering a year of
+	//leaderboard and the new truck badge
+	defaultArchiveDays = 400
+)
+
+//ArchivedDay is what was booked at a location on a day
+type ArchivedDay struct {
+	LocationID   string          `json:"location_id"`
+	LocationName string          `json:"location_name"`
+	Day          string          `json:"day"`
+	Trucks       []ArchivedTruck `json:"trucks"`
+}
+
+//ArchivedTruck is a truck booked on an archived day
+type ArchivedTruck struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	FoodCategories []string  `json:"food_categories"`
+	Start          time.Time `json:"start,omitempty"`
+	End            time.Time `json:"end,omitempty"`
+}
+
+//archiveSinceKey is the key of the first day archived at a location, the days being
+//keyed by date
+func archiveSinceKey(locationID string) string {
+	return "since:" + locationID
+}
+
+//archiveMu serializes updates of the archived days, each holding every location
+var archiveMu sync.Mutex
+
+//archivedDay returns what was booked on day by location id
+func archivedDay(day string) (map[string]ArchivedDay, error) {
+	locs := make(map[string]ArchivedDay)
+	if err := store.GetJSON(kv, store.Archive, day, &locs); err != nil && err != store.ErrNotFound {
+		return nil, err
+	}
+	return locs, nil
+}
+
+//putArchivedDay replaces what was booked on day, expiring archiveDays after it. Days
+//older than that aren't kept.
+func putArchivedDay(day string, locs map[string]ArchivedDay) error {
+	on, err := time.ParseInLocation(dayLayout, day, tz)
+	if err != nil {
+		return err
+	}
+	ttl := time.Until(on.AddDate(0, 0, archiveDays))
+	if ttl <= 0 {
+		return nil
+	}
+	data, err := json.Marshal(locs)
+	if err != nil {
+		return err
+	}
+	//Put doesn't expire keys, the day is stored again with its ttl
+	if err := kv.Delete(store.Archive, day); err != nil {
+		return err
+	}
+	_, err = kv.PutIfAbsent(store.Archive, day, data, ttl)
+	return err
+}
+
+//archiveEvents stores the trucks booked at loc on day, replacing what was archived
+//before so the last fetch of a day wins. The store is only written when that changes.
+func archiveEvents(loc seattlefoodtruck.Location, day string, events []seattlefoodtruck.Event) {
+	a := ArchivedDay{LocationID: loc.ID, LocationName: loc.Name, Day: day}
+	for _, e := range events {
+		st, _ := time.Parse(time.RFC3339, e.StartTime)
+		et, _ := time.Parse(time.RFC3339, e.EndTime)
+		for _, b := range e.Bookings {
+			a.Trucks = append(a.Trucks, ArchivedTruck{ID: b.Truck.ID, Name: b.Truck.Name,
+				FoodCategories: b.Truck.FoodCategories, Start: st, End: et})
+		}
+	}
+
+	archiveMu.Lock()
+	defer archiveMu.Unlock()
+
+	locs, err := archivedDay(day)
+	if err != nil {
+		logger.Warnw("Error reading archived day", zap.Error(err))
+		return
+	}
+	if archived, ok := locs[loc.ID]; ok && sameArchivedDay(archived, a) {
+		return
+	}
+	locs[loc.ID] = a
+	if err := putArchivedDay(day, locs); err != nil {
+		logger.Warnw("Error archiving events", zap.Error(err))
+		return
+	}
+	if _, err := kv.PutIfAbsent(store.Archive, archiveSinceKey(loc.ID), []byte(day), 0); err != nil {
+		logger.Warnw("Error archiving first day", zap.Error(err))
+	}
+}
+
+//sameArchivedDay reports whether a and b are archived alike, compared as stored since
+//times read back aren't in the same location
+func sameArchivedDay(a, b ArchivedDay) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	jb, err := json.Marshal(b)
+	return err == nil && bytes.Equal(ja, jb)
+}
+
+//archivedSince returns the archived days on or after since, which is no earlier than
+//archiveDays ago
+func archivedSince(since time.Time) ([]ArchivedDay, error) {
+	now := time.Now().In(tz)
+	if oldest := now.AddDate(0, 0, -archiveDays); since.Before(oldest) {
+		since = oldest
+	}
+	//tomorrow is archived when its schedule is posted
+	last := now.AddDate(0, 0, 1).Format(dayLayout)
+	var days []ArchivedDay
+	for on := since.In(tz); on.Format(dayLayout) <= last; on = on.AddDate(0, 0, 1) {
+		locs, err := archivedDay(on.Format(dayLayout))
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range locs {
+			days = append(days, a)
+		}
+	}
+	return days, nil
+}
+
+//newTrucksAt returns the ids of the trucks of events that weren't booked at the location
+//in the newTruckMonths before day. Nothing is new until the archive of the location
+//reaches back that far, so a fresh archive doesn't badge every truck.
+func newTrucksAt(locationID, day string, events []seattlefoodtruck.Event) map[string]bool {
+	if newTruckMonths <= 0 || len(events) == 0 {
+		return nil
+	}
+	on, err := time.ParseInLocation(dayLayout, day, tz)
+	if err != nil {
+		return nil
+	}
+	from := on.AddDate(0, -newTruckMonths, 0)
+	first, err := kv.Get(store.Archive, archiveSinceKey(locationID))
+	if err != nil {
+		if err != store.ErrNotFound {
+			logger.Warnw("Error reading archive", zap.Error(err))
+		}
+		return nil
+	}
+	if string(first) > from.Format(dayLayout) {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for d := from; d.Before(on); d = d.AddDate(0, 0, 1) {
+		locs, err := archivedDay(d.Format(dayLayout))
+		if err != nil {
+			logger.Warnw("Error reading archived day", zap.Error(err))
+			return nil
+		}
+		for _, t := range locs[locationID].Trucks {
+			seen[t.ID] = true
+		}
+	}
+	fresh := make(map[string]bool)
+	for _, e := range events {
+		for _, b := range e.Bookings {
+			if !seen[b.Truck.ID] {
+				fresh[b.Truck.ID] = true
+			}
+		}
+	}
+	return fresh
+}
+
+//migrateArchive moves the days archived a key per location, before days were keyed by
+//date, into the days they belong to
+func migrateArchive() error {
+	values, err := kv.List(store.Archive)
+	if err != nil {
+		return err
+	}
+	archiveMu.Lock()
+	defer archiveMu.Unlock()
+
+	byDay := make(map[string][]ArchivedDay)
+	legacy := make(map[string][]string)
+	for key, data := range values {
+		//legacy keys are day:location id
+		if len(key) <= len(dayLayout) || key[len(dayLayout)] != ':' {
+			continue
+		}
+		var a ArchivedDay
+		if err := json.Unmarshal(data, &a); err != nil {
+			return err
+		}
+		day := key[:len(dayLayout)]
+		byDay[day] = append(byDay[day], a)
+		legacy[day] = append(legacy[day], key)
+		first, err := kv.Get(store.Archive, archiveSinceKey(a.LocationID))
+		if err == store.ErrNotFound || (err == nil && string(first) > day) {
+			err = kv.Put(store.Archive, archiveSinceKey(a.LocationID), []byte(day))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for day, archived := range byDay {
+		locs, err := archivedDay(day)
+		if err != nil {
+			return err
+		}
+		for _, a := range archived {
+			//what was archived since wins
+			if _, ok := locs[a.LocationID]; !ok {
+				locs[a.LocationID] = a
+			}
+		}
+		if err := putArchivedDay(day, locs); err != nil {
+			return err
+		}
+		for _, key := range legacy[day] {
+			if err := kv.Delete(store.Archive, key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//truckCount is how often a truck was booked
+type truckCount struct {
+	Truck ArchivedTruck
+	Count int
+}
+
+//countTrucks returns the trucks of days ordered by bookings, most booked first
+func countTrucks(days []ArchivedDay) []truckCount {
+	counts := make(map[string]*truckCount)
+	for _, a := range days {
+		for _, t := range a.Trucks {
+			if c, ok := counts[t.ID]; ok {
+				c.Count++
+				continue
+			}
+			counts[t.ID] = &truckCount{Truck: t, Count: 1}
+		}
+	}
+	result := make([]truckCount, 0, len(counts))
+	for _, c := range counts {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Truck.Name < result[j].Truck.Name
+	})
+	return result
+}
+
+//postStats posts this month's booking stats into channel
+func postStats(channel string) {
+	locale := localeFor(channel)
+	now := time.Now().In(tz)
+	since := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, tz)
+	days, err := archivedSince(since)
+	if err != nil {
+		logger.Errorw("Error reading archive", zap.Error(err))
+		slackAPI().PostMessage(channel, slack.MsgOptionText(i18n.T(locale, i18n.ArchiveReadFailed), false))
+		return
+	}
+	if len(days) == 0 {
+		slackAPI().PostMessage(channel, slack.MsgOptionText(i18n.T(locale, i18n.StatsNone, now.Format("January")), false))
+		return
+	}
+
+	var top strings.Builder
+	for i, c := range countTrucks(days) {
+		if i == topTrucks {
+			break
+		}
+		top.WriteString(i18n.T(locale, i18n.StatsTruck, i+1, fmt.Sprintf(truckURL, c.Truck.ID), c.Truck.Name, c.Count) + "\n")
+	}
+
+	type average struct {
+		name         string
+		days, trucks int
+	}
+	perLocation := make(map[string]*average)
+	perWeekday := make(map[time.Weekday]int)
+	for _, a := range days {
+		avg, ok := perLocation[a.LocationID]
+		if !ok {
+			avg = &average{name: a.LocationName}
+			perLocation[a.LocationID] = avg
+		}
+		avg.days++
+		avg.trucks += len(a.Trucks)
+		if t, err := time.ParseInLocation(dayLayout, a.Day, tz); err == nil {
+			perWeekday[t.Weekday()] += len(a.Trucks)
+		}
+	}
+	var locs []string
+	for _, avg := range perLocation {
+		locs = append(locs, i18n.T(locale, i18n.StatsPerLocation, avg.name, float64(avg.trucks)/float64(avg.days)))
+	}
+	sort.Strings(locs)
+	busiest := time.Sunday
+	for wd, n := range perWeekday {
+		if n > perWeekday[busiest] || (n == perWeekday[busiest] && wd < busiest) {
+			busiest = wd
+		}
+	}
+
+	msg := slack.NewBlockMessage(
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", i18n.T(locale, i18n.StatsTitle, now.Format("January 2006")), false, false), nil, nil),
+		slack.NewDividerBlock(),
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", i18n.T(locale, i18n.StatsMostFrequent)+"\n"+top.String(), false, false), nil, nil),
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", i18n.T(locale, i18n.StatsAverage)+"\n"+strings.Join(locs, "\n"), false, false), nil, nil),
+		slack.NewContextBlock("", slack.NewTextBlockObject("mrkdwn", i18n.T(locale, i18n.StatsBusiest, busiest, len(days)), false, false)),
+	)
+	msg.Text = i18n.T(locale, i18n.StatsText, now.Format("January 2006"), busiest)
+	if _, _, err := slackAPI().PostMessage(channel, slack.MsgOptionText(msg.Text, false), slack.MsgOptionBlocks(msg.Blocks.BlockSet...)); err != nil {
+		logger.Errorw("Error posting stats", zap.Error(err))
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"go.uber.org/zap"
+)
+
+//withArchive points the archive at an empty memory store and returns a func restoring it
+func withArchive() func() {
+	oldKV, oldLogger, oldDays, oldMonths := kv, logger, archiveDays, newTruckMonths
+	kv, logger, archiveDays, newTruckMonths = store.NewMemoryStore(), zap.NewNop().Sugar(), defaultArchiveDays, defaultNewTruckMonths
+	return func() {
+		kv, logger, archiveDays, newTruckMonths = oldKV, oldLogger, oldDays, oldMonths
+	}
+}
+
+//booked returns events booking trucks
+func booked(trucks ...string) []seattlefoodtruck.Event {
+	e := seattlefoodtruck.Event{StartTime: "2020-06-01T11:00:00-07:00", EndTime: "2020-06-01T14:00:00-07:00"}
+	for _, id := range trucks {
+		e.Bookings = append(e.Bookings, seattlefoodtruck.Booking{Truck: seattlefoodtruck.BookingTruck{ID: id, Name: id}})
+	}
+	return []seattlefoodtruck.Event{e}
+}
+
+//daysAgo returns the day n days before today
+func daysAgo(n int) string {
+	return time.Now().In(tz).AddDate(0, 0, -n).Format(dayLayout)
+}
+
+func TestArchivedSince(t *testing.T) {
+	defer withArchive()()
+	westlake, pike := seattlefoodtruck.Location{ID: "69", Name: "Westlake"}, seattlefoodtruck.Location{ID: "123", Name: "Pike"}
+	archiveEvents(westlake, daysAgo(3), booked("marination"))
+	archiveEvents(pike, daysAgo(3), booked("skillet"))
+	archiveEvents(westlake, daysAgo(1), booked("skillet"))
+	//the last fetch of a day wins
+	archiveEvents(westlake, daysAgo(1), booked("bread-and-circuses"))
+	archiveEvents(westlake, daysAgo(archiveDays+1), booked("old"))
+
+	days, err := archivedSince(time.Now().AddDate(-2, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range days {
+		for _, truck := range d.Trucks {
+			got = append(got, d.Day+" "+d.LocationID+" "+truck.ID)
+		}
+	}
+	sort.Strings(got)
+	want := []string{daysAgo(3) + " 123 skillet", daysAgo(3) + " 69 marination", daysAgo(1) + " 69 bread-and-circuses"}
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("archivedSince = %q, want %q", got, want)
+	}
+}
+
+func TestNewTrucksAt(t *testing.T) {
+	defer withArchive()()
+	westlake := seattlefoodtruck.Location{ID: "69", Name: "Westlake"}
+	today := daysAgo(0)
+	if fresh := newTrucksAt(westlake.ID, today, booked("marination")); fresh != nil {
+		t.Errorf("new trucks without an archive = %v, want none", fresh)
+	}
+
+	archiveEvents(westlake, daysAgo(250), booked("skillet"))
+	archiveEvents(westlake, daysAgo(20), booked("marination"))
+	fresh := newTrucksAt(westlake.ID, today, booked("marination", "skillet"))
+	if want := map[string]bool{"skillet": true}; !reflect.DeepEqual(fresh, want) {
+		t.Errorf("new trucks = %v, want %v", fresh, want)
+	}
+}
+
+func TestMigrateArchive(t *testing.T) {
+	defer withArchive()()
+	day := daysAgo(2)
+	for _, a := range []ArchivedDay{
+		{LocationID: "69", LocationName: "Westlake", Day: day, Trucks: []ArchivedTruck{{ID: "marination"}}},
+		{LocationID: "123", LocationName: "Pike", Day: day, Trucks: []ArchivedTruck{{ID: "skillet"}}},
+	} {
+		if err := store.PutJSON(kv, store.Archive, a.Day+":"+a.LocationID, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := migrateArchive(); err != nil {
+		t.Fatal(err)
+	}
+
+	locs, err := archivedDay(day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locs) != 2 || locs["69"].Trucks[0].ID != "marination" || locs["123"].Trucks[0].ID != "skillet" {
+		t.Errorf("migrated day = %+v, want both locations", locs)
+	}
+	first, err := kv.Get(store.Archive, archiveSinceKey("69"))
+	if err != nil || string(first) != day {
+		t.Errorf("first day at 69 = %s, %v, want %s", first, err, day)
+	}
+	if _, err := kv.Get(store.Archive, day+":69"); err != store.ErrNotFound {
+		t.Errorf("legacy key after migrating: %v, want it gone", err)
+	}
+}
+
+//countingStore counts the writes to a store
+type countingStore struct {
+	store.Store
+	writes int
+}
+
+func (c *countingStore) Put(bucket, key string, value []byte) error {
+	c.writes++
+	return c.Store.Put(bucket, key, value)
+}
+
+func (c *countingStore) PutIfAbsent(bucket, key string, value []byte, ttl time.Duration) (bool, error) {
+	c.writes++
+	return c.Store.PutIfAbsent(bucket, key, value, ttl)
+}
+
+func TestArchiveEventsWritesChanges(t *testing.T) {
+	defer withArchive()()
+	counting := &countingStore{Store: kv}
+	kv = counting
+	westlake := seattlefoodtruck.Location{ID: "69", Name: "Westlake"}
+
+	archiveEvents(westlake, daysAgo(0), booked("marination"))
+	if counting.writes == 0 {
+		t.Fatal("the first fetch of a day isn't archived")
+	}
+	counting.writes = 0
+	archiveEvents(westlake, daysAgo(0), booked("marination"))
+	if counting.writes != 0 {
+		t.Errorf("an unchanged day was written %v time(s)", counting.writes)
+	}
+	archiveEvents(westlake, daysAgo(0), booked("marination", "skillet"))
+	if counting.writes == 0 {
+		t.Error("a changed day isn't archived")
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/slackbot"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"go.uber.org/zap"
+)
+
+//atomDays is how many days back the atom feed goes
+const atomDays = 14
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Content atomContent `xml:"content"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Author  string      `xml:"author>name"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+//postedSchedule is the latest schedule posted for a location on a day in any channel
+type postedSchedule struct {
+	LocationID string
+	Post       slackbot.PostRecord
+}
+
+//postedSince returns the schedules posted for days on or after since, newest day first.
+//They are the snapshots the scheduled posts were rendered from.
+func postedSince(since time.Time) ([]postedSchedule, error) {
+	values, err := kv.List(store.Messages)
+	if err != nil {
+		return nil, err
+	}
+	from := since.Format(dayLayout)
+	latest := make(map[string]postedSchedule)
+	for key, data := range values {
+		parts := strings.Split(key, ":")
+		if len(parts) != 3 || parts[1] == weeklyPostID || parts[2] < from {
+			continue
+		}
+		var post slackbot.PostRecord
+		if err := json.Unmarshal(data, &post); err != nil {
+			return nil, err
+		}
+		id := parts[2] + ":" + parts[1]
+		if p, ok := latest[id]; !ok || post.PostedAt.After(p.Post.PostedAt) {
+			latest[id] = postedSchedule{LocationID: parts[1], Post: post}
+		}
+	}
+
+	posted := make([]postedSchedule, 0, len(latest))
+	for _, p := range latest {
+		posted = append(posted, p)
+	}
+	sort.Slice(posted, func(i, j int) bool {
+		if posted[i].Post.Snapshot.Day != posted[j].Post.Snapshot.Day {
+			return posted[i].Post.Snapshot.Day > posted[j].Post.Snapshot.Day
+		}
+		return posted[i].LocationID < posted[j].LocationID
+	})
+	return posted, nil
+}
+
+//scheduleEntry renders a posted schedule as an atom entry
+func scheduleEntry(p postedSchedule) atomEntry {
+	name := p.LocationID
+	if v, err := apiCache.fetch("location:"+p.LocationID, func() (interface{}, error) {
+		return proxy.GetLocation(p.LocationID)
+	}); err == nil {
+		name = v.(seattlefoodtruck.Location).Name
+	}
+	title := fmt.Sprintf("Food trucks at %s", name)
+	if d, err := time.ParseInLocation(dayLayout, p.Post.Snapshot.Day, tz); err == nil {
+		title += " on " + d.Format("Mon Jan 2")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<ul>")
+	for _, s := range p.Post.Snapshot.Sorted() {
+		sb.WriteString(fmt.Sprintf("<li>%s %s–%s</li>", html.EscapeString(s.Truck),
+			s.Start.In(tz).Format(time.Kitchen), s.End.In(tz).Format(time.Kitchen)))
+	}
+	sb.WriteString("</ul>")
+
+	return atomEntry{
+		Title:   title,
+		ID:      fmt.Sprintf("urn:seafoodtruck-slack:schedule:%s:%s", p.Post.Snapshot.Day, p.LocationID),
+		Updated: p.Post.PostedAt.UTC().Format(time.RFC3339),
+		Link:    atomLink{Href: fmt.Sprintf(locationScheduleURL, p.LocationID)},
+		Content: atomContent{Type: "html", Body: sb.String()},
+	}
+}
+
+//atomHandler serves the schedules posted over the last atomDays days as an atom feed,
+//one entry per location and day
+func atomHandler(w http.ResponseWriter, r *http.Request) {
+	posted, err := postedSince(time.Now().In(tz).AddDate(0, 0, -atomDays))
+	if err != nil {
+		requestLogger(r.Context()).Errorw("Error loading posted schedules", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "error getting schedules")
+		return
+	}
+
+	feed := atomFeed{
+		Title:  "Food Trucks",
+		ID:     "urn:seafoodtruck-slack:schedules",
+		Author: "seafoodtruck-slack",
+		Link:   atomLink{Href: "https://www.seattlefoodtruck.com", Rel: "alternate"},
+	}
+	var updated time.Time
+	for _, p := range posted {
+		feed.Entries = append(feed.Entries, scheduleEntry(p))
+		if p.Post.PostedAt.After(updated) {
+			updated = p.Post.PostedAt
+		}
+	}
+	feed.Updated = updated.UTC().Format(time.RFC3339)
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, xml.Header)
+	if err := xml.NewEncoder(w).Encode(&feed); err != nil {
+		requestLogger(r.Context()).Warnw("Error writing atom feed", zap.Error(err))
+	}
+}
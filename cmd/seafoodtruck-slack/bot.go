@@ -0,0 +1,627 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+
+	s "github.com/appsbyram/pkg/http"
+	"github.com/appsbyram/pkg/logging"
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/appsbyram/seafoodtruck-slack/internal/schedule"
+	"github.com/appsbyram/seafoodtruck-slack/internal/slackbot"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/secrets"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"github.com/appsbyram/seafoodtruck-slack/version"
+
+	"go.uber.org/zap"
+)
+
+const (
+	contentTypeHeader         = "Content-Type"
+	contentTypeFormURLEncoded = "application/x-www-form-urlencoded"
+	locationScheduleURL       = render.LocationScheduleURL
+	truckURL                  = render.TruckURL
+	green                     = "#36a64f"
+	today                     = schedule.Today
+	tomorrow                  = schedule.Tomorrow
+	lookaheadDays             = slackbot.LookaheadDays
+)
+
+var (
+	addr              string
+	refreshInterval   time.Duration
+	debugUpstream     bool
+	upstreamFixtures  string
+	watchInterval     time.Duration
+	discoverInterval  time.Duration
+	shutdownTimeout   time.Duration
+	tlsCertFile       string
+	tlsKeyFile        string
+	autocertHost      string
+	autocertCacheDir  string
+	autocertHTTPAddr  string
+	rateLimit         float64
+	rateBurst         int
+	trustForwardedFor bool
+	pprofAddr         string
+	maxPayloadBytes   int64
+	apiCacheTTL       time.Duration
+	apiCache          *ttlCache
+	feedDays          int
+	grpcAddr          string
+	configFile        string
+	secretTTL         time.Duration
+	adminClientCA     string
+	token             string
+	proxy             seattlefoodtruck.FoodTruckClient
+	logger            *zap.SugaredLogger
+	logLevel          zap.AtomicLevel
+	channel           string
+	locations         string
+	schedules         map[string]Schedule
+	tz                *time.Location
+
+	notifyNoEvents    bool
+	truckOrder        string
+	weeklyPreviewSpec string
+	eveningSpec       string
+	adminChannel      string
+	retryAttempts     int
+	retryBackoff      time.Duration
+	locker            Locker
+	adminToken        string
+	adminUsers        string
+	holidays          string
+	holidayCalendar   string
+	holidayNote       bool
+	reminderMinutes   int
+	newTruckMonths    int
+	archiveDays       int
+	socialLinks       bool
+	menuItems         int
+	locale            string
+	chartURL          string
+	chat              string
+	quietHours        string
+	quietDays         string
+	pollCutoff        string
+	discoverAll       bool
+)
+
+func init() {
+	//replaced by the configured zone in bootstrap
+	tz = seattlefoodtruck.LoadLocation(seattlefoodtruck.DefaultTimeZone)
+}
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+//newLogger creates the logger, logging upstream requests when debugUpstream is set
+func newLogger() {
+	level := "info"
+	if debugUpstream {
+		level = "debug"
+	}
+	logger, logLevel = logging.NewLogger(level)
+}
+
+//bootstrap loads the configuration, resolves credentials and opens the stores every
+//command posting to Slack needs
+func bootstrap() {
+	var err error
+
+	config, err := loadConfig(configFile)
+	if err != nil {
+		logger.Fatalw("Error loading configuration", zap.Error(err))
+	}
+	if err := config.validate(); err != nil {
+		logger.Fatalw("Invalid configuration", zap.Error(err))
+	}
+	applyConfig(config)
+	secretCache = secrets.NewCache(secretTTL)
+	if err := resolveCredentials(config); err != nil {
+		logger.Fatalw("Error resolving secrets", zap.Error(err))
+	}
+	if secretTTL > 0 {
+		go rotateCredentials(config, secretTTL)
+	}
+
+	newFoodTruckClient()
+
+	if locker, err = NewLocker(config.LockRedisURL); err != nil {
+		logger.Fatalw("Error parsing lock_redis_url", zap.Error(err))
+	}
+	if err := openSchedules(config); err != nil {
+		logger.Fatalw("Error loading schedules", zap.Error(err))
+	}
+	if err := migrateArchive(); err != nil {
+		logger.Warnw("Error migrating archive", zap.Error(err))
+	}
+	if smsEnabled() {
+		if err := indexSMSAlerts(); err != nil {
+			logger.Warnw("Error indexing SMS alerts", zap.Error(err))
+		}
+	}
+}
+
+//openSchedules opens the store and loads the location groups and schedules kept in it,
+//then builds the cron runner and the publisher posting them. The food truck client
+//must exist.
+func openSchedules(config Config) error {
+	var err error
+	if kv, err = store.Open(config.StoreURL); err != nil {
+		return fmt.Errorf("store_url: %v", err)
+	}
+	setLocationGroups(withStoredGroups(currentLocationGroups()))
+	scheduleStore = NewScheduleStore(config.ScheduleStore, kv)
+	if schedules, err = loadSchedules(config.Schedules, scheduleStore); err != nil {
+		return err
+	}
+	runner = newRunner()
+	publisher = newPublisher()
+	return nil
+}
+
+//newFoodTruckClient creates the client of the Seattle Food Truck API in the configured
+//zone, answered from the fixtures under upstreamFixtures when set
+func newFoodTruckClient() {
+	ctx := logging.WithLogger(context.TODO(), logger)
+	cfg := seattlefoodtruck.NewConfiguration()
+	transport := http.DefaultTransport
+	if len(upstreamFixtures) > 0 {
+		transport = &seattlefoodtruck.FixtureTransport{Dir: upstreamFixtures, Location: tz}
+	}
+	cfg.HTTPClient = &http.Client{Transport: &upstreamMonitor{next: transport}}
+	if len(version.Version) > 0 {
+		cfg.UserAgent = fmt.Sprintf("%s/%s", cfg.UserAgent, version.Version)
+	}
+	cfg.Debug = debugUpstream
+	cfg.Location = tz
+	proxy = seattlefoodtruck.NewFoodTruckClientFromConfig(ctx, cfg)
+}
+
+//runServe answers Slack and the REST endpoints and posts the schedules until a signal
+//shuts it down
+func runServe() {
+	bootstrap()
+
+	apiCache = newTTLCache(apiCacheTTL)
+	routes := serveRoutes()
+
+	//warm neighborhood catalog and keep it fresh
+	if err := proxy.RefreshNeighborhoods(); err != nil {
+		logger.Warnw("Error loading neighborhoods", zap.Error(err))
+	}
+	if refreshInterval > 0 {
+		go refreshNeighborhoods(refreshInterval)
+	}
+
+	//post into every channel the bot is a member of
+	if discoverAll {
+		discoverChannels()
+		if discoverInterval > 0 {
+			go discover(discoverInterval)
+		}
+	}
+
+	//start cron
+	runner.Start()
+	resumePolls()
+
+	if watchInterval > 0 {
+		go watch(watchInterval)
+	}
+	if len(configFile) > 0 {
+		go watchConfig(configFile)
+	}
+
+	if len(pprofAddr) > 0 {
+		go servePprof(pprofAddr)
+	}
+	if len(grpcAddr) > 0 {
+		serveGRPC(grpcAddr)
+	}
+
+	if len(appToken) > 0 {
+		go runSocketMode()
+	}
+	if len(telegramToken) > 0 {
+		go runTelegram()
+	}
+	if len(googleCalendarID) > 0 {
+		go runCalendarSync(googleCalendarInterval)
+	}
+	go monitorOps(opsCheckInterval)
+
+	setReady(true)
+	//serve returns once a signal shut the http server down
+	serve(withMiddleware(routes))
+	setReady(false)
+	shutdown(shutdownTimeout)
+}
+
+//serveRoutes returns the routes the bot answers, documented at /openapi.json
+func serveRoutes() s.Routes {
+	schema, err := newGraphQLSchema()
+	if err != nil {
+		logger.Fatalw("Error building graphql schema", zap.Error(err))
+	}
+	home := newEventsHandler()
+	routes := s.Routes{
+		s.Route{
+			Name:        "HomeGet",
+			Method:      "GET",
+			Pattern:     "/",
+			HandlerFunc: home.ServeHTTP,
+		},
+		s.Route{
+			Name:        "HomePost",
+			Method:      "POST",
+			Pattern:     "/",
+			HandlerFunc: home.ServeHTTP,
+		},
+		s.Route{
+			Name:        "EventsGet",
+			Method:      "GET",
+			Pattern:     "/events",
+			HandlerFunc: eventsHandler,
+		},
+		s.Route{
+			Name:        "LocationsGet",
+			Method:      "GET",
+			Pattern:     "/locations",
+			HandlerFunc: locationsHandler,
+		},
+		s.Route{
+			Name:        "LocationGet",
+			Method:      "GET",
+			Pattern:     "/locations/{id}",
+			HandlerFunc: locationHandler,
+		},
+		s.Route{
+			Name:        "TrucksGet",
+			Method:      "GET",
+			Pattern:     "/trucks",
+			HandlerFunc: trucksHandler,
+		},
+		s.Route{
+			Name:        "TruckGet",
+			Method:      "GET",
+			Pattern:     "/trucks/{id}",
+			HandlerFunc: truckHandler,
+		},
+		s.Route{
+			Name:        "NeighborhoodsGet",
+			Method:      "GET",
+			Pattern:     "/neighborhoods",
+			HandlerFunc: neighborhoodsHandler,
+		},
+		s.Route{
+			Name:        "FeedGet",
+			Method:      "GET",
+			Pattern:     "/feed.json",
+			HandlerFunc: feedHandler,
+		},
+		s.Route{
+			Name:        "TriggerBookingsGet",
+			Method:      "GET",
+			Pattern:     "/triggers/bookings",
+			HandlerFunc: triggerHandler,
+		},
+		s.Route{
+			Name:        "BriefingGet",
+			Method:      "GET",
+			Pattern:     "/briefing.json",
+			HandlerFunc: briefingHandler,
+		},
+		s.Route{
+			Name:        "CalendarGet",
+			Method:      "GET",
+			Pattern:     "/calendar.ics",
+			HandlerFunc: calendarHandler,
+		},
+		s.Route{
+			Name:        "AtomGet",
+			Method:      "GET",
+			Pattern:     "/feed.atom",
+			HandlerFunc: atomHandler,
+		},
+		s.Route{
+			Name:        "ExportGet",
+			Method:      "GET",
+			Pattern:     "/export.csv",
+			HandlerFunc: exportHandler,
+		},
+		s.Route{
+			Name:        "GraphQLGet",
+			Method:      "GET",
+			Pattern:     "/graphql",
+			HandlerFunc: graphqlHandler(schema),
+		},
+		s.Route{
+			Name:        "GraphQLPost",
+			Method:      "POST",
+			Pattern:     "/graphql",
+			HandlerFunc: graphqlHandler(schema),
+		},
+		s.Route{
+			Name:        "AdminPost",
+			Method:      "POST",
+			Pattern:     "/admin/post",
+			HandlerFunc: adminPostHandler,
+		},
+		s.Route{
+			Name:        "AdminJobsGet",
+			Method:      "GET",
+			Pattern:     "/admin/jobs",
+			HandlerFunc: adminJobsHandler,
+		},
+		s.Route{
+			Name:        "AdminJobPost",
+			Method:      "POST",
+			Pattern:     "/admin/jobs/{id}/{action}",
+			HandlerFunc: adminJobHandler,
+		},
+		s.Route{
+			Name:        "AdminGroupsGet",
+			Method:      "GET",
+			Pattern:     "/admin/groups",
+			HandlerFunc: adminGroupsHandler,
+		},
+		s.Route{
+			Name:        "AdminGroupPut",
+			Method:      "PUT",
+			Pattern:     "/admin/groups/{name}",
+			HandlerFunc: adminGroupHandler,
+		},
+		s.Route{
+			Name:        "AdminGroupDelete",
+			Method:      "DELETE",
+			Pattern:     "/admin/groups/{name}",
+			HandlerFunc: adminGroupHandler,
+		},
+		s.Route{
+			Name:        "AdminSchedulesGet",
+			Method:      "GET",
+			Pattern:     "/admin/schedules",
+			HandlerFunc: adminSchedulesHandler,
+		},
+		s.Route{
+			Name:        "AdminSchedulePut",
+			Method:      "PUT",
+			Pattern:     "/admin/schedules/{channel}",
+			HandlerFunc: adminScheduleHandler,
+		},
+		s.Route{
+			Name:        "AdminScheduleDelete",
+			Method:      "DELETE",
+			Pattern:     "/admin/schedules/{channel}",
+			HandlerFunc: adminScheduleHandler,
+		},
+		s.Route{
+			Name:        "ReadyGet",
+			Method:      "GET",
+			Pattern:     "/readyz",
+			HandlerFunc: readyHandler,
+		},
+		s.Route{
+			Name:        "InteractionsPost",
+			Method:      "POST",
+			Pattern:     "/interactions",
+			HandlerFunc: interactionsHandler,
+		},
+	}
+
+	switch chat {
+	case slackWebhookChat:
+		routes = withoutSlackEvents(routes)
+	case mattermostChat:
+		routes = append(routes, s.Route{
+			Name:        "MattermostCommandPost",
+			Method:      "POST",
+			Pattern:     "/mattermost/command",
+			HandlerFunc: mattermostCommandHandler,
+		})
+	case googleChat:
+		routes = append(routes, s.Route{
+			Name:        "GoogleChatPost",
+			Method:      "POST",
+			Pattern:     "/googlechat",
+			HandlerFunc: googleChatHandler,
+		})
+	}
+
+	routes = append(routes, s.Route{
+		Name:        "OpenAPIGet",
+		Method:      "GET",
+		Pattern:     "/openapi.json",
+		HandlerFunc: openAPIHandler(routes),
+	})
+
+	return routes
+}
+
+func eventsHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	day := r.URL.Query().Get("day")
+
+	events, err := proxy.GetEvents(id, day)
+	if err != nil {
+		http.Error(w, "Error getting events", http.StatusInternalServerError)
+	}
+	p := s.NewPayload()
+	p.WriteResponse(s.ContentTypeJSON, 200, &events, w)
+}
+
+//newEventsHandler returns the handler of the Events API requests Slack posts
+func newEventsHandler() *slackbot.EventsHandler {
+	return &slackbot.EventsHandler{
+		MaxPayload:    maxPayloadBytes,
+		Verify:        verifySlackRequest,
+		FirstDelivery: firstDelivery,
+		Logger:        requestLogger,
+		EventType:     setEventType,
+		Answer:        answerEvent,
+	}
+}
+
+//answerEvent answers an event delivered over http or Socket Mode without blocking
+func answerEvent(log *zap.SugaredLogger, innerEvent slackevents.EventsAPIInnerEvent) {
+	switch ev := innerEvent.Data.(type) {
+	case *slackevents.AppMentionEvent:
+		safeGo(ev.Channel, func() { responder.Respond(log, ev) })
+	}
+}
+
+func formatDate(t time.Time) string {
+	return t.In(tz).Format(time.RFC822)
+}
+
+//responder answers the commands the bot is mentioned with
+var responder = &slackbot.Responder{
+	Handlers: map[string]slackbot.Handler{
+		commands.Jobs:          jobsCommand,
+		commands.PauseJob:      jobsCommand,
+		commands.ResumeJob:     jobsCommand,
+		commands.RescheduleJob: jobsCommand,
+		commands.PostSchedule:  withArgs(postSchedule),
+		commands.Favorites:     favoriteCommand,
+		commands.Favorite:      favoriteCommand,
+		commands.Unfavorite:    favoriteCommand,
+		commands.Poll: func(event *slackevents.AppMentionEvent, _ commands.Command) {
+			startPoll(event.Channel)
+		},
+		commands.Leaderboard: func(event *slackevents.AppMentionEvent, cmd commands.Command) {
+			postLeaderboard(event.Channel, cmd.Args)
+		},
+		commands.Stats: func(event *slackevents.AppMentionEvent, _ commands.Command) {
+			postStats(event.Channel)
+		},
+		commands.Prefs:     withArgs(prefsCommand),
+		commands.Truck:     withArgs(truckCommand),
+		commands.SMS:       withArgs(smsCommand),
+		commands.Subscribe: withArgs(subscribe),
+		commands.Help: func(event *slackevents.AppMentionEvent, _ commands.Command) {
+			showHelp(event.Channel, localeOf(event.User, event.Channel))
+		},
+		commands.Unsubscribe: func(event *slackevents.AppMentionEvent, _ commands.Command) {
+			unsubscribe(event)
+		},
+		commands.FindEvents: findEventsCommand,
+	},
+	Unknown: func(event *slackevents.AppMentionEvent, _ commands.Command) {
+		slackAPI().PostMessage(event.Channel, slack.MsgOptionText(tr(event, i18n.UnknownCommand), false))
+	},
+}
+
+//withArgs adapts a handler of the arguments of a command to a slackbot.Handler
+func withArgs(handle func(event *slackevents.AppMentionEvent, args string)) slackbot.Handler {
+	return func(event *slackevents.AppMentionEvent, cmd commands.Command) {
+		handle(event, cmd.Args)
+	}
+}
+
+//findEventsCommand posts the events at the channel's locations or the ones asked for,
+//in the poster's preferred diets and style when they set them
+func findEventsCommand(event *slackevents.AppMentionEvent, cmd commands.Command) {
+	forLocations := locationsFor(event.Channel)
+	day, at, by := commands.FindEventsArgs(cmd.Args)
+	if at != nil {
+		forLocations = expandLocations(at)
+	}
+	order, ok := render.ParseOrder(by)
+	if !ok {
+		postEphemeral(event, tr(event, i18n.KnownOrders, strings.Replace(render.KnownOrders(), ",", ", ", -1)))
+		return
+	}
+	if prefs, style := dietsOf(event.User), styleOf(event.User); len(prefs) > 0 || len(style) > 0 {
+		postPreferredEvents(event, day, forLocations, prefs, order, style)
+		return
+	}
+	publisher.Find(event.Channel, day, forLocations, order)
+}
+
+//postEvents posts the events booked on day at forLocations into channel, of CHAT.
+//Failures are returned as *slackbot.PostError so callers decide how to surface them.
+func postEvents(channel, day string, forLocations []string) error {
+	return postEventsOnce(channel, day, forLocations, nil)
+}
+
+//postEventsOnce is postEvents skipping the Slack posts whose keys are in posted and
+//adding the ones it makes, so a retry of a post that failed halfway doesn't post the
+//locations posted before the failure again
+func postEventsOnce(channel, day string, forLocations []string, posted map[string]bool) error {
+	if notify, ok := notifiers[chat]; ok {
+		return notify(channel, day, forLocations)
+	}
+	return publisher.Publish(channel, day, forLocations, false, "", posted)
+}
+
+//dayName returns how notifications name day in locale, empty unless it is today or
+//tomorrow
+func dayName(day, locale string) string {
+	if day == today || day == tomorrow {
+		return dayWord(locale, day)
+	}
+	return ""
+}
+
+//dayOf returns the date day refers to in the configured zone
+func dayOf(day string) time.Time {
+	return schedule.DayOf(day, time.Now().In(tz))
+}
+
+//showHelp lists the commands in channel, described in locale
+func showHelp(channel, locale string) {
+	title := i18n.T(locale, i18n.HelpTitle)
+	commands := strings.Join([]string{commands.Help,
+		i18n.T(locale, i18n.HelpFindEvents, commands.FindEvents),
+		i18n.T(locale, i18n.HelpSubscribe, commands.Subscribe),
+		i18n.T(locale, i18n.HelpUnsubscribe, commands.Unsubscribe),
+		i18n.T(locale, i18n.HelpFavorite, commands.Favorite+"/"+commands.Unfavorite),
+		i18n.T(locale, i18n.HelpTruck, commands.Truck),
+		i18n.T(locale, i18n.HelpFavorites, commands.Favorites),
+		i18n.T(locale, i18n.HelpSMS, commands.SMS),
+		i18n.T(locale, i18n.HelpPoll, commands.Poll),
+		i18n.T(locale, i18n.HelpStats, commands.Stats),
+		i18n.T(locale, i18n.HelpLeaderboard, commands.Leaderboard),
+		i18n.T(locale, i18n.HelpPrefsDiets, commands.Prefs),
+		i18n.T(locale, i18n.HelpPrefsCompact, commands.Prefs),
+		i18n.T(locale, i18n.HelpPrefsAccess, commands.Prefs),
+		i18n.T(locale, i18n.HelpPrefsLocale, commands.Prefs, i18n.Locales()),
+	}, " \n ") + " \n"
+	attachment := slack.Attachment{
+		Color:      green,
+		Title:      commands,
+		Footer:     "Slack Events API | " + formatDate(time.Now()),
+		FooterIcon: "https://platform.slack-edge.com/img/default_application_icon.png",
+	}
+	_, _, err := slackAPI().PostMessage(channel, slack.MsgOptionText(title, false), slack.MsgOptionAttachments(attachment))
+	if err != nil {
+		logger.Errorw("Error posting message to channel", zap.Error(err))
+	}
+}
+
+func refreshNeighborhoods(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := proxy.RefreshNeighborhoods(); err != nil {
+			logger.Warnw("Error refreshing neighborhoods", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	s "github.com/appsbyram/pkg/http"
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"go.uber.org/zap"
+)
+
+//briefingTimeLayout is the updateDate format of flash briefings
+const briefingTimeLayout = "2006-01-02T15:04:05.0Z"
+
+//BriefingItem is an item of an Alexa flash briefing feed,
+//https://developer.amazon.com/en-US/docs/alexa/flashbriefing/flash-briefing-skill-api-feed-reference.html
+type BriefingItem struct {
+	UID            string `json:"uid"`
+	UpdateDate     string `json:"updateDate"`
+	TitleText      string `json:"titleText"`
+	MainText       string `json:"mainText"`
+	RedirectionURL string `json:"redirectionUrl"`
+}
+
+//briefingItems returns an item per location of today's feed read aloud in locale, a
+//single item saying there are no trucks when none are booked
+func briefingItems(today FeedDay, locale string, updated time.Time) []BriefingItem {
+	title := i18n.T(locale, i18n.BriefingTitle)
+	var items []BriefingItem
+	index := make(map[string]int)
+	for _, e := range today.Events {
+		if len(e.Trucks) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(e.Trucks))
+		for _, t := range e.Trucks {
+			names = append(names, t.Name)
+		}
+		key := i18n.BriefingTrucks
+		if len(names) == 1 {
+			key = i18n.BriefingTruck
+		}
+		text := i18n.T(locale, key, len(names), e.Location, e.Start.Format(time.Kitchen), e.End.Format(time.Kitchen), strings.Join(names, ", "))
+		if i, ok := index[e.LocationID]; ok {
+			items[i].MainText += " " + text
+			continue
+		}
+		index[e.LocationID] = len(items)
+		items = append(items, BriefingItem{
+			UID:            fmt.Sprintf("urn:seafoodtruck-slack:briefing:%s:%s", today.Date, e.LocationID),
+			UpdateDate:     updated.UTC().Format(briefingTimeLayout),
+			TitleText:      title,
+			MainText:       text,
+			RedirectionURL: fmt.Sprintf(locationScheduleURL, e.LocationID),
+		})
+	}
+	if len(items) == 0 {
+		items = append(items, BriefingItem{
+			UID:            "urn:seafoodtruck-slack:briefing:" + today.Date,
+			UpdateDate:     updated.UTC().Format(briefingTimeLayout),
+			TitleText:      title,
+			MainText:       i18n.T(locale, i18n.BriefingNone),
+			RedirectionURL: "https://www.seattlefoodtruck.com",
+		})
+	}
+	return items
+}
+
+//briefingHandler serves today's trucks at the configured location groups, or at ?group=
+//only, as an Alexa flash briefing for smart speakers announcing lunch
+func briefingHandler(w http.ResponseWriter, r *http.Request) {
+	group := strings.ToLower(r.URL.Query().Get("group"))
+	byGroup := feedLocations(group)
+	if len(byGroup) == 0 {
+		writeError(w, http.StatusNotFound, "no location group "+group)
+		return
+	}
+	l := locale
+	if q := strings.ToLower(r.URL.Query().Get("locale")); len(q) > 0 {
+		if !i18n.IsLocale(q) {
+			writeError(w, http.StatusBadRequest, "locale must be one of "+i18n.Locales())
+			return
+		}
+		l = q
+	}
+
+	now := time.Now().In(tz)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, tz)
+	v, err := apiCache.fetch("feed:"+from.Format(dayLayout)+":1:"+group, func() (interface{}, error) {
+		return buildFeed(byGroup, from, 1)
+	})
+	if err != nil {
+		requestLogger(r.Context()).Errorw("Error building briefing", zap.Error(err))
+		writeError(w, http.StatusBadGateway, "error getting events")
+		return
+	}
+	items := briefingItems(v.([]FeedDay)[0], l, now)
+	s.NewPayload().WriteResponse(s.ContentTypeJSON, http.StatusOK, &items, w)
+}
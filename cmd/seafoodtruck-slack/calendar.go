@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const icsTimeLayout = "20060102T150405Z"
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+//foldICS folds a content line into lines of at most 75 octets as RFC 5545 requires,
+//without splitting utf-8 sequences
+func foldICS(line string) string {
+	var sb strings.Builder
+	n := 0
+	for _, r := range line {
+		size := len(string(r))
+		if n+size > 75 {
+			sb.WriteString("\r\n ")
+			n = 1
+		}
+		sb.WriteRune(r)
+		n += size
+	}
+	sb.WriteString("\r\n")
+	return sb.String()
+}
+
+//calendarICS renders the events of feed as an iCalendar. Times are written in UTC so
+//calendars show them in the viewer's zone, X-WR-TIMEZONE hints at the bot's.
+func calendarICS(feed []FeedDay, stamp time.Time) string {
+	var sb strings.Builder
+	line := func(name, value string) {
+		sb.WriteString(foldICS(name + ":" + value))
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//seafoodtruck-slack//Food Trucks//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", "Food Trucks")
+	line("X-WR-TIMEZONE", tz.String())
+	for _, day := range feed {
+		for _, e := range day.Events {
+			var trucks []string
+			for _, t := range e.Trucks {
+				truck := t.Name
+				if len(t.Categories) > 0 {
+					truck += " (" + strings.Join(t.Categories, ", ") + ")"
+				}
+				trucks = append(trucks, truck)
+			}
+			summary := fmt.Sprintf("%v food truck(s) at %s", len(e.Trucks), e.Location)
+			line("BEGIN", "VEVENT")
+			line("UID", fmt.Sprintf("%v-%s@seafoodtruck-slack", e.ID, e.LocationID))
+			line("DTSTAMP", stamp.UTC().Format(icsTimeLayout))
+			line("DTSTART", e.Start.UTC().Format(icsTimeLayout))
+			line("DTEND", e.End.UTC().Format(icsTimeLayout))
+			line("SUMMARY", icsEscaper.Replace(summary))
+			line("LOCATION", icsEscaper.Replace(e.Address))
+			line("DESCRIPTION", icsEscaper.Replace(strings.Join(trucks, "\n")))
+			line("URL", fmt.Sprintf(locationScheduleURL, e.LocationID))
+			line("END", "VEVENT")
+		}
+	}
+	line("END", "VCALENDAR")
+	return sb.String()
+}
+
+//calendarHandler serves the events of the feed as an iCalendar to subscribe to
+func calendarHandler(w http.ResponseWriter, r *http.Request) {
+	feed, ok := loadFeed(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="foodtrucks.ics"`)
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, calendarICS(feed, time.Now()))
+}
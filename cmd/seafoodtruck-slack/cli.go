@@ -0,0 +1,342 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/appsbyram/seafoodtruck-slack/version"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+)
+
+//newRootCommand returns the seafoodtruck-slack command and its subcommands
+func newRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:   "seafoodtruck-slack",
+		Short: "Posts the Seattle food trucks booked at your locations into Slack",
+		//errors are logged by the subcommands, usage only helps with bad arguments
+		SilenceUsage: true,
+		PersistentPreRun: func(*cobra.Command, []string) {
+			newLogger()
+		},
+	}
+	root.PersistentFlags().StringVar(&configFile, "config", "", "YAML configuration file, see config.example.yaml. Environment variables override its keys.")
+	root.PersistentFlags().BoolVar(&debugUpstream, "debug-upstream", false, "Log upstream Seattle Food Truck API requests and responses.")
+	root.PersistentFlags().StringVar(&upstreamFixtures, "upstream-fixtures", "", "Answer Seattle Food Truck API requests from the fixtures saved by record in this directory instead of calling upstream.")
+
+	root.AddCommand(newServeCommand(), newPostCommand(), newRenderCommand(), newPreviewCommand(), newManifestCommand(), newHealthcheckCommand(), newDoctorCommand(), newSimulateCommand(), newRecordCommand(), newValidateConfigCommand(), newLambdaCommand(), newVersionCommand())
+	return root
+}
+
+func newServeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Answer Slack and the REST endpoints and post the schedules",
+		Args:  cobra.NoArgs,
+		Run: func(*cobra.Command, []string) {
+			runServe()
+		},
+	}
+	serveFlags(cmd.Flags())
+	return cmd
+}
+
+//serveFlags registers the flags of the serve command
+func serveFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&addr, "listen-address", ":8080", "The address to listen on for HTTP requests.")
+	fs.BoolVar(&runCron, "cron", true, "Post the schedules from the in-process cron, disable when an external scheduler runs post --scheduled.")
+	fs.DurationVar(&watchInterval, "watch-interval", 0, "How often to poll today's events and announce schedule changes, 0 disables watching.")
+	fs.StringVar(&tlsCertFile, "tls-cert", "", "Certificate file to serve https with, requires tls-key.")
+	fs.StringVar(&tlsKeyFile, "tls-key", "", "Private key file of tls-cert.")
+	fs.StringVar(&autocertHost, "autocert-host", "", "Hostname to obtain a Let's Encrypt certificate for and serve https, overrides tls-cert.")
+	fs.StringVar(&autocertCacheDir, "autocert-cache", "autocert", "Directory caching certificates obtained for autocert-host.")
+	fs.StringVar(&autocertHTTPAddr, "autocert-http-address", ":80", "The address answering ACME http-01 challenges for autocert-host.")
+	fs.StringVar(&adminClientCA, "admin-client-ca", "", "CA file whose client certificates are authorized for the admin endpoints, requires https.")
+	fs.Float64Var(&rateLimit, "rate-limit", 5, "Requests per second each client ip may make to the public endpoints, 0 disables limiting.")
+	fs.IntVar(&rateBurst, "rate-burst", 20, "Requests a client ip may burst to the public endpoints above rate-limit.")
+	fs.BoolVar(&trustForwardedFor, "trust-forwarded-for", false, "Rate limit by the client ip the proxy appends to X-Forwarded-For, set when running behind a proxy.")
+	fs.StringVar(&grpcAddr, "grpc-address", "", "The address to serve the gRPC Schedule service on, e.g. :9090. Empty disables gRPC.")
+	fs.StringVar(&pprofAddr, "pprof-address", "", "The internal address to serve net/http/pprof profiles on, e.g. localhost:6060. Empty disables profiling.")
+	fs.Int64Var(&maxPayloadBytes, "max-payload-bytes", 1<<20, "Largest Slack payload accepted, bigger ones are answered with 413.")
+	fs.DurationVar(&secretTTL, "secret-ttl", time.Hour, "How long secrets fetched from secrets backends are cached before being fetched again to pick up rotations, 0 fetches them once.")
+	fs.DurationVar(&apiCacheTTL, "api-cache-ttl", 10*time.Minute, "How long the REST endpoints cache upstream responses, 0 disables caching.")
+	fs.IntVar(&feedDays, "feed-days", 5, "Days of events /feed.json returns unless asked for more or fewer.")
+	fs.DurationVar(&shutdownTimeout, "shutdown-timeout", 30*time.Second, "How long to wait for running jobs and responses on shutdown.")
+	fs.DurationVar(&discoverInterval, "discover-interval", time.Hour, "How often to look for channels the bot joined or left when DISCOVER_CHANNELS is set, 0 only discovers at startup.")
+	fs.DurationVar(&refreshInterval, "neighborhood-refresh-interval", 24*time.Hour, "How often to refresh the cached neighborhood catalog, 0 only loads it at startup.")
+}
+
+func newPostCommand() *cobra.Command {
+	var channel, day, job, out string
+	var scheduled, dryRun bool
+	cmd := &cobra.Command{
+		Use:   "post",
+		Short: "Post the events of a channel's locations once and exit",
+		Long: `Post the events of a channel's locations once and exit.
+
+With --scheduled the posts of --job are made for every schedule, or only the one of
+--channel, the way the in-process cron would. Run it from an external scheduler such
+as a Kubernetes CronJob and serve with --cron=false. The exit code is non-zero when a
+post failed.`,
+		Args: cobra.NoArgs,
+		RunE: func(*cobra.Command, []string) error {
+			if !scheduled && len(channel) == 0 {
+				return errors.New("--channel is required unless --scheduled is set")
+			}
+			if dryRun {
+				if scheduled {
+					return errors.New("--dry-run only previews the post of --channel")
+				}
+				return runRender(channel, day, nil, out)
+			}
+			oneShot = true
+			bootstrap()
+			if scheduled {
+				return runScheduled(job, channel)
+			}
+			if err := postEvents(channel, day, locationsFor(channel)); err != nil {
+				logger.Errorw("Error posting events into channel "+channel, zap.Error(err))
+				return err
+			}
+			logger.Infof("Posted %s's events into channel %s", day, channel)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&channel, "channel", "", "Channel to post into, its schedule's locations are posted.")
+	cmd.Flags().StringVar(&day, "day", today, "Day to post the events of, today or tomorrow.")
+	cmd.Flags().BoolVar(&scheduled, "scheduled", false, "Make the posts of --job for every schedule, or only the one of --channel.")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Write the Block Kit JSON of the post instead of posting it.")
+	cmd.Flags().StringVar(&out, "out", "", "File --dry-run writes to, stdout when empty.")
+	cmd.Flags().StringVar(&job, "job", postsJob, "Job to run with --scheduled: "+postsJob+", "+eveningJob+" or "+weeklyJob+".")
+	return cmd
+}
+
+func newRenderCommand() *cobra.Command {
+	var channel, day, out string
+	var ids []string
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Write the Block Kit JSON of a schedule post without posting it",
+		Long: `Write the Block Kit JSON of a schedule post without posting it.
+
+The output can be pasted into Slack's Block Kit Builder to preview layout changes.
+The locations are given with --locations, or are those of --channel's schedule.`,
+		Args: cobra.NoArgs,
+		RunE: func(*cobra.Command, []string) error {
+			if len(channel) == 0 && len(ids) == 0 {
+				return errors.New("--locations or --channel is required")
+			}
+			return runRender(channel, day, ids, out)
+		},
+	}
+	cmd.Flags().StringSliceVar(&ids, "locations", nil, "Location ids or groups to render.")
+	cmd.Flags().StringVar(&channel, "channel", "", "Channel whose schedule's locations are rendered.")
+	cmd.Flags().StringVar(&day, "day", today, "Day to render the events of, today or tomorrow.")
+	cmd.Flags().StringVar(&out, "out", "", "File to write to, stdout when empty.")
+	return cmd
+}
+
+func newPreviewCommand() *cobra.Command {
+	var channel, day string
+	var ids, diets []string
+	cmd := &cobra.Command{
+		Use:   "preview",
+		Short: "Print a day's schedule in the terminal",
+		Long: `Print a day's schedule in the terminal, with the ratings and food categories of
+every truck. The locations are given with --locations, or are those of --channel's
+schedule.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if len(channel) == 0 && len(ids) == 0 {
+				return errors.New("--locations or --channel is required")
+			}
+			return runPreview(cmd.OutOrStdout(), channel, day, ids, diets)
+		},
+	}
+	cmd.Flags().StringSliceVar(&ids, "locations", nil, "Location ids or groups to preview.")
+	cmd.Flags().StringVar(&channel, "channel", "", "Channel whose schedule's locations are previewed.")
+	cmd.Flags().StringVar(&day, "day", today, "Day to preview the events of, today or tomorrow.")
+	cmd.Flags().StringSliceVar(&diets, "diets", nil, "Only list trucks fitting these diets: "+render.KnownDiets()+".")
+	return cmd
+}
+
+func newManifestCommand() *cobra.Command {
+	var baseURL, name string
+	cmd := &cobra.Command{
+		Use:   "manifest",
+		Short: "Print the Slack app manifest matching the configuration",
+		Long: `Print the Slack app manifest matching the configuration, with the event and
+interactivity URLs of the bot served at --url and the scopes of the enabled features.
+Paste it when creating the app at https://api.slack.com/apps.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			config, err := loadConfig(configFile)
+			if err != nil {
+				return err
+			}
+			return writeManifest(cmd.OutOrStdout(), config, baseURL, name)
+		},
+	}
+	cmd.Flags().StringVar(&baseURL, "url", "", "The https address the bot is served at, e.g. https://trucks.example.com.")
+	cmd.Flags().StringVar(&name, "name", "Seafood Truck", "Name of the app and its bot user.")
+	cmd.MarkFlagRequired("url")
+	return cmd
+}
+
+func newHealthcheckCommand() *cobra.Command {
+	var listen string
+	var useTLS bool
+	var timeout time.Duration
+	cmd := &cobra.Command{
+		Use:   "healthcheck",
+		Short: "Check the local bot is ready, for container probes",
+		Long: `Get /readyz of the bot served on --listen-address and exit non-zero unless it is
+ready, e.g. HEALTHCHECK CMD ["./bot", "healthcheck"] in a Dockerfile.`,
+		Args: cobra.NoArgs,
+		RunE: func(*cobra.Command, []string) error {
+			return healthcheck(listen, useTLS, timeout)
+		},
+	}
+	cmd.Flags().StringVar(&listen, "listen-address", ":8080", "The address the bot listens on for HTTP requests.")
+	cmd.Flags().BoolVar(&useTLS, "tls", false, "Use https, set when the bot serves https.")
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Second, "How long to wait for the bot to answer.")
+	return cmd
+}
+
+func newDoctorCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the token, channels, upstream API and zone data, with hints to fix them",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runDoctor(cmd.OutOrStdout())
+		},
+	}
+}
+
+func newSimulateCommand() *cobra.Command {
+	var channel, user, ts string
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Run a mention or button click through the handlers against a fake Slack",
+		Long: `Run a synthetic app_mention or block action through the handlers the way Slack
+would deliver it, printing the Slack web api calls the bot makes instead of sending
+them. For local development, no workspace or token is needed. Clicks on messages of an
+earlier run need a persistent store_url.`,
+		Hidden: true,
+	}
+	cmd.PersistentFlags().StringVar(&channel, "channel", "CSIMULATED", "Channel the mention or click is in.")
+	cmd.PersistentFlags().StringVar(&user, "user", "USIMULATED", "User mentioning the bot or clicking.")
+
+	run := func(out io.Writer, deliver func() int) error {
+		done, err := simulate(out)
+		if err != nil {
+			return err
+		}
+		status := deliver()
+		done()
+		if status != http.StatusOK {
+			return fmt.Errorf("handler answered %v", status)
+		}
+		return nil
+	}
+	mention := &cobra.Command{
+		Use:   "mention <text>",
+		Short: "Mention the bot, e.g. simulate mention find events for today",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.OutOrStdout(), func() int {
+				return simulateMention(channel, user, strings.Join(args, " "))
+			})
+		},
+	}
+	action := &cobra.Command{
+		Use:   "action <action id> <value>",
+		Short: "Click a button, e.g. simulate action --ts 1571140800.000100 " + rsvpActionID + " <truck id>",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.OutOrStdout(), func() int {
+				return simulateAction(channel, user, ts, args[0], args[1])
+			})
+		},
+	}
+	action.Flags().StringVar(&ts, "ts", "", "Timestamp of the message the button is on.")
+	action.MarkFlagRequired("ts")
+	cmd.AddCommand(mention, action)
+	return cmd
+}
+
+func newRecordCommand() *cobra.Command {
+	var ids []string
+	var days int
+	var out string
+	cmd := &cobra.Command{
+		Use:   "record",
+		Short: "Save upstream responses for locations as fixtures",
+		Long: `Call the live Seattle Food Truck API for --locations over --days starting today and
+save every response, with the contact details of the truck owners blanked, as a JSON
+fixture under --out. The fixtures are served by --upstream-fixtures, the fake client
+and the fixture server of the seattlefoodtruck package. Record again when upstream
+changes its schema.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runRecord(cmd.OutOrStdout(), ids, days, out)
+		},
+	}
+	cmd.Flags().StringSliceVar(&ids, "locations", nil, "Location ids or groups to record.")
+	cmd.Flags().IntVar(&days, "days", 2, "Days of events to record, starting today.")
+	cmd.Flags().StringVar(&out, "out", seattlefoodtruck.DefaultFixtureDir, "Directory to save the fixtures in.")
+	cmd.MarkFlagRequired("locations")
+	return cmd
+}
+
+func newValidateConfigCommand() *cobra.Command {
+	var offline bool
+	cmd := &cobra.Command{
+		Use:   "validate-config",
+		Short: "Check the configuration and what it points at and exit",
+		Long: `Check the configuration file and environment and exit.
+
+Unless --offline is set the token, the channel and locations of every schedule and
+the specs of every job are checked too, and a table of what will run where and when
+is printed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			config, err := loadConfig(configFile)
+			if err == nil {
+				err = config.validate()
+			}
+			if err != nil {
+				return err
+			}
+			if !offline {
+				bootstrap()
+				if problems := checkDeployment(cmd.OutOrStdout()); len(problems) > 0 {
+					return errors.New(strings.Join(problems, "; "))
+				}
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Configuration is valid")
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&offline, "offline", false, "Only check the configuration itself, without calling Slack or the food truck API.")
+	return cmd
+}
+
+func newVersionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Print the version and git commit",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s (%s)\n", version.Version, version.GitCommitID)
+		},
+	}
+}
@@ -0,0 +1,442 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/internal/mqtt"
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/appsbyram/seafoodtruck-slack/internal/schedule"
+	"github.com/appsbyram/seafoodtruck-slack/internal/twilio"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/geo"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/scheduler"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/secrets"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/weather"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+//Config is the configuration file of the bot, see config.example.yaml. Every key can be
+//overridden by the environment variable of the same name in upper case, e.g. TOKEN or
+//LOCATION_IDS; location_groups and schedules take json there.
+type Config struct {
+	Token             string              `json:"token"`
+	SigningSecret     string              `json:"signing_secret"`
+	AppToken          string              `json:"app_token"`
+	Channel           string              `json:"channel"`
+	LocationIDs       []string            `json:"location_ids"`
+	LocationGroups    map[string][]string `json:"location_groups"`
+	Schedules         []Schedule          `json:"schedules"`
+	Emoji             map[string]string   `json:"emoji"`
+	Timezone          string              `json:"timezone"`
+	NotifyNoEvents    bool                `json:"notify_no_events"`
+	WeeklyPreviewSpec string              `json:"weekly_preview_spec"`
+	EveningSpec       string              `json:"evening_spec"`
+	AdminChannel      string              `json:"admin_channel"`
+	AdminToken        string              `json:"admin_token"`
+	AdminUsers        string              `json:"admin_users"`
+	OpsChannel        string              `json:"ops_channel"`
+	OpsWebhookURL     string              `json:"ops_webhook_url"`
+	OpsUpstreamErrors int                 `json:"ops_upstream_errors"`
+	OpsCheckInterval  time.Duration       `json:"ops_check_interval"`
+	Holidays          string              `json:"holidays"`
+	HolidayCalendar   string              `json:"holiday_calendar"`
+	HolidayNote       bool                `json:"holiday_note"`
+	ReminderMinutes   int                 `json:"reminder_minutes"`
+	QuietHours        string              `json:"quiet_hours"`
+	QuietDays         string              `json:"quiet_days"`
+	PollCutoff        string              `json:"poll_cutoff"`
+	DiscoverChannels  bool                `json:"discover_channels"`
+	PostRetries       int                 `json:"post_retries"`
+	PostRetryBackoff  time.Duration       `json:"post_retry_backoff"`
+	LockRedisURL      string              `json:"lock_redis_url"`
+	StoreURL          string              `json:"store_url"`
+	ScheduleStore     string              `json:"schedule_store"`
+	StaticMap         string              `json:"static_map"`
+	StaticMapKey      string              `json:"static_map_key"`
+	WeatherAPIKey     string              `json:"weather_api_key"`
+	Office            string              `json:"office"`
+	DistanceMatrixKey string              `json:"distance_matrix_key"`
+	TruckOrder        string              `json:"truck_order"`
+	NewTruckMonths    int                 `json:"new_truck_months"`
+	ArchiveDays       int                 `json:"archive_days"`
+	SocialLinks       bool                `json:"social_links"`
+	MenuItems         int                 `json:"menu_items"`
+	Locale            string              `json:"locale"`
+	ChartURL          string              `json:"chart_url"`
+	Chat              string              `json:"chat"`
+
+	MattermostWebhookURL string `json:"mattermost_webhook_url"`
+	MattermostToken      string `json:"mattermost_token"`
+	TelegramToken        string `json:"telegram_token"`
+	WebhookURLs          string `json:"webhook_urls"`
+	WebhookSecret        string `json:"webhook_secret"`
+
+	GoogleChatWebhooks map[string]string `json:"google_chat_webhooks"`
+	GoogleChatAudience string            `json:"google_chat_audience"`
+
+	TwilioAccountSID string `json:"twilio_account_sid"`
+	TwilioAuthToken  string `json:"twilio_auth_token"`
+	TwilioFrom       string `json:"twilio_from"`
+
+	GoogleCalendarID          string        `json:"google_calendar_id"`
+	GoogleCalendarCredentials string        `json:"google_calendar_credentials"`
+	GoogleCalendarInterval    time.Duration `json:"google_calendar_interval"`
+	GoogleCalendarDays        int           `json:"google_calendar_days"`
+
+	MQTTURL           string `json:"mqtt_url"`
+	MQTTScheduleTopic string `json:"mqtt_schedule_topic"`
+	MQTTChangeTopic   string `json:"mqtt_change_topic"`
+
+	SlackWebhooks map[string]string `json:"slack_webhooks"`
+
+	MatrixHomeserverURL string `json:"matrix_homeserver_url"`
+	MatrixAccessToken   string `json:"matrix_access_token"`
+}
+
+//configKeys returns the keys of Config, each bound to its environment variable
+func configKeys() []string {
+	var keys []string
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		keys = append(keys, t.Field(i).Tag.Get("json"))
+	}
+	return keys
+}
+
+//loadConfig reads the yaml file at path, if any, and applies environment overrides.
+//Unknown keys are an error so typos don't go unnoticed.
+func loadConfig(path string) (Config, error) {
+	var cfg Config
+
+	v := viper.New()
+	v.SetDefault("timezone", seattlefoodtruck.DefaultTimeZone)
+	v.SetDefault("post_retries", defaultRetryAttempts)
+	v.SetDefault("post_retry_backoff", defaultRetryBackoff)
+	v.SetDefault("new_truck_months", defaultNewTruckMonths)
+	v.SetDefault("archive_days", defaultArchiveDays)
+	v.SetDefault("chart_url", render.QuickChartURL)
+	v.SetDefault("chat", slackChat)
+	v.SetDefault("google_calendar_interval", defaultCalendarInterval)
+	v.SetDefault("google_calendar_days", defaultCalendarDays)
+	v.SetDefault("mqtt_schedule_topic", defaultMQTTScheduleTopic)
+	v.SetDefault("mqtt_change_topic", defaultMQTTChangeTopic)
+	v.SetDefault("ops_upstream_errors", defaultOpsUpstreamErrors)
+	v.SetDefault("ops_check_interval", defaultOpsCheckInterval)
+	for _, key := range configKeys() {
+		if err := v.BindEnv(key, strings.ToUpper(key)); err != nil {
+			return cfg, err
+		}
+	}
+	if len(path) > 0 {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return cfg, err
+		}
+	}
+	err := v.Unmarshal(&cfg, func(dc *mapstructure.DecoderConfig) {
+		dc.TagName = "json"
+		dc.ErrorUnused = true
+		dc.WeaklyTypedInput = true
+		dc.DecodeHook = decodeEnvValue
+	})
+	return cfg, err
+}
+
+//decodeEnvValue converts the strings environment variables are made of: comma separated
+//lists, durations, and json for maps and schedules
+func decodeEnvValue(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String {
+		return data, nil
+	}
+	raw := data.(string)
+	switch to {
+	case reflect.TypeOf([]string{}):
+		return commands.SplitIDs(raw), nil
+	case reflect.TypeOf(time.Duration(0)):
+		return time.ParseDuration(raw)
+	case reflect.TypeOf([]Schedule{}):
+		var byChannel map[string]Schedule
+		if len(raw) > 0 {
+			if err := json.Unmarshal([]byte(raw), &byChannel); err != nil {
+				return nil, err
+			}
+		}
+		var schedules []Schedule
+		for ch, sch := range byChannel {
+			sch.Channel = ch
+			schedules = append(schedules, sch)
+		}
+		sort.Slice(schedules, func(i, j int) bool {
+			return schedules[i].Channel < schedules[j].Channel
+		})
+		return schedules, nil
+	}
+	if to.Kind() == reflect.Map {
+		m := make(map[string]interface{})
+		if len(raw) > 0 {
+			if err := json.Unmarshal([]byte(raw), &m); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	}
+	return data, nil
+}
+
+//validate reports every problem of c at once
+func (c Config) validate() error {
+	var problems []string
+	check := func(err error, what string) {
+		if err != nil {
+			problems = append(problems, what+": "+err.Error())
+		}
+	}
+
+	if !isChat(c.Chat) {
+		problems = append(problems, "chat must be one of "+knownChats())
+	}
+	if c.Chat == slackChat && len(c.Token) == 0 {
+		problems = append(problems, "token is required")
+	}
+	if c.Chat == slackChat && len(c.AppToken) == 0 && len(c.SigningSecret) == 0 {
+		problems = append(problems, "signing_secret is required to receive requests from Slack without app_token")
+	}
+	if c.Chat == mattermostChat && (len(c.MattermostWebhookURL) == 0 || len(c.MattermostToken) == 0) {
+		problems = append(problems, "mattermost_webhook_url and mattermost_token are required by the mattermost chat")
+	}
+	if c.Chat == telegramChat && len(c.TelegramToken) == 0 {
+		problems = append(problems, "telegram_token is required by the telegram chat")
+	}
+	if c.Chat == matrixChat && (len(c.MatrixHomeserverURL) == 0 || len(c.MatrixAccessToken) == 0) {
+		problems = append(problems, "matrix_homeserver_url and matrix_access_token are required by the matrix chat")
+	}
+	if c.Chat == googleChat {
+		if len(c.GoogleChatAudience) == 0 {
+			problems = append(problems, "google_chat_audience is required by the googlechat chat")
+		}
+		for _, sch := range c.Schedules {
+			if _, ok := c.GoogleChatWebhooks[sch.Channel]; !ok {
+				problems = append(problems, "google_chat_webhooks has no webhook for space "+sch.Channel)
+			}
+		}
+	}
+	if c.Chat == slackWebhookChat {
+		for _, sch := range c.Schedules {
+			if _, ok := c.SlackWebhooks[sch.Channel]; !ok {
+				problems = append(problems, "slack_webhooks has no webhook for channel "+sch.Channel)
+			}
+		}
+		if c.DiscoverChannels || len(c.AppToken) > 0 {
+			problems = append(problems, "discover_channels and app_token need a bot token, not the slack-webhook chat")
+		}
+	}
+	if c.OpsUpstreamErrors < 0 {
+		problems = append(problems, "ops_upstream_errors must not be negative")
+	}
+	if len(c.OpsWebhookURL) > 0 && !strings.HasPrefix(c.OpsWebhookURL, "https://") {
+		problems = append(problems, "ops_webhook_url must be an https URL")
+	}
+	for _, u := range commands.SplitIDs(c.WebhookURLs) {
+		if parsed, err := url.Parse(u); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			problems = append(problems, "webhook_urls must be http or https URLs, not "+u)
+		}
+	}
+	if twilioSet := len(c.TwilioAccountSID) > 0; twilioSet != (len(c.TwilioAuthToken) > 0) || twilioSet != (len(c.TwilioFrom) > 0) {
+		problems = append(problems, "twilio_account_sid, twilio_auth_token and twilio_from go together")
+	} else if _, ok := twilio.NormalizeNumber(c.TwilioFrom); twilioSet && !ok {
+		problems = append(problems, "twilio_from must be a phone number such as +12065550100")
+	}
+	if len(c.GoogleCalendarID) > 0 {
+		if len(c.GoogleCalendarCredentials) == 0 {
+			problems = append(problems, "google_calendar_credentials is required to sync google_calendar_id")
+		}
+		if c.GoogleCalendarInterval < time.Minute {
+			problems = append(problems, "google_calendar_interval must be at least 1m")
+		}
+		if c.GoogleCalendarDays < 1 || c.GoogleCalendarDays > lookaheadDays {
+			problems = append(problems, fmt.Sprintf("google_calendar_days must be between 1 and %d", lookaheadDays))
+		}
+	}
+	if len(c.MQTTURL) > 0 {
+		if _, err := mqtt.New(c.MQTTURL, "", 0); err != nil {
+			problems = append(problems, "mqtt_url: "+err.Error())
+		}
+		if !mqtt.ValidTopic(c.MQTTScheduleTopic) || !mqtt.ValidTopic(c.MQTTChangeTopic) {
+			problems = append(problems, "mqtt_schedule_topic and mqtt_change_topic must be topics without + or #")
+		}
+	}
+	if len(c.AppToken) > 0 && !secrets.IsReference(c.AppToken) && !strings.HasPrefix(c.AppToken, "xapp-") {
+		problems = append(problems, "app_token must be an app-level token starting with xapp-")
+	}
+	_, err := time.LoadLocation(c.Timezone)
+	check(err, "timezone")
+	for _, spec := range []struct{ name, value string }{
+		{"weekly_preview_spec", c.WeeklyPreviewSpec},
+		{"evening_spec", c.EveningSpec},
+	} {
+		if len(spec.value) > 0 {
+			_, err := scheduler.Parse(spec.value)
+			check(err, spec.name)
+		}
+	}
+	_, err = schedule.ParseQuietHours(c.QuietHours)
+	check(err, "quiet_hours")
+	if len(c.Office) > 0 {
+		_, err := geo.ParsePoint(c.Office)
+		check(err, "office")
+	}
+	if _, ok := render.ParseOrder(c.TruckOrder); !ok {
+		problems = append(problems, "truck_order must be one of "+render.KnownOrders())
+	}
+	if len(c.PollCutoff) > 0 {
+		_, err := commands.ParseTime(c.PollCutoff, tz)
+		check(err, "poll_cutoff")
+	}
+	if !render.IsStaticMapProvider(c.StaticMap) {
+		problems = append(problems, fmt.Sprintf("static_map must be %s, %s or empty", render.GoogleStaticMaps, render.OSMStaticMaps))
+	}
+	if c.StaticMap == render.GoogleStaticMaps && len(c.StaticMapKey) == 0 {
+		problems = append(problems, "static_map_key is required by the google static map")
+	}
+	if c.ReminderMinutes < 0 {
+		problems = append(problems, "reminder_minutes must not be negative")
+	}
+	if c.MenuItems < 0 {
+		problems = append(problems, "menu_items must not be negative")
+	}
+	if !i18n.IsLocale(c.Locale) {
+		problems = append(problems, "locale must be one of "+i18n.Locales())
+	}
+	if len(c.ChartURL) > 0 {
+		if u, err := url.Parse(c.ChartURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			problems = append(problems, "chart_url must be an http or https URL")
+		}
+	}
+	if c.NewTruckMonths < 0 {
+		problems = append(problems, "new_truck_months must not be negative")
+	}
+	if c.ArchiveDays <= c.NewTruckMonths*31 {
+		problems = append(problems, "archive_days must be positive and reach back further than new_truck_months")
+	}
+	for group, ids := range c.LocationGroups {
+		if len(ids) == 0 {
+			problems = append(problems, "location group "+group+" has no locations")
+		}
+	}
+	for i, sch := range c.Schedules {
+		name := sch.Channel
+		if len(name) == 0 {
+			name = fmt.Sprintf("#%d", i+1)
+			problems = append(problems, "schedule "+name+" has no channel")
+		}
+		if len(sch.Locations) == 0 {
+			problems = append(problems, "schedule "+name+" has no locations")
+		}
+		for _, spec := range []string{sch.Spec, sch.WeeklySpec, sch.EveningSpec} {
+			if len(spec) > 0 {
+				_, err := scheduler.Parse(spec)
+				check(err, "schedule "+name)
+			}
+		}
+		_, err := schedule.ParseQuietHours(sch.QuietHours)
+		check(err, "schedule "+name+" quiet_hours")
+		if len(sch.Office) > 0 {
+			_, err := geo.ParsePoint(sch.Office)
+			check(err, "schedule "+name+" office")
+		}
+		if _, ok := render.ParseOrder(sch.Order); !ok {
+			problems = append(problems, "schedule "+name+" order must be one of "+render.KnownOrders())
+		}
+		if !i18n.IsLocale(sch.Locale) {
+			problems = append(problems, "schedule "+name+" locale must be one of "+i18n.Locales())
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+//applyConfig sets the settings of c
+func applyConfig(c Config) {
+	channel = c.Channel
+	locations = strings.Join(c.LocationIDs, ",")
+	tz = seattlefoodtruck.LoadLocation(c.Timezone)
+	notifyNoEvents = c.NotifyNoEvents
+	weeklyPreviewSpec = c.WeeklyPreviewSpec
+	eveningSpec = c.EveningSpec
+	adminChannel = c.AdminChannel
+	adminToken = c.AdminToken
+	adminUsers = c.AdminUsers
+	opsChannel = c.OpsChannel
+	opsWebhookURL = c.OpsWebhookURL
+	opsUpstreamErrors = c.OpsUpstreamErrors
+	if opsCheckInterval = c.OpsCheckInterval; opsCheckInterval <= 0 {
+		opsCheckInterval = defaultOpsCheckInterval
+	}
+	holidays = c.Holidays
+	holidayCalendar = c.HolidayCalendar
+	holidayNote = c.HolidayNote
+	reminderMinutes = c.ReminderMinutes
+	newTruckMonths = c.NewTruckMonths
+	archiveDays = c.ArchiveDays
+	socialLinks = c.SocialLinks
+	menuItems = c.MenuItems
+	locale = c.Locale
+	chartURL = c.ChartURL
+	chat = c.Chat
+	mattermostWebhookURL = c.MattermostWebhookURL
+	mattermostToken = c.MattermostToken
+	telegramToken = c.TelegramToken
+	webhookURLs = c.WebhookURLs
+	webhookSecret = c.WebhookSecret
+	googleChatWebhooks = c.GoogleChatWebhooks
+	googleChatVerifier.Audience = c.GoogleChatAudience
+	twilioAccountSID = c.TwilioAccountSID
+	twilioAuthToken = c.TwilioAuthToken
+	twilioFrom = c.TwilioFrom
+	googleCalendarID = c.GoogleCalendarID
+	googleCalendarCredentials = c.GoogleCalendarCredentials
+	googleCalendarInterval = c.GoogleCalendarInterval
+	googleCalendarDays = c.GoogleCalendarDays
+	mqttURL = c.MQTTURL
+	mqttScheduleTopic = c.MQTTScheduleTopic
+	mqttChangeTopic = c.MQTTChangeTopic
+	slackWebhooks = c.SlackWebhooks
+	matrixHomeserverURL = c.MatrixHomeserverURL
+	matrixAccessToken = c.MatrixAccessToken
+	quietHours = c.QuietHours
+	quietDays = c.QuietDays
+	pollCutoff = c.PollCutoff
+	discoverAll = c.DiscoverChannels
+	if retryAttempts = c.PostRetries; retryAttempts < 1 {
+		retryAttempts = defaultRetryAttempts
+	}
+	if retryBackoff = c.PostRetryBackoff; retryBackoff <= 0 {
+		retryBackoff = defaultRetryBackoff
+	}
+	setLocationGroups(loadLocationGroups(c.LocationGroups))
+	render.SetEmoji(c.Emoji)
+	render.SetStaticMap(c.StaticMap, c.StaticMapKey)
+	forecaster = nil
+	if len(c.WeatherAPIKey) > 0 {
+		forecaster = weather.NewOpenWeatherMap(c.WeatherAPIKey, nil)
+	}
+	office = c.Office
+	truckOrder, _ = render.ParseOrder(c.TruckOrder)
+	router = nil
+	if len(c.DistanceMatrixKey) > 0 {
+		router = geo.NewDistanceMatrix(c.DistanceMatrixKey, nil)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/slackbot"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/secrets"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+var (
+	credentialsMu sync.RWMutex
+	slackClient   *slack.Client
+	signingSecret string
+	//appToken is the app-level token Socket Mode connects with, empty to receive
+	//events over http
+	appToken    string
+	secretCache *secrets.Cache
+	//unsignedRequests lets the requests of the simulate command through unverified
+	unsignedRequests bool
+)
+
+//slackAPI returns the client of the current bot token, replaced when the token rotates
+func slackAPI() *slack.Client {
+	credentialsMu.RLock()
+	defer credentialsMu.RUnlock()
+	return slackClient
+}
+
+func currentSigningSecret() string {
+	credentialsMu.RLock()
+	defer credentialsMu.RUnlock()
+	return signingSecret
+}
+
+//resolveCredentials resolves token and signing_secret of c, either may reference a
+//secrets backend such as secretsmanager://, ssm:// or vault://
+func resolveCredentials(c Config) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resolvedToken, err := secretCache.Resolve(ctx, c.Token)
+	if err != nil {
+		return errors.New("token: " + err.Error())
+	}
+	resolvedSecret, err := secretCache.Resolve(ctx, c.SigningSecret)
+	if err != nil {
+		return errors.New("signing_secret: " + err.Error())
+	}
+	resolvedAppToken, err := secretCache.Resolve(ctx, c.AppToken)
+	if err != nil {
+		return errors.New("app_token: " + err.Error())
+	}
+
+	credentialsMu.Lock()
+	defer credentialsMu.Unlock()
+	if resolvedToken != token || resolvedAppToken != appToken || slackClient == nil {
+		if slackClient != nil {
+			logger.Info("Bot token rotated")
+		}
+		token, appToken = resolvedToken, resolvedAppToken
+		var options []slack.Option
+		if len(appToken) > 0 {
+			options = append(options, slack.OptionAppLevelToken(appToken))
+		}
+		slackClient = slack.New(token, options...)
+	}
+	if resolvedSecret != signingSecret && len(signingSecret) > 0 {
+		logger.Info("Signing secret rotated")
+	}
+	signingSecret = resolvedSecret
+	return nil
+}
+
+//rotateCredentials resolves the credentials of c every interval to pick up rotated secrets
+func rotateCredentials(c Config, interval time.Duration) {
+	if !secrets.IsReference(c.Token) && !secrets.IsReference(c.SigningSecret) && !secrets.IsReference(c.AppToken) {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := resolveCredentials(c); err != nil {
+			logger.Warnw("Error refreshing secrets, keeping the current ones", zap.Error(err))
+		}
+	}
+}
+
+//verifySlackRequest checks the signature Slack puts on the requests it sends, only the
+//requests of the simulate command go unverified
+func verifySlackRequest(r *http.Request, body []byte) error {
+	credentialsMu.RLock()
+	unsigned := unsignedRequests
+	credentialsMu.RUnlock()
+	if unsigned {
+		return nil
+	}
+	return slackbot.Verify(r.Header, currentSigningSecret(), body)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"go.uber.org/zap"
+)
+
+//Slack gives up retrying an event well within an hour
+const eventDedupTTL = time.Hour
+
+//firstDelivery records eventID as processed and reports whether it was seen for the first
+//time, so events Slack redelivers while an earlier delivery is still being handled are
+//skipped. If the store fails the event is handled, a duplicate beats a dropped request.
+//Events replayed on Lambda were recorded by the invocation that answered them.
+func firstDelivery(eventID string) bool {
+	if len(eventID) == 0 || replaying {
+		return true
+	}
+	ok, err := kv.PutIfAbsent(store.Dedup, "event:"+eventID, []byte(time.Now().Format(time.RFC3339)), eventDedupTTL)
+	if err != nil {
+		logger.Warnw("Error recording event "+eventID+", handling it anyway", zap.Error(err))
+		return true
+	}
+	return ok
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/internal/schedule"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+//discovered holds the channels whose schedules came from discovery rather than config.
+//Channels that unsubscribed are kept in the opt-outs bucket so they aren't rediscovered
+//nor their configured schedule loaded, restarts included. Guarded by schedulesMu.
+var discovered = make(map[string]bool)
+
+//optOut records that channel unsubscribed, or with optedOut false that it subscribed again
+func optOut(channel string, optedOut bool) error {
+	if !optedOut {
+		return kv.Delete(store.OptOuts, channel)
+	}
+	return kv.Put(store.OptOuts, channel, []byte(time.Now().Format(time.RFC3339)))
+}
+
+//joinedChannels lists the conversations the bot is a member of
+func joinedChannels() ([]string, error) {
+	var ids []string
+	params := &slack.GetConversationsForUserParameters{
+		Types:           []string{"public_channel", "private_channel"},
+		Limit:           200,
+		ExcludeArchived: true,
+	}
+	for {
+		channels, cursor, err := slackAPI().GetConversationsForUser(params)
+		if err != nil {
+			return nil, err
+		}
+		for _, ch := range channels {
+			ids = append(ids, ch.ID)
+		}
+		if len(cursor) == 0 {
+			return ids, nil
+		}
+		params.Cursor = cursor
+	}
+}
+
+//discoverChannels gives every joined channel without a schedule the default one and drops
+//discovered schedules of channels the bot has left. Configured and stored schedules are
+//left alone, so they act as per-channel overrides. It reports whether schedules changed.
+func discoverChannels() bool {
+	joined, err := joinedChannels()
+	if err != nil {
+		logger.Errorw("Error listing joined channels", zap.Error(err))
+		return false
+	}
+	optedOut, err := kv.List(store.OptOuts)
+	if err != nil {
+		logger.Errorw("Error loading channel opt-outs", zap.Error(err))
+		return false
+	}
+	member := make(map[string]bool)
+	for _, ch := range joined {
+		member[ch] = true
+	}
+
+	schedulesMu.Lock()
+	defer schedulesMu.Unlock()
+
+	changed := false
+	for _, ch := range joined {
+		_, skip := optedOut[ch]
+		if _, ok := schedules[ch]; !ok && !discovered[ch] && !skip {
+			schedules[ch] = schedule.WithDefaults(ch, Schedule{Locations: commands.SplitIDs(locations)})
+			discovered[ch] = true
+			changed = true
+			logger.Infof("Discovered channel %s", ch)
+		}
+	}
+	for ch := range discovered {
+		if !member[ch] {
+			delete(schedules, ch)
+			delete(discovered, ch)
+			changed = true
+			logger.Infof("Left channel %s, removing its schedule", ch)
+		}
+	}
+	//a channel the bot is invited back into starts over with the default schedule
+	for ch := range optedOut {
+		if !member[ch] {
+			if err := optOut(ch, false); err != nil {
+				logger.Warnw("Error dropping channel opt-out", "channel", ch, zap.Error(err))
+			}
+		}
+	}
+	return changed
+}
+
+//discover re-runs channel discovery every interval, rescheduling when channels changed
+func discover(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if discoverChannels() {
+			runner.Restart(nil)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/geo"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"go.uber.org/zap"
+)
+
+var (
+	//office is where people walk from unless their channel's schedule sets it
+	office string
+	//router finds walking times, nil unless distance_matrix_key is set
+	router geo.Router
+)
+
+//officeFor returns where the people of channel walk from and false when it isn't set
+func officeFor(channel string) (geo.Point, bool) {
+	schedulesMu.RLock()
+	at := office
+	if sch, ok := schedules[channel]; ok && len(sch.Office) > 0 {
+		at = sch.Office
+	}
+	schedulesMu.RUnlock()
+	if len(at) == 0 {
+		return geo.Point{}, false
+	}
+	p, err := geo.ParsePoint(at)
+	if err != nil {
+		logger.Warnw("Invalid office", "channel", channel, zap.Error(err))
+		return geo.Point{}, false
+	}
+	return p, true
+}
+
+//distanceFrom returns how far loc is from the office of channel, the walking time when
+//a router is configured and the straight-line distance otherwise. It is empty when
+//either place is unknown.
+func distanceFrom(channel string, loc seattlefoodtruck.Location) string {
+	from, ok := officeFor(channel)
+	if !ok || (loc.Latitude == 0 && loc.Longitude == 0) {
+		return ""
+	}
+	to := geo.Point{Lat: loc.Latitude, Lon: loc.Longitude}
+	if router != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		walk, err := router.Walk(ctx, from, to)
+		if err == nil {
+			minutes := int(math.Ceil(walk.Duration.Minutes()))
+			return i18n.T(localeFor(channel), i18n.WalkDistance, minutes, geo.Miles(walk.Meters))
+		}
+		logger.Warnw("Error finding walking route", "location", loc.ID, zap.Error(err))
+	}
+	return i18n.T(localeFor(channel), i18n.Distance, geo.Miles(geo.Distance(from, to)))
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/secrets"
+	"github.com/slack-go/slack"
+)
+
+//diagnosis is the outcome of one doctor check with what to do when it failed
+type diagnosis struct {
+	check string
+	err   error
+	hint  string
+}
+
+//runDoctor checks the bot can run where it is: the configuration, the token and its
+//scopes, the channels of the schedules, the upstream API and the zone data. It writes
+//pass or fail with a hint for every check to w and fails when any check failed.
+func runDoctor(w io.Writer) error {
+	var results []diagnosis
+	report := func(check string, err error, hint string) bool {
+		results = append(results, diagnosis{check, err, hint})
+		return err == nil
+	}
+	defer func() {
+		for _, d := range results {
+			if d.err == nil {
+				fmt.Fprintf(w, "PASS  %s\n", d.check)
+				continue
+			}
+			fmt.Fprintf(w, "FAIL  %s: %v\n      %s\n", d.check, d.err, d.hint)
+		}
+	}()
+
+	config, err := loadConfig(configFile)
+	if err == nil {
+		err = config.validate()
+	}
+	if !report("configuration", err, "Fix the configuration file or environment, see config.example.yaml") {
+		return errors.New("doctor found problems")
+	}
+	applyConfig(config)
+
+	_, err = time.LoadLocation(config.Timezone)
+	report("zone data for "+config.Timezone, err,
+		"Install the zone database, e.g. apk add tzdata on Alpine, or point ZONEINFO at a zoneinfo.zip; without it times are shown in UTC")
+
+	secretCache = secrets.NewCache(0)
+	err = resolveCredentials(config)
+	if report("secrets", err, "Check the secret references of token and signing_secret and the credentials of their backends") {
+		scopes, err := tokenScopes(token)
+		if report("bot token", err, "Check slack.com is reachable and token is the bot token (xoxb-) of an app installed to the workspace") {
+			var missing []string
+			for _, scope := range newAppManifest(config, "", "").OAuthConfig.Scopes.Bot {
+				if !contains(scopes, scope) {
+					missing = append(missing, scope)
+				}
+			}
+			if len(missing) > 0 {
+				err = fmt.Errorf("missing %s", strings.Join(missing, ", "))
+			}
+			report("bot token scopes", err, "Add the scopes under OAuth & Permissions and reinstall the app, or recreate it from the manifest command's output")
+		}
+	}
+
+	newFoodTruckClient()
+	_, err = proxy.GetNeighborhoods()
+	report("seattlefoodtruck.com API", err, "Check outbound https to www.seattlefoodtruck.com is allowed, through HTTPS_PROXY if needed")
+
+	err = openSchedules(config)
+	if report("store", err, "Check store_url and schedule_store point at a reachable store") && len(token) > 0 {
+		schedulesMu.RLock()
+		channels := make([]string, 0, len(schedules))
+		for ch := range schedules {
+			channels = append(channels, ch)
+		}
+		schedulesMu.RUnlock()
+		sort.Strings(channels)
+		for _, ch := range channels {
+			info, err := slackAPI().GetConversationInfo(&slack.GetConversationInfoInput{ChannelID: ch})
+			if err == nil && !info.IsMember {
+				err = errors.New("the bot is not a member")
+			}
+			report("channel "+ch, err, "Invite the bot with /invite @bot in the channel, or fix the channel id of its schedule")
+		}
+	}
+
+	for _, d := range results {
+		if d.err != nil {
+			return errors.New("doctor found problems")
+		}
+	}
+	return nil
+}
+
+//tokenScopes returns the scopes granted to token, which Slack only reports in the
+//X-OAuth-Scopes header of its responses
+func tokenScopes(token string) ([]string, error) {
+	req, err := http.NewRequest(http.MethodPost, slack.APIURL+"auth.test", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var auth slack.SlackResponse
+	if err := json.NewDecoder(resp.Body).Decode(&auth); err != nil {
+		return nil, err
+	}
+	if !auth.Ok {
+		return nil, errors.New(auth.Error)
+	}
+	var scopes []string
+	for _, scope := range strings.Split(resp.Header.Get("X-OAuth-Scopes"), ",") {
+		if scope = strings.TrimSpace(scope); len(scope) > 0 {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
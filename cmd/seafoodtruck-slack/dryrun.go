@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/slack-go/slack"
+)
+
+//renderLocations loads the configuration and returns the locations of ids, or of
+//channel's schedule when ids is empty. Rendering doesn't need the Slack token.
+func renderLocations(channel string, ids []string) ([]string, error) {
+	config, err := loadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	applyConfig(config)
+	newFoodTruckClient()
+	if err := openSchedules(config); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return locationsFor(channel), nil
+	}
+	return expandLocations(ids), nil
+}
+
+//runRender writes the Block Kit JSON of day's post at ids, or at the locations of
+//channel when ids is empty
+func runRender(channel, day string, ids []string, out string) error {
+	forLocations, err := renderLocations(channel, ids)
+	if err != nil {
+		return err
+	}
+	msgs, err := renderSchedule(channel, day, forLocations)
+	if err != nil {
+		return err
+	}
+	return writeBlockKit(out, msgs)
+}
+
+//runPreview writes day's schedule at ids, or at the locations of channel when ids is
+//empty, as text to w
+func runPreview(w io.Writer, channel, day string, ids, diets []string) error {
+	for _, d := range diets {
+		if !render.IsDiet(d) {
+			return fmt.Errorf("unknown diet %s, try %s", d, render.KnownDiets())
+		}
+	}
+	forLocations, err := renderLocations(channel, ids)
+	if err != nil {
+		return err
+	}
+	for _, id := range forLocations {
+		loc, err := proxy.GetLocation(id)
+		if err != nil {
+			return err
+		}
+		events, err := proxy.GetEvents(id, day)
+		if err != nil {
+			return err
+		}
+		if err := render.Text(w, loc, events, proxy.GetTruck, tz, render.Options{Diets: diets, Order: orderFor(channel)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//blockKit is a message in the format Slack's Block Kit Builder takes
+type blockKit struct {
+	Blocks []slack.Block `json:"blocks"`
+}
+
+//renderSchedule builds the messages postEvents would post into channel for the events
+//of day at forLocations without posting or recording anything. Locations without
+//events are left out.
+func renderSchedule(channel, day string, forLocations []string) ([]slack.Message, error) {
+	var msgs []slack.Message
+	for i, id := range forLocations {
+		loc, err := proxy.GetLocation(id)
+		if err != nil {
+			return nil, err
+		}
+		events, err := proxy.GetEvents(id, day)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) == 0 {
+			logger.Infof("No events at %s, skipping", loc.Name)
+			continue
+		}
+		opts := render.Options{More: i < len(forLocations)-1, RSVP: true, Weather: weatherLine(loc, events, localeFor(channel)), Distance: distanceFrom(channel, loc), Order: orderFor(channel),
+			New: newTrucksAt(loc.ID, dayOf(day).Format(dayLayout), events), Social: socialLinks, MenuItems: menuItems, Day: dayName(day, localeFor(channel)), Style: styleFor(channel), Locale: localeFor(channel)}
+		opts.RSVP = opts.Style != render.StyleCompact
+		msgs = append(msgs, render.Split(render.Events(loc, events, proxy.GetTruck, tz, opts))...)
+	}
+	return msgs, nil
+}
+
+//writeBlockKit writes msgs as one Block Kit Builder message to path, or to stdout
+//when path is empty
+func writeBlockKit(path string, msgs []slack.Message) error {
+	var kit blockKit
+	for _, msg := range msgs {
+		kit.Blocks = append(kit.Blocks, msg.Blocks.BlockSet...)
+	}
+	out := os.Stdout
+	if len(path) > 0 {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(kit)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/csv"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+//exportDays is how many days back the export goes without ?from=
+const exportDays = 30
+
+var exportHeader = []string{"date", "location", "truck", "categories", "start", "end"}
+
+//exportHandler writes the archived bookings from ?from= through ?to=, days formatted as
+//2006-01-02, optionally at ?location= only, as csv rows
+func exportHandler(w http.ResponseWriter, r *http.Request) {
+	now := time.Now().In(tz)
+	from, to := now.AddDate(0, 0, -exportDays), now
+	var err error
+	if f := r.URL.Query().Get("from"); len(f) > 0 {
+		if from, err = time.ParseInLocation(dayLayout, f, tz); err != nil {
+			writeError(w, http.StatusBadRequest, "from must look like "+dayLayout)
+			return
+		}
+	}
+	if t := r.URL.Query().Get("to"); len(t) > 0 {
+		if to, err = time.ParseInLocation(dayLayout, t, tz); err != nil {
+			writeError(w, http.StatusBadRequest, "to must look like "+dayLayout)
+			return
+		}
+	}
+	if to.Before(from) {
+		writeError(w, http.StatusBadRequest, "to is before from")
+		return
+	}
+	location := r.URL.Query().Get("location")
+
+	days, err := archivedSince(from)
+	if err != nil {
+		requestLogger(r.Context()).Errorw("Error loading archive", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "error getting archive")
+		return
+	}
+	last := to.Format(dayLayout)
+	selected := days[:0]
+	for _, d := range days {
+		if d.Day <= last && (len(location) == 0 || d.LocationID == location) {
+			selected = append(selected, d)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		if selected[i].Day != selected[j].Day {
+			return selected[i].Day < selected[j].Day
+		}
+		return selected[i].LocationName < selected[j].LocationName
+	})
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="schedules.csv"`)
+	w.WriteHeader(http.StatusOK)
+	cw := csv.NewWriter(w)
+	cw.Write(exportHeader)
+	for _, d := range selected {
+		for _, t := range d.Trucks {
+			cw.Write([]string{d.Day, d.LocationName, t.Name, strings.Join(t.FoodCategories, "; "),
+				exportTime(t.Start), exportTime(t.End)})
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		requestLogger(r.Context()).Warnw("Error writing export", zap.Error(err))
+	}
+}
+
+//exportTime formats t as a local time of day, days archived before times were kept have none
+func exportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(tz).Format("15:04")
+}
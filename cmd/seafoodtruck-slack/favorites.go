@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"go.uber.org/zap"
+)
+
+const (
+	//alerts are deduplicated per user, truck, location and day
+	favoriteAlertTTL = 36 * time.Hour
+)
+
+//FavoriteTruck is a truck a user wants to hear about
+type FavoriteTruck struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func favoritesOf(user string) ([]FavoriteTruck, error) {
+	var favorites []FavoriteTruck
+	err := store.GetJSON(kv, store.Favorites, user, &favorites)
+	if err == store.ErrNotFound {
+		return nil, nil
+	}
+	return favorites, err
+}
+
+//lookupTruck finds a truck by its id, e.g. seattle-chicken-over-rice, or by its name
+func lookupTruck(name string) (seattlefoodtruck.Truck, error) {
+	slug := strings.Join(strings.Fields(strings.ToLower(name)), "-")
+	return proxy.GetTruck(slug)
+}
+
+//favoriteCommand handles favorite, unfavorite and favorites
+func favoriteCommand(event *slackevents.AppMentionEvent, cmd commands.Command) {
+	favorites, err := favoritesOf(event.User)
+	if err != nil {
+		logger.Errorw("Error loading favorites", zap.Error(err))
+		postEphemeral(event, tr(event, i18n.FavoritesLoadFailed))
+		return
+	}
+	if cmd.Name == commands.Favorites {
+		if len(favorites) == 0 {
+			postEphemeral(event, tr(event, i18n.FavoritesNone))
+			return
+		}
+		names := make([]string, 0, len(favorites))
+		for _, f := range favorites {
+			names = append(names, fmt.Sprintf("<%s|%s>", fmt.Sprintf(truckURL, f.ID), f.Name))
+		}
+		postEphemeral(event, tr(event, i18n.FavoritesList, strings.Join(names, ", ")))
+		return
+	}
+
+	remove := cmd.Name == commands.Unfavorite
+	name := cmd.Args
+	truck, err := lookupTruck(name)
+	if err != nil {
+		postEphemeral(event, tr(event, i18n.TruckNotFound, name))
+		return
+	}
+	kept := favorites[:0]
+	for _, f := range favorites {
+		if f.ID != truck.ID {
+			kept = append(kept, f)
+		}
+	}
+	if !remove {
+		kept = append(kept, FavoriteTruck{ID: truck.ID, Name: truck.Name})
+	}
+	if err := store.PutJSON(kv, store.Favorites, event.User, kept); err != nil {
+		logger.Errorw("Error saving favorites", zap.Error(err))
+		postEphemeral(event, tr(event, i18n.FavoritesSaveFailed))
+		return
+	}
+	if remove {
+		postEphemeral(event, tr(event, i18n.FavoriteRemoved, truck.Name))
+		return
+	}
+	postEphemeral(event, tr(event, i18n.FavoriteAdded, truck.Name))
+}
+
+//notifyFavorites DMs every user with a favorite among the trucks booked at loc today,
+//and texts those registered for SMS alerts there
+func notifyFavorites(loc seattlefoodtruck.Location, events []seattlefoodtruck.Event) {
+	if smsEnabled() {
+		textAlerts(loc, events)
+	}
+	all, err := kv.List(store.Favorites)
+	if err != nil {
+		logger.Errorw("Error listing favorites", zap.Error(err))
+		return
+	}
+	day := time.Now().In(tz).Format(dayLayout)
+	for user := range all {
+		favorites, err := favoritesOf(user)
+		if err != nil {
+			logger.Warnw("Error loading favorites of "+user, zap.Error(err))
+			continue
+		}
+		for _, e := range events {
+			for _, b := range e.Bookings {
+				if !isFavorite(favorites, b.Truck.ID) {
+					continue
+				}
+				key := fmt.Sprintf("favorite:%s:%s:%s:%s", user, b.Truck.ID, loc.ID, day)
+				if ok, err := kv.PutIfAbsent(store.Dedup, key, []byte(day), favoriteAlertTTL); err != nil || !ok {
+					continue
+				}
+				st, _ := time.Parse(time.RFC3339, e.StartTime)
+				et, _ := time.Parse(time.RFC3339, e.EndTime)
+				//favorites aren't tied to a channel, the DM is in the user's language
+				text := i18n.T(localeOf(user, ""), i18n.FavoriteAlert,
+					fmt.Sprintf(truckURL, b.Truck.ID), b.Truck.Name, fmt.Sprintf(locationScheduleURL, loc.ID), loc.Name,
+					st.In(tz).Format(time.Kitchen), et.In(tz).Format(time.Kitchen))
+				if _, _, err := slackAPI().PostMessage(user, slack.MsgOptionText(text, false)); err != nil {
+					logger.Errorw("Error sending favorite alert", zap.Error(err))
+				}
+			}
+		}
+	}
+}
+
+func isFavorite(favorites []FavoriteTruck, truckID string) bool {
+	for _, f := range favorites {
+		if f.ID == truckID {
+			return true
+		}
+	}
+	return false
+}
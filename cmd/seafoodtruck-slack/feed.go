@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	s "github.com/appsbyram/pkg/http"
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"go.uber.org/zap"
+)
+
+//maxFeedDays bounds ?days= of the feed
+const maxFeedDays = 14
+
+//FeedTruck is a truck booked for a FeedEvent
+type FeedTruck struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Categories []string `json:"categories"`
+}
+
+//FeedEvent lists the trucks at a location during an event
+type FeedEvent struct {
+	ID         int         `json:"id"`
+	Group      string      `json:"group,omitempty"`
+	LocationID string      `json:"location_id"`
+	Location   string      `json:"location"`
+	Address    string      `json:"address"`
+	Start      time.Time   `json:"start"`
+	End        time.Time   `json:"end"`
+	Trucks     []FeedTruck `json:"trucks"`
+}
+
+//FeedDay holds the events of one day of the feed, sorted by start
+type FeedDay struct {
+	Date   string      `json:"date"`
+	Events []FeedEvent `json:"events"`
+}
+
+//feedLocations returns the location ids of the configured groups keyed by group name, the
+//locations of LOCATION_IDS go by the empty name when no groups are configured
+func feedLocations(group string) map[string][]string {
+	byGroup := make(map[string][]string)
+	groups := currentLocationGroups()
+	if len(groups) == 0 {
+		byGroup[""] = commands.SplitIDs(locations)
+		return byGroup
+	}
+	for name, ids := range groups {
+		if len(group) == 0 || strings.EqualFold(name, group) {
+			byGroup[name] = ids
+		}
+	}
+	return byGroup
+}
+
+//buildFeed aggregates the events of the next days at the locations of byGroup by day
+func buildFeed(byGroup map[string][]string, from time.Time, days int) ([]FeedDay, error) {
+	to := from.AddDate(0, 0, days-1)
+	feed := make([]FeedDay, days)
+	index := make(map[string]int)
+	for i := range feed {
+		date := from.AddDate(0, 0, i).Format(dayLayout)
+		feed[i] = FeedDay{Date: date, Events: []FeedEvent{}}
+		index[date] = i
+	}
+
+	for group, ids := range byGroup {
+		for _, id := range ids {
+			loc, err := proxy.GetLocation(id)
+			if err != nil {
+				return nil, err
+			}
+			events, err := proxy.GetEventsBetween(context.TODO(), id, from, to)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range events {
+				fe, ok := feedEvent(e)
+				if !ok {
+					continue
+				}
+				i, ok := index[fe.Start.Format(dayLayout)]
+				if !ok {
+					continue
+				}
+				fe.Group, fe.LocationID, fe.Location, fe.Address = group, loc.ID, loc.Name, loc.Address
+				feed[i].Events = append(feed[i].Events, fe)
+			}
+		}
+	}
+	for _, day := range feed {
+		events := day.Events
+		sort.SliceStable(events, func(i, j int) bool {
+			if !events[i].Start.Equal(events[j].Start) {
+				return events[i].Start.Before(events[j].Start)
+			}
+			return events[i].Location < events[j].Location
+		})
+	}
+	return feed, nil
+}
+
+func feedEvent(e seattlefoodtruck.Event) (FeedEvent, bool) {
+	st, err := time.Parse(time.RFC3339, e.StartTime)
+	if err != nil {
+		return FeedEvent{}, false
+	}
+	et, err := time.Parse(time.RFC3339, e.EndTime)
+	if err != nil {
+		return FeedEvent{}, false
+	}
+	fe := FeedEvent{ID: e.ID, Start: st.In(tz), End: et.In(tz), Trucks: []FeedTruck{}}
+	for _, b := range e.Bookings {
+		fe.Trucks = append(fe.Trucks, FeedTruck{ID: b.Truck.ID, Name: b.Truck.Name, Categories: b.Truck.FoodCategories})
+	}
+	return fe, true
+}
+
+//feedHandler returns the events of the next ?days= days at the configured location groups,
+//or at ?group= only, for dashboards showing the schedule outside of Slack
+func feedHandler(w http.ResponseWriter, r *http.Request) {
+	feed, ok := loadFeed(w, r)
+	if !ok {
+		return
+	}
+	s.NewPayload().WriteResponse(s.ContentTypeJSON, http.StatusOK, &feed, w)
+}
+
+//loadFeed returns the cached feed asked for by the ?days= and ?group= of r, answering
+//the request itself when that fails
+func loadFeed(w http.ResponseWriter, r *http.Request) ([]FeedDay, bool) {
+	days := feedDays
+	if d := r.URL.Query().Get("days"); len(d) > 0 {
+		days, _ = strconv.Atoi(d)
+	}
+	if days < 1 || days > maxFeedDays {
+		writeError(w, http.StatusBadRequest, "days must be between 1 and "+strconv.Itoa(maxFeedDays))
+		return nil, false
+	}
+	group := strings.ToLower(r.URL.Query().Get("group"))
+	byGroup := feedLocations(group)
+	if len(byGroup) == 0 {
+		writeError(w, http.StatusNotFound, "no location group "+group)
+		return nil, false
+	}
+
+	now := time.Now().In(tz)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, tz)
+	key := "feed:" + from.Format(dayLayout) + ":" + strconv.Itoa(days) + ":" + group
+	v, err := apiCache.fetch(key, func() (interface{}, error) {
+		return buildFeed(byGroup, from, days)
+	})
+	if err != nil {
+		requestLogger(r.Context()).Errorw("Error building feed", zap.Error(err))
+		writeError(w, http.StatusBadGateway, "error getting events")
+		return nil, false
+	}
+	return v.([]FeedDay), true
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/gcal"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultCalendarInterval = 30 * time.Minute
+	defaultCalendarDays     = 7
+)
+
+var (
+	googleCalendarID          string
+	googleCalendarCredentials string
+	googleCalendarInterval    time.Duration
+	googleCalendarDays        int
+)
+
+//calendarEntry records an event synced to the calendar, Hash telling whether it changed
+type calendarEntry struct {
+	Date string `json:"date"`
+	Hash string `json:"hash"`
+}
+
+//runCalendarSync keeps the Google Calendar GOOGLE_CALENDAR_ID in step with the bookings
+//of the next days, syncing now and every interval
+func runCalendarSync(interval time.Duration) {
+	sa, err := gcal.LoadServiceAccount(googleCalendarCredentials)
+	if err != nil {
+		logger.Errorw("Error loading Google Calendar credentials", zap.Error(err))
+		return
+	}
+	client, err := gcal.New(googleCalendarID, sa, &http.Client{Timeout: 10 * time.Second})
+	if err != nil {
+		logger.Errorw("Error creating Google Calendar client", zap.Error(err))
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := syncCalendar(context.TODO(), client); err != nil {
+			logger.Errorw("Error syncing Google Calendar", zap.Error(err))
+		}
+		<-ticker.C
+	}
+}
+
+//syncCalendar creates or updates an event per booked event of the next days and
+//deletes the events whose bookings were cancelled since the last sync
+func syncCalendar(ctx context.Context, client *gcal.Client) error {
+	from := time.Now().In(tz)
+	feed, err := buildFeed(feedLocations(""), from, googleCalendarDays)
+	if err != nil {
+		return err
+	}
+	synced, err := kv.List(store.Calendar)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	for _, day := range feed {
+		for _, fe := range day.Events {
+			e := calendarEvent(fe)
+			seen[e.ID] = true
+			data, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			sum := sha256.Sum256(data)
+			entry := calendarEntry{Date: day.Date, Hash: hex.EncodeToString(sum[:])}
+			var last calendarEntry
+			if raw, ok := synced[e.ID]; ok && json.Unmarshal(raw, &last) == nil && last == entry {
+				continue
+			}
+			if err := client.Upsert(ctx, e); err != nil {
+				return err
+			}
+			if err := store.PutJSON(kv, store.Calendar, e.ID, entry); err != nil {
+				return err
+			}
+		}
+	}
+
+	first, last := from.Format(dayLayout), from.AddDate(0, 0, googleCalendarDays-1).Format(dayLayout)
+	for id, raw := range synced {
+		var entry calendarEntry
+		if err := json.Unmarshal(raw, &entry); err != nil || seen[id] {
+			continue
+		}
+		//past events stay on the calendar, only their records go
+		if entry.Date >= first && entry.Date <= last {
+			if err := client.Delete(ctx, id); err != nil {
+				return err
+			}
+		} else if entry.Date > last {
+			continue
+		}
+		if err := kv.Delete(store.Calendar, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//calendarEvent returns the calendar event of fe, the trucks booked in its description
+func calendarEvent(fe FeedEvent) gcal.Event {
+	var trucks []string
+	for _, t := range fe.Trucks {
+		truck := t.Name
+		if len(t.Categories) > 0 {
+			truck += " (" + strings.Join(t.Categories, ", ") + ")"
+		}
+		trucks = append(trucks, truck+"\n"+fmt.Sprintf(truckURL, t.ID))
+	}
+	return gcal.Event{
+		ID:          gcal.EventID(fe.LocationID, strconv.Itoa(fe.ID)),
+		Summary:     fmt.Sprintf("%v food truck(s) at %s", len(fe.Trucks), fe.Location),
+		Description: strings.Join(trucks, "\n\n"),
+		Location:    fe.Address,
+		Start:       gcal.EventTime{DateTime: fe.Start.Format(time.RFC3339), TimeZone: tz.String()},
+		End:         gcal.EventTime{DateTime: fe.End.Format(time.RFC3339), TimeZone: tz.String()},
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/internal/googlechat"
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/appsbyram/seafoodtruck-slack/internal/slackbot"
+	"go.uber.org/zap"
+)
+
+//googleChat is the CHAT posting schedules into Google Chat spaces, schedule channels
+//being space names such as spaces/AAAAxyz
+const googleChat = "googlechat"
+
+var (
+	googleChatClient = &http.Client{Timeout: 10 * time.Second}
+	//googleChatWebhooks are the incoming webhook urls of spaces keyed by space name
+	googleChatWebhooks map[string]string
+	googleChatVerifier = &googlechat.Verifier{Client: googleChatClient}
+)
+
+//googleChatEvents renders the events on day at forLocations as a card per location
+func googleChatEvents(day string, forLocations []string, order, locale string) (googlechat.Message, error) {
+	var msg googlechat.Message
+	all, err := eventsAt(day, forLocations)
+	if err != nil {
+		return msg, err
+	}
+	var empty []string
+	for _, le := range all {
+		if len(le.events) == 0 {
+			empty = append(empty, strings.Replace(i18n.T(locale, i18n.NoTrucks, le.loc.Name, dayWord(locale, day), dayOf(day).Format("Mon Jan 2")), "*", "", -1))
+			continue
+		}
+		opts := render.Options{Order: order, Locale: locale, New: newTrucksAt(le.loc.ID, dayOf(day).Format(dayLayout), le.events)}
+		msg.CardsV2 = append(msg.CardsV2, render.GoogleChat(le.loc, le.events, proxy.GetTruck, tz, opts))
+	}
+	msg.Text = strings.Join(empty, "\n")
+	return msg, nil
+}
+
+//postGoogleChatEvents posts the events on day at forLocations into the Google Chat
+//space channel through its incoming webhook
+func postGoogleChatEvents(channel, day string, forLocations []string) error {
+	webhook, ok := googleChatWebhooks[channel]
+	if !ok {
+		return &slackbot.PostError{Reason: i18n.ErrPostEvents, Err: errors.New("no google chat webhook for space " + channel)}
+	}
+	msg, err := googleChatEvents(day, forLocations, orderFor(channel), localeFor(channel))
+	if err != nil {
+		return err
+	}
+	return googlechat.Post(context.TODO(), googleChatClient, webhook, msg)
+}
+
+//googleChatHandler answers the events Google Chat sends the app: help when it's added
+//to a space, and find events or help when it's messaged
+func googleChatHandler(w http.ResponseWriter, r *http.Request) {
+	if err := googleChatVerifier.Verify(r); err != nil {
+		logger.Warnw("Rejecting Google Chat event", zap.Error(err))
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+	var event googlechat.Event
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPayloadBytes)).Decode(&event); err != nil {
+		http.Error(w, "Error reading event", http.StatusBadRequest)
+		return
+	}
+	space := event.Space.Name
+	locale := localeFor(space)
+	help := googlechat.Message{Text: strings.Join([]string{i18n.T(locale, i18n.HelpTitle),
+		i18n.T(locale, i18n.HelpFindEvents, commands.FindEvents),
+		commands.Help,
+	}, "\n")}
+
+	var resp googlechat.Message
+	switch event.Type {
+	case googlechat.AddedToSpace:
+		resp = help
+	case googlechat.MessageEvent:
+		cmd := commands.Parse(strings.TrimSpace(event.Message.ArgumentText))
+		switch cmd.Name {
+		case commands.FindEvents:
+			forLocations := locationsFor(space)
+			day, at, by := commands.FindEventsArgs(cmd.Args)
+			if at != nil {
+				forLocations = expandLocations(at)
+			}
+			order, ok := render.ParseOrder(by)
+			if !ok {
+				resp.Text = i18n.T(locale, i18n.KnownOrders, strings.Replace(render.KnownOrders(), ",", ", ", -1))
+				break
+			}
+			if len(order) == 0 {
+				order = orderFor(space)
+			}
+			if len(forLocations) == 0 {
+				resp.Text = i18n.T(locale, i18n.ErrNoLocations)
+				break
+			}
+			var err error
+			if resp, err = googleChatEvents(day, forLocations, order, locale); err != nil {
+				logger.Errorw("Error getting events for Google Chat", zap.Error(err))
+				resp = googlechat.Message{Text: i18n.T(locale, i18n.ErrEvents)}
+			}
+		case commands.Help:
+			resp = help
+		default:
+			resp.Text = i18n.T(locale, i18n.UnknownCommand)
+		}
+	default:
+		//removed from a space or a card click, nothing to say
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.Header().Set(contentTypeHeader, "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Errorw("Error answering Google Chat", zap.Error(err))
+	}
+}
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	s "github.com/appsbyram/pkg/http"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"go.uber.org/zap"
+)
+
+const (
+	//graphqlMaxDepth is how deeply queries may nest fields, location { events { bookings
+	//{ truck { photos { file } } } } } is 6 deep
+	graphqlMaxDepth = 8
+	//graphqlMaxCost bounds the fields a query resolves, counting those under a list once
+	//per item of graphqlListCost items, so a query can't fan out into thousands of
+	//upstream calls
+	graphqlMaxCost  = 2000
+	graphqlListCost = 10
+)
+
+//graphqlRequest is the body of POST /graphql
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+//cachedLocation, cachedTruck and cachedEvents go through apiCache like the REST endpoints
+func cachedLocation(id string) (seattlefoodtruck.Location, error) {
+	v, err := apiCache.fetch("location:"+id, func() (interface{}, error) {
+		return proxy.GetLocation(id)
+	})
+	if err != nil {
+		return seattlefoodtruck.Location{}, err
+	}
+	return v.(seattlefoodtruck.Location), nil
+}
+
+func cachedTruck(id string) (seattlefoodtruck.Truck, error) {
+	v, err := apiCache.fetch("truck:"+id, func() (interface{}, error) {
+		return proxy.GetTruck(id)
+	})
+	if err != nil {
+		return seattlefoodtruck.Truck{}, err
+	}
+	return v.(seattlefoodtruck.Truck), nil
+}
+
+func cachedEvents(id, day string) ([]seattlefoodtruck.Event, error) {
+	v, err := apiCache.fetch("events:"+id+":"+dayOf(day).Format(dayLayout), func() (interface{}, error) {
+		return proxy.GetEvents(id, day)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]seattlefoodtruck.Event), nil
+}
+
+//stringArg returns the string argument name of a resolver, empty when not given
+func stringArg(p graphql.ResolveParams, name string) string {
+	v, _ := p.Args[name].(string)
+	return v
+}
+
+//newGraphQLSchema builds the schema of locations, their events and bookings, trucks and
+//neighborhoods. Fields are named after the json of the REST endpoints.
+func newGraphQLSchema() (graphql.Schema, error) {
+	neighborhoodType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Neighborhood",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.Int},
+			"name":      &graphql.Field{Type: graphql.String},
+			"slug":      &graphql.Field{Type: graphql.String},
+			"latitude":  &graphql.Field{Type: graphql.Float},
+			"longitude": &graphql.Field{Type: graphql.Float},
+		},
+	})
+	categoryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "FoodCategory",
+		Fields: graphql.Fields{
+			"id":   &graphql.Field{Type: graphql.String},
+			"name": &graphql.Field{Type: graphql.String},
+		},
+	})
+	menuItemType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "MenuItem",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.Int},
+			"name":        &graphql.Field{Type: graphql.String},
+			"description": &graphql.Field{Type: graphql.String},
+			"price":       &graphql.Field{Type: graphql.Float},
+		},
+	})
+	photoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Photo",
+		Fields: graphql.Fields{
+			"id":       &graphql.Field{Type: graphql.Int},
+			"file":     &graphql.Field{Type: graphql.String},
+			"position": &graphql.Field{Type: graphql.Int},
+		},
+	})
+	truckType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Truck",
+		Fields: graphql.Fields{
+			"id":              &graphql.Field{Type: graphql.String},
+			"name":            &graphql.Field{Type: graphql.String},
+			"description":     &graphql.Field{Type: graphql.String},
+			"rating":          &graphql.Field{Type: graphql.Float},
+			"rating_count":    &graphql.Field{Type: graphql.Int},
+			"featured_photo":  &graphql.Field{Type: graphql.String},
+			"website":         &graphql.Field{Type: graphql.String},
+			"vegetarian":      &graphql.Field{Type: graphql.Boolean},
+			"vegan":           &graphql.Field{Type: graphql.Boolean},
+			"gluten_free":     &graphql.Field{Type: graphql.Boolean},
+			"paleo":           &graphql.Field{Type: graphql.Boolean},
+			"food_categories": &graphql.Field{Type: graphql.NewList(categoryType)},
+			"menu_items":      &graphql.Field{Type: graphql.NewList(menuItemType)},
+			"photos":          &graphql.Field{Type: graphql.NewList(photoType)},
+		},
+	})
+	bookingType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Booking",
+		Fields: graphql.Fields{
+			"id":     &graphql.Field{Type: graphql.Int},
+			"status": &graphql.Field{Type: graphql.String},
+			"truck": &graphql.Field{
+				Type: truckType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return cachedTruck(p.Source.(seattlefoodtruck.Booking).Truck.ID)
+				},
+			},
+		},
+	})
+	eventType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Event",
+		Fields: graphql.Fields{
+			"id":         &graphql.Field{Type: graphql.Int},
+			"name":       &graphql.Field{Type: graphql.String},
+			"start_time": &graphql.Field{Type: graphql.String},
+			"end_time":   &graphql.Field{Type: graphql.String},
+			"bookings":   &graphql.Field{Type: graphql.NewList(bookingType)},
+		},
+	})
+	locationType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Location",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.String},
+			"name":      &graphql.Field{Type: graphql.String},
+			"address":   &graphql.Field{Type: graphql.String},
+			"latitude":  &graphql.Field{Type: graphql.Float},
+			"longitude": &graphql.Field{Type: graphql.Float},
+			"neighborhood": &graphql.Field{
+				Type: neighborhoodType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(seattlefoodtruck.Location).Neighborhood, nil
+				},
+			},
+			"events": &graphql.Field{
+				Type: graphql.NewList(eventType),
+				Args: graphql.FieldConfigArgument{
+					"day": &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: today},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return cachedEvents(p.Source.(seattlefoodtruck.Location).ID, stringArg(p, "day"))
+				},
+			},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"location": &graphql.Field{
+				Type: locationType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return cachedLocation(stringArg(p, "id"))
+				},
+			},
+			"locations": &graphql.Field{
+				Type: graphql.NewList(locationType),
+				Args: graphql.FieldConfigArgument{
+					"query":        &graphql.ArgumentConfig{Type: graphql.String},
+					"neighborhood": &graphql.ArgumentConfig{Type: graphql.Int},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					neighborhood, _ := p.Args["neighborhood"].(int)
+					return proxy.FindLocations(p.Context, stringArg(p, "query"), neighborhood)
+				},
+			},
+			"truck": &graphql.Field{
+				Type: truckType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return cachedTruck(stringArg(p, "id"))
+				},
+			},
+			"trucks": &graphql.Field{
+				Type: graphql.NewList(truckType),
+				Args: graphql.FieldConfigArgument{
+					"category": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					category := stringArg(p, "category")
+					return apiCache.fetch("trucks:"+category, func() (interface{}, error) {
+						return proxy.TrucksByCategory(context.TODO(), category)
+					})
+				},
+			},
+			"neighborhoods": &graphql.Field{
+				Type: graphql.NewList(neighborhoodType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return proxy.GetNeighborhoods()
+				},
+			},
+		},
+	})
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+//graphqlHandler executes the query of ?query= or of a json body against schema
+func graphqlHandler(schema graphql.Schema) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req graphqlRequest
+		if r.Method == http.MethodPost {
+			r.Body = http.MaxBytesReader(w, r.Body, maxPayloadBytes)
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid graphql request")
+				return
+			}
+		} else {
+			req.Query = r.URL.Query().Get("query")
+			req.OperationName = r.URL.Query().Get("operationName")
+		}
+		if len(req.Query) == 0 {
+			writeError(w, http.StatusBadRequest, "query is required")
+			return
+		}
+		if err := checkGraphQLQuery(schema, req.Query); err != nil {
+			requestLogger(r.Context()).Infow("GraphQL query rejected", zap.Error(err))
+			s.NewPayload().WriteResponse(s.ContentTypeJSON, http.StatusOK,
+				&graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(err.Error())}}, w)
+			return
+		}
+
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  req.Query,
+			VariableValues: req.Variables,
+			OperationName:  req.OperationName,
+			Context:        r.Context(),
+		})
+		if result.HasErrors() {
+			requestLogger(r.Context()).Infow("GraphQL query failed", zap.Any("errors", result.Errors))
+		}
+		s.NewPayload().WriteResponse(s.ContentTypeJSON, http.StatusOK, result, w)
+	}
+}
+
+//checkGraphQLQuery rejects queries nesting deeper than graphqlMaxDepth or costing more
+//than graphqlMaxCost. Queries that don't parse are left to graphql.Do to report.
+func checkGraphQLQuery(schema graphql.Schema, query string) error {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return nil
+	}
+	q := &queryCost{schema: schema, fragments: make(map[string]*ast.FragmentDefinition)}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok && f.Name != nil {
+			q.fragments[f.Name.Value] = f
+		}
+	}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		var root graphql.Type = schema.QueryType()
+		if op.Operation == ast.OperationTypeMutation {
+			root = schema.MutationType()
+		}
+		depth, cost := q.selectionSet(root, op.SelectionSet, 1, make(map[string]bool))
+		if depth > graphqlMaxDepth {
+			return fmt.Errorf("query is nested %d deep, more than the %d allowed", depth, graphqlMaxDepth)
+		}
+		if cost > graphqlMaxCost {
+			return fmt.Errorf("query costs %d, more than the %d allowed, select fewer fields or lists", cost, graphqlMaxCost)
+		}
+	}
+	return nil
+}
+
+//queryCost measures the selections of a query against the schema
+type queryCost struct {
+	schema    graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+}
+
+//selectionSet returns the depth of the deepest field of set, whose fields are at depth,
+//and their cost. Fields of parent are costed 1 each, the fields selected under a list
+//graphqlListCost times. Introspection fields are free, they don't call upstream.
+func (q *queryCost) selectionSet(parent graphql.Type, set *ast.SelectionSet, depth int, spreading map[string]bool) (int, int) {
+	if set == nil {
+		return depth - 1, 0
+	}
+	if depth > graphqlMaxDepth {
+		//deep enough to reject, no need to cost what's below
+		return depth, 0
+	}
+	maxDepth, cost := depth, 0
+	for _, sel := range set.Selections {
+		d, c := depth, 0
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if sel.Name == nil || strings.HasPrefix(sel.Name.Value, "__") {
+				continue
+			}
+			var fieldType graphql.Type
+			if fields, ok := parent.(withFields); ok {
+				if def, ok := fields.Fields()[sel.Name.Value]; ok {
+					fieldType = def.Type
+				}
+			}
+			var below int
+			d, below = q.selectionSet(namedType(fieldType), sel.SelectionSet, depth+1, spreading)
+			if isList(fieldType) {
+				below *= graphqlListCost
+			}
+			c = 1 + below
+		case *ast.InlineFragment:
+			d, c = q.selectionSet(q.typeCondition(sel.TypeCondition, parent), sel.SelectionSet, depth, spreading)
+		case *ast.FragmentSpread:
+			f, ok := q.fragments[sel.Name.Value]
+			if !ok || spreading[f.Name.Value] {
+				//unknown and cyclic fragments fail validation
+				continue
+			}
+			spreading[f.Name.Value] = true
+			d, c = q.selectionSet(q.typeCondition(f.TypeCondition, parent), f.SelectionSet, depth, spreading)
+			delete(spreading, f.Name.Value)
+		}
+		if d > maxDepth {
+			maxDepth = d
+		}
+		cost += c
+	}
+	return maxDepth, cost
+}
+
+//withFields are the objects and interfaces of a schema
+type withFields interface {
+	Fields() graphql.FieldDefinitionMap
+}
+
+//typeCondition returns the type named by cond, parent when there is none
+func (q *queryCost) typeCondition(cond *ast.Named, parent graphql.Type) graphql.Type {
+	if cond == nil || cond.Name == nil {
+		return parent
+	}
+	if t := q.schema.Type(cond.Name.Value); t != nil {
+		return t
+	}
+	return parent
+}
+
+//namedType unwraps the lists and non null of t
+func namedType(t graphql.Type) graphql.Type {
+	for {
+		switch wrapped := t.(type) {
+		case *graphql.NonNull:
+			t = wrapped.OfType
+		case *graphql.List:
+			t = wrapped.OfType
+		default:
+			return t
+		}
+	}
+}
+
+//isList reports whether t is a list, nullable or not
+func isList(t graphql.Type) bool {
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		t = nonNull.OfType
+	}
+	_, ok := t.(*graphql.List)
+	return ok
+}
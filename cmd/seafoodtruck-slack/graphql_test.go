@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+//aliases returns a query asking for the trucks of n locations under aliases
+func aliases(n int) string {
+	var b strings.Builder
+	b.WriteString("{")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, ` l%d: location(id: "%d") { events { bookings { truck { name } } } }`, i, i)
+	}
+	return b.String() + " }"
+}
+
+func TestCheckGraphQLQuery(t *testing.T) {
+	schema, err := newGraphQLSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		query string
+		//reject is part of the error, empty when the query is accepted
+		reject string
+	}{
+		{"a location's trucks", `{ location(id: "69") { name events { start_time bookings { truck { name rating } } } } }`, ""},
+		{"a truck's photos", `{ truck(id: "marination") { name menu_items { name price } photos { file } } }`, ""},
+		{"introspection", `{ __schema { types { name fields { name type { name ofType { name ofType { name ofType { name ofType { name } } } } } } } } }`, ""},
+		{"invalid queries are left to graphql", `{ location(id: "69") {`, ""},
+		{"every location's trucks", `{ locations { name events { bookings { truck { name photos { file } } } } } }`, "costs"},
+		{"a few aliases", aliases(5), ""},
+		{"many aliases", aliases(20), "costs"},
+		{"6 deep through inline fragments", `{ location(id: "69") { ... on Location { events { bookings { truck { photos { ... { file } } } } } } } }`, ""},
+		{"too deep", `{ location(id: "69") { a { b { c { d { e { f { g { h } } } } } } } } }`, "nested"},
+		{"fragments count where they are spread", `query { location(id: "69") { ...L } }
+			fragment L on Location { events { bookings { truck { ...T } } } }
+			fragment T on Truck { photos { file } menu_items { name } food_categories { name } }`, "costs"},
+		{"cyclic fragments", `{ location(id: "69") { ...A } } fragment A on Location { ...B } fragment B on Location { ...A }`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkGraphQLQuery(schema, tt.query)
+			switch {
+			case len(tt.reject) == 0 && err != nil:
+				t.Errorf("rejected: %v", err)
+			case len(tt.reject) > 0 && (err == nil || !strings.Contains(err.Error(), tt.reject)):
+				t.Errorf("error %v, want one saying the query %s", err, tt.reject)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/schedule"
+)
+
+var (
+	groupsMu sync.RWMutex
+	//locationGroups maps a group name such as an office to its location ids, guarded by groupsMu
+	locationGroups map[string][]string
+)
+
+//currentLocationGroups returns the location groups, replaced as a whole on reload
+func currentLocationGroups() map[string][]string {
+	groupsMu.RLock()
+	defer groupsMu.RUnlock()
+	return locationGroups
+}
+
+func setLocationGroups(groups map[string][]string) {
+	groupsMu.Lock()
+	defer groupsMu.Unlock()
+	locationGroups = groups
+}
+
+//loadLocationGroups indexes the configured mapping of group name to location ids,
+//e.g. {"slu": ["69", "123"], "bellevue": ["45"]}, by lower case name
+func loadLocationGroups(configured map[string][]string) map[string][]string {
+	groups := make(map[string][]string)
+	for name, ids := range configured {
+		groups[strings.ToLower(name)] = ids
+	}
+	return groups
+}
+
+//expandLocations replaces group names in ids with the locations of the group,
+//dropping duplicates while keeping the order
+func expandLocations(ids []string) []string {
+	return schedule.ExpandLocations(ids, currentLocationGroups())
+}
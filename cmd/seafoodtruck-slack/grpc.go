@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/schedulepb"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//changeStreamKey is the pseudo channel the watcher diffs snapshots under for gRPC streams,
+//so each change is streamed once no matter how many channels watch the location
+const changeStreamKey = "grpc"
+
+var (
+	grpcServer *grpc.Server
+
+	changeSubsMu sync.Mutex
+	changeSubs   = make(map[chan ScheduleChange]bool)
+)
+
+//subscribeChanges returns a channel receiving the changes found by the watcher
+func subscribeChanges() chan ScheduleChange {
+	ch := make(chan ScheduleChange, 64)
+	changeSubsMu.Lock()
+	changeSubs[ch] = true
+	changeSubsMu.Unlock()
+	return ch
+}
+
+func unsubscribeChanges(ch chan ScheduleChange) {
+	changeSubsMu.Lock()
+	defer changeSubsMu.Unlock()
+	if changeSubs[ch] {
+		delete(changeSubs, ch)
+		close(ch)
+	}
+}
+
+//publishChanges hands changes to every subscriber, dropping them for subscribers too
+//slow to keep up rather than holding up the watcher
+func publishChanges(changes []ScheduleChange) {
+	changeSubsMu.Lock()
+	defer changeSubsMu.Unlock()
+	for ch := range changeSubs {
+		for _, change := range changes {
+			select {
+			case ch <- change:
+			default:
+				logger.Warn("Dropping schedule change for slow gRPC stream")
+			}
+		}
+	}
+}
+
+//closeChangeStreams ends every change stream so the gRPC server can stop gracefully
+func closeChangeStreams() {
+	changeSubsMu.Lock()
+	defer changeSubsMu.Unlock()
+	for ch := range changeSubs {
+		delete(changeSubs, ch)
+		close(ch)
+	}
+}
+
+//scheduleServer implements the Schedule service on top of the cached client
+type scheduleServer struct {
+	schedulepb.UnimplementedScheduleServer
+}
+
+func (scheduleServer) ListEvents(ctx context.Context, req *schedulepb.ListEventsRequest) (*schedulepb.ListEventsResponse, error) {
+	if len(req.LocationId) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "location_id is required")
+	}
+	var events []seattlefoodtruck.Event
+	var err error
+	switch req.Day {
+	case "", today, tomorrow:
+		day := req.Day
+		if len(day) == 0 {
+			day = today
+		}
+		events, err = cachedEvents(req.LocationId, day)
+	default:
+		t, perr := time.ParseInLocation(dayLayout, req.Day, tz)
+		if perr != nil {
+			return nil, status.Error(codes.InvalidArgument, "day must be today, tomorrow or look like "+dayLayout)
+		}
+		events, err = proxy.GetEventsOn(ctx, req.LocationId, t)
+	}
+	if err != nil {
+		logger.Errorw("Error getting events for gRPC", zap.Error(err))
+		return nil, status.Error(codes.Unavailable, "error getting events")
+	}
+
+	resp := &schedulepb.ListEventsResponse{}
+	for _, e := range events {
+		event := &schedulepb.Event{Id: int64(e.ID), LocationId: req.LocationId, StartTime: e.StartTime, EndTime: e.EndTime}
+		for _, b := range e.Bookings {
+			event.Bookings = append(event.Bookings, &schedulepb.Booking{TruckId: b.Truck.ID, TruckName: b.Truck.Name, Categories: b.Truck.FoodCategories})
+		}
+		resp.Events = append(resp.Events, event)
+	}
+	return resp, nil
+}
+
+func (scheduleServer) GetTruck(ctx context.Context, req *schedulepb.GetTruckRequest) (*schedulepb.Truck, error) {
+	truck, err := cachedTruck(req.Id)
+	if err != nil {
+		logger.Errorw("Error getting truck for gRPC", zap.Error(err))
+		return nil, status.Error(codes.Unavailable, "error getting truck")
+	}
+	if len(truck.ID) == 0 {
+		return nil, status.Error(codes.NotFound, "no truck "+req.Id)
+	}
+	t := &schedulepb.Truck{Id: truck.ID, Name: truck.Name, Description: truck.Description,
+		Rating: truck.Rating, RatingCount: int32(truck.RatingCount), Website: truck.Website}
+	for _, fc := range truck.FoodCategories {
+		t.Categories = append(t.Categories, fc.Name)
+	}
+	return t, nil
+}
+
+func (scheduleServer) GetLocation(ctx context.Context, req *schedulepb.GetLocationRequest) (*schedulepb.Location, error) {
+	loc, err := cachedLocation(req.Id)
+	if err != nil {
+		logger.Errorw("Error getting location for gRPC", zap.Error(err))
+		return nil, status.Error(codes.Unavailable, "error getting location")
+	}
+	if len(loc.ID) == 0 {
+		return nil, status.Error(codes.NotFound, "no location "+req.Id)
+	}
+	return &schedulepb.Location{Id: loc.ID, Name: loc.Name, Address: loc.Address,
+		Latitude: loc.Latitude, Longitude: loc.Longitude, Neighborhood: loc.Neighborhood.Name}, nil
+}
+
+//StreamScheduleChanges sends what the watcher finds until the client goes away, it needs
+//watch-interval to be set to ever send anything
+func (scheduleServer) StreamScheduleChanges(req *schedulepb.StreamScheduleChangesRequest, stream schedulepb.Schedule_StreamScheduleChangesServer) error {
+	only := make(map[string]bool)
+	for _, id := range expandLocations(req.LocationIds) {
+		only[id] = true
+	}
+	changes := subscribeChanges()
+	defer unsubscribeChanges(changes)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case change, ok := <-changes:
+			if !ok {
+				return nil
+			}
+			if len(only) > 0 && !only[change.Location.ID] {
+				continue
+			}
+			err := stream.Send(&schedulepb.ScheduleChange{
+				Kind:         change.Kind,
+				LocationId:   change.Location.ID,
+				LocationName: change.Location.Name,
+				TruckId:      change.TruckID,
+				Truck:        change.Truck,
+				StartTime:    change.Start.Format(time.RFC3339),
+				EndTime:      change.End.Format(time.RFC3339),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+//serveGRPC serves the Schedule service on addr in the background, alongside the http server
+func serveGRPC(addr string) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		logger.Errorw("Error listening for gRPC", zap.Error(err))
+		return
+	}
+	grpcServer = grpc.NewServer()
+	schedulepb.RegisterScheduleServer(grpcServer, scheduleServer{})
+
+	logger.Infof("Serving gRPC on %s", addr)
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			logger.Errorw("Error serving gRPC", zap.Error(err))
+		}
+	}()
+}
+
+//stopGRPC ends the change streams and lets the remaining calls finish
+func stopGRPC() {
+	if grpcServer == nil {
+		return
+	}
+	closeChangeStreams()
+	grpcServer.GracefulStop()
+}
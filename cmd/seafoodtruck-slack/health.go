@@ -0,0 +1,72 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	s "github.com/appsbyram/pkg/http"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"go.uber.org/zap"
+)
+
+//readyProbeKey is looked up in the store to check it answers
+const readyProbeKey = "readyz"
+
+//ready is set once serve started posting and answering, and cleared on shutdown
+var ready int32
+
+func setReady(r bool) {
+	var v int32
+	if r {
+		v = 1
+	}
+	atomic.StoreInt32(&ready, v)
+}
+
+//readyHandler answers 200 once the bot is up and its store answers, and 503 otherwise
+//so orchestrators hold traffic back
+func readyHandler(w http.ResponseWriter, r *http.Request) {
+	p := s.NewPayload()
+	if atomic.LoadInt32(&ready) == 0 {
+		p.WriteResponse(s.ContentTypeJSON, http.StatusServiceUnavailable, s.HealthReport{Status: "STARTING"}, w)
+		return
+	}
+	if _, err := kv.Get(store.Dedup, readyProbeKey); err != nil && err != store.ErrNotFound {
+		requestLogger(r.Context()).Warnw("Store not ready", zap.Error(err))
+		p.WriteResponse(s.ContentTypeJSON, http.StatusServiceUnavailable, s.HealthReport{Status: "DOWN"}, w)
+		return
+	}
+	p.WriteResponse(s.ContentTypeJSON, http.StatusOK, s.HealthReport{Status: "UP"}, w)
+}
+
+//healthcheck gets /readyz of the bot listening on listen, over https when useTLS is
+//set, and fails unless it is ready
+func healthcheck(listen string, useTLS bool, timeout time.Duration) error {
+	host, port, err := net.SplitHostPort(listen)
+	if err != nil {
+		return err
+	}
+	if len(host) == 0 || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	scheme := "http"
+	client := &http.Client{Timeout: timeout}
+	if useTLS {
+		scheme = "https"
+		//the certificate is for the public host name, not localhost
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+	resp, err := client.Get(fmt.Sprintf("%s://%s/readyz", scheme, net.JoinHostPort(host, port)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("not ready: %s", resp.Status)
+	}
+	return nil
+}
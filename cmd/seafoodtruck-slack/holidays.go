@@ -0,0 +1,115 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	dayLayout         = "2006-01-02"
+	usHolidayCalendar = "us"
+)
+
+//holidayName returns the name of the office holiday falling on t, if any.
+//Holidays come from HOLIDAYS (comma separated YYYY-MM-DD dates) and, when
+//HOLIDAY_CALENDAR=us, the observed US federal holidays.
+func holidayName(t time.Time) (string, bool) {
+	day := t.Format(dayLayout)
+	for _, h := range commands.SplitIDs(holidays) {
+		if h == day {
+			return "Office holiday", true
+		}
+	}
+	if strings.EqualFold(holidayCalendar, usHolidayCalendar) {
+		for name, d := range usHolidays(t.Year()) {
+			if d.Format(dayLayout) == day {
+				return name, true
+			}
+		}
+		//December 31 observes New Year's Day of the following year falling on a Saturday
+		for name, d := range usHolidays(t.Year() + 1) {
+			if d.Format(dayLayout) == day {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+//usHolidays returns the observed dates of the US federal holidays in year
+func usHolidays(year int) map[string]time.Time {
+	fixed := func(m time.Month, d int) time.Time {
+		return observed(time.Date(year, m, d, 0, 0, 0, 0, time.UTC))
+	}
+	return map[string]time.Time{
+		"New Year's Day":             fixed(time.January, 1),
+		"Martin Luther King Jr. Day": nthWeekday(year, time.January, time.Monday, 3),
+		"Presidents' Day":            nthWeekday(year, time.February, time.Monday, 3),
+		"Memorial Day":               lastWeekday(year, time.May, time.Monday),
+		"Juneteenth":                 fixed(time.June, 19),
+		"Independence Day":           fixed(time.July, 4),
+		"Labor Day":                  nthWeekday(year, time.September, time.Monday, 1),
+		"Indigenous Peoples' Day":    nthWeekday(year, time.October, time.Monday, 2),
+		"Veterans Day":               fixed(time.November, 11),
+		"Thanksgiving Day":           nthWeekday(year, time.November, time.Thursday, 4),
+		"Christmas Day":              fixed(time.December, 25),
+	}
+}
+
+//observed moves a holiday falling on a weekend to the nearest weekday
+func observed(t time.Time) time.Time {
+	switch t.Weekday() {
+	case time.Saturday:
+		return t.AddDate(0, 0, -1)
+	case time.Sunday:
+		return t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+func nthWeekday(year int, m time.Month, wd time.Weekday, n int) time.Time {
+	t := time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+	for t.Weekday() != wd {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t.AddDate(0, 0, 7*(n-1))
+}
+
+func lastWeekday(year int, m time.Month, wd time.Weekday) time.Time {
+	t := time.Date(year, m+1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	for t.Weekday() != wd {
+		t = t.AddDate(0, 0, -1)
+	}
+	return t
+}
+
+//skipHolidays wraps a scheduled post of day's events so it does not run on office
+//holidays, optionally leaving a note in the channel instead
+func skipHolidays(channel, day string, post func() error) func() error {
+	return func() error {
+		on := time.Now().In(tz)
+		if day == tomorrow {
+			on = on.AddDate(0, 0, 1)
+		}
+		name, ok := holidayName(on)
+		if !ok {
+			return post()
+		}
+		logger.Infof("Skipping post for channel %s, %s is %s", channel, on.Format(dayLayout), name)
+		if holidayNote && day != tomorrow {
+			text := i18n.T(localeFor(channel), i18n.HolidayNote, name)
+			if name == "Office holiday" {
+				text = i18n.T(localeFor(channel), i18n.OfficeHolidayNote)
+			}
+			if _, _, err := postMessage(channel, slack.MsgOptionText(text, false)); err != nil {
+				logger.Errorw("Error posting holiday note", zap.Error(err))
+			}
+		}
+		return nil
+	}
+}
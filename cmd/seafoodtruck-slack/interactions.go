@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/appsbyram/seafoodtruck-slack/internal/slackbot"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+//actionHandlers handle the block actions of interactive messages, keyed by action id
+var actionHandlers = map[string]func(cb *slack.InteractionCallback, action *slack.BlockAction){
+	voteActionID:                votePoll,
+	rsvpActionID:                toggleRSVP,
+	render.TruckDetailsActionID: showTruckDetails,
+	render.FullMenuActionID:     showFullMenu,
+	//the Directions button opens its maps link, the click only needs acknowledging
+	render.DirectionsActionID: func(*slack.InteractionCallback, *slack.BlockAction) {},
+}
+
+//interactionsHandler receives the button clicks Slack posts to the interactivity request URL
+func interactionsHandler(w http.ResponseWriter, r *http.Request) {
+	buf, err := slackbot.ReadPayload(w, r, maxPayloadBytes)
+	if err != nil {
+		logger.Errorw("Error reading interaction payload", zap.Error(err))
+		slackbot.PayloadError(w, err)
+		return
+	}
+	defer slackbot.ReleasePayload(buf)
+	if err := verifySlackRequest(r, buf.Bytes()); err != nil {
+		logger.Warnw("Rejecting interaction with invalid signature", zap.Error(err))
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(buf.String())
+	if err != nil {
+		http.Error(w, "Error parsing payload", http.StatusBadRequest)
+		return
+	}
+	var cb slack.InteractionCallback
+	if err := json.Unmarshal([]byte(form.Get("payload")), &cb); err != nil {
+		logger.Errorw("Error parsing interaction payload", zap.Error(err))
+		http.Error(w, "Error parsing payload", http.StatusBadRequest)
+		return
+	}
+	//acknowledge within Slack's 3 seconds, handlers answer through the web api
+	w.WriteHeader(http.StatusOK)
+	dispatchActions(&cb)
+}
+
+//dispatchActions hands each block action of cb to its handler without blocking
+func dispatchActions(cb *slack.InteractionCallback) {
+	for _, action := range cb.ActionCallback.BlockActions {
+		if handle, ok := actionHandlers[action.ActionID]; ok {
+			action := action
+			safeGo(cb.Channel.ID, func() { handle(cb, action) })
+			continue
+		}
+		logger.Warnf("No handler for action %s", action.ActionID)
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	s "github.com/appsbyram/pkg/http"
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/internal/schedule"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/scheduler"
+	"github.com/gorilla/mux"
+	"github.com/slack-go/slack/slackevents"
+)
+
+const (
+	postsJob   = "posts"
+	weeklyJob  = "weekly preview"
+	eveningJob = "evening post"
+)
+
+var (
+	jobsMu sync.Mutex
+	//jobs maps scheduler entries to the channel and kind of post they run
+	jobs = make(map[scheduler.EntryID]jobInfo)
+	//pausedJobs survives scheduler restarts, keyed by kind and channel
+	pausedJobs = make(map[string]bool)
+)
+
+type jobInfo struct {
+	Kind    string
+	Channel string
+}
+
+func (j jobInfo) key() string {
+	return j.Kind + ":" + j.Channel
+}
+
+//JobStatus describes a scheduled job for the admin api
+type JobStatus struct {
+	ID        int       `json:"id"`
+	Kind      string    `json:"kind"`
+	Channel   string    `json:"channel"`
+	Spec      string    `json:"spec"`
+	Locations []string  `json:"locations"`
+	Next      time.Time `json:"next"`
+	Paused    bool      `json:"paused"`
+}
+
+//trackJob remembers what entry id of cron runs job and re-applies a pause from before
+//a restart
+func trackJob(cron scheduler.Scheduler, id scheduler.EntryID, job schedule.Job) {
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+
+	j := jobInfo{Kind: job.Kind, Channel: job.Channel}
+	jobs[id] = j
+	if pausedJobs[j.key()] {
+		cron.Pause(id)
+	}
+}
+
+func resetJobs() {
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+
+	jobs = make(map[scheduler.EntryID]jobInfo)
+}
+
+func listJobs() []JobStatus {
+	var result []JobStatus
+
+	c := runner.Cron()
+	if c == nil {
+		return result
+	}
+	for _, e := range c.Entries() {
+		jobsMu.Lock()
+		j := jobs[e.ID]
+		jobsMu.Unlock()
+
+		result = append(result, JobStatus{
+			ID:        int(e.ID),
+			Kind:      j.Kind,
+			Channel:   j.Channel,
+			Spec:      e.Spec,
+			Locations: locationsFor(j.Channel),
+			Next:      e.Next,
+			Paused:    e.Paused,
+		})
+	}
+	return result
+}
+
+//setJobPaused pauses or resumes the job with id
+func setJobPaused(id int, paused bool) error {
+	c := runner.Cron()
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+
+	j, ok := jobs[scheduler.EntryID(id)]
+	if !ok || c == nil {
+		return fmt.Errorf("job %v not found", id)
+	}
+	//remembered first so a restart racing with this re-applies it
+	if paused {
+		pausedJobs[j.key()] = true
+		c.Pause(scheduler.EntryID(id))
+	} else {
+		delete(pausedJobs, j.key())
+		c.Resume(scheduler.EntryID(id))
+	}
+	return nil
+}
+
+//rescheduleJob changes the spec of the job with id, persisting it with the channel schedule
+func rescheduleJob(id int, spec string) error {
+	if _, err := scheduler.Parse(spec); err != nil {
+		return err
+	}
+	jobsMu.Lock()
+	j, ok := jobs[scheduler.EntryID(id)]
+	jobsMu.Unlock()
+	if !ok {
+		return fmt.Errorf("job %v not found", id)
+	}
+
+	schedulesMu.RLock()
+	sch, ok := schedules[j.Channel]
+	schedulesMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("channel %s has no schedule", j.Channel)
+	}
+	switch j.Kind {
+	case postsJob:
+		sch.Spec = spec
+	case weeklyJob:
+		sch.WeeklySpec = spec
+	case eveningJob:
+		sch.EveningSpec = spec
+	}
+	return saveSchedule(sch)
+}
+
+//jobsCommand handles the jobs, pause job, resume job and reschedule job admin commands
+func jobsCommand(event *slackevents.AppMentionEvent, cmd commands.Command) {
+	if !isAdmin(event.User) {
+		postEphemeral(event, tr(event, i18n.JobsAdminOnly))
+		return
+	}
+	var err error
+	switch cmd.Name {
+	case commands.Jobs:
+		postEphemeral(event, formatJobs(localeOf(event.User, event.Channel), listJobs()))
+		return
+	case commands.PauseJob:
+		err = withJobID(cmd.Args, func(id int, _ string) error { return setJobPaused(id, true) })
+	case commands.ResumeJob:
+		err = withJobID(cmd.Args, func(id int, _ string) error { return setJobPaused(id, false) })
+	case commands.RescheduleJob:
+		err = withJobID(cmd.Args, rescheduleJob)
+	}
+	if err != nil {
+		postEphemeral(event, tr(event, i18n.JobsFailed, err))
+		return
+	}
+	postEphemeral(event, tr(event, i18n.JobsDone, formatJobs(localeOf(event.User, event.Channel), listJobs())))
+}
+
+//withJobID parses "<id> [rest]" and calls fn with both parts
+func withJobID(args string, fn func(id int, rest string) error) error {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return fmt.Errorf("please tell me the job id, see `%s`", commands.Jobs)
+	}
+	id, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return fmt.Errorf("%s is not a job id", fields[0])
+	}
+	return fn(id, strings.Join(fields[1:], " "))
+}
+
+func formatJobs(locale string, statuses []JobStatus) string {
+	if len(statuses) == 0 {
+		return i18n.T(locale, i18n.JobsNone)
+	}
+	var sb strings.Builder
+	for _, j := range statuses {
+		sb.WriteString(i18n.T(locale, i18n.JobLine,
+			j.ID, j.Kind, j.Channel, j.Spec, j.Next.In(tz).Format("Mon Jan 2 3:04PM"),
+			strings.Join(j.Locations, ", ")))
+		if j.Paused {
+			sb.WriteString(i18n.T(locale, i18n.JobPaused))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+//adminJobsHandler lists the scheduled jobs
+func adminJobsHandler(w http.ResponseWriter, r *http.Request) {
+	p := s.NewPayload()
+	if !authorized(r) {
+		p.WriteResponse(s.ContentTypeJSON, http.StatusUnauthorized, &AdminResponse{Status: "unauthorized"}, w)
+		return
+	}
+	statuses := listJobs()
+	p.WriteResponse(s.ContentTypeJSON, http.StatusOK, &statuses, w)
+}
+
+//adminJobHandler pauses, resumes or reschedules the job {id} according to {action},
+//reschedule reads the new spec from ?spec=
+func adminJobHandler(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	p := s.NewPayload()
+	if !authorized(r) {
+		p.WriteResponse(s.ContentTypeJSON, http.StatusUnauthorized, &AdminResponse{Status: "unauthorized"}, w)
+		return
+	}
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		p.WriteResponse(s.ContentTypeJSON, http.StatusBadRequest, &AdminResponse{Status: "error", Message: "invalid job id"}, w)
+		return
+	}
+	switch vars["action"] {
+	case "pause":
+		err = setJobPaused(id, true)
+	case "resume":
+		err = setJobPaused(id, false)
+	case "reschedule":
+		err = rescheduleJob(id, r.URL.Query().Get("spec"))
+	default:
+		p.WriteResponse(s.ContentTypeJSON, http.StatusNotFound, &AdminResponse{Status: "error", Message: "unknown action"}, w)
+		return
+	}
+	if err != nil {
+		p.WriteResponse(s.ContentTypeJSON, http.StatusBadRequest, &AdminResponse{Status: "error", Message: err.Error()}, w)
+		return
+	}
+	p.WriteResponse(s.ContentTypeJSON, http.StatusOK, &AdminResponse{Status: "ok"}, w)
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awslambda "github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/gorilla/mux"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+//watchJob re-fetches today's events and announces changes, as --watch-interval does
+const watchJob = "watch"
+
+//lambdaJob is the constant input of the EventBridge rules that replace the cron, e.g.
+//{"job": "posts"} or {"job": "weekly preview", "channel": "C0123"}, or the input of the
+//function invoking itself with Replay, an API Gateway event whose work it deferred
+type lambdaJob struct {
+	Job     string          `json:"job"`
+	Channel string          `json:"channel"`
+	Replay  json.RawMessage `json:"replay,omitempty"`
+}
+
+//The function answers one invocation at a time, the invocation loop owns these
+var (
+	//deferWork makes safeGo drop work rather than start it while an API Gateway request
+	//is answered, workDeferred records it did
+	deferWork, workDeferred bool
+	//replaying lets events recorded by firstDelivery through again while the work
+	//deferred answering them is done
+	replaying bool
+)
+
+func newLambdaCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lambda",
+		Short: "Answer API Gateway and EventBridge invocations on AWS Lambda",
+		Long: `Answer API Gateway and EventBridge invocations on AWS Lambda.
+
+Run it as the bootstrap of a custom runtime function. API Gateway proxy events, from a
+REST or an HTTP API, are answered by the REST and Slack endpoints serve has. There is no
+in-process cron: EventBridge rules invoke the function on the schedules' times with the
+constant input {"job": "posts"}, "evening post", "weekly preview" or "watch", and an
+optional "channel".
+
+Slack is answered within its 3 seconds: the work a request starts, such as answering a
+mention, is done by the function invoking itself asynchronously, which its role needs
+lambda:InvokeFunction on the function for.`,
+		Args: cobra.NoArgs,
+		RunE: func(*cobra.Command, []string) error {
+			return runLambda()
+		},
+	}
+	fs := cmd.Flags()
+	fs.Int64Var(&maxPayloadBytes, "max-payload-bytes", 1<<20, "Largest Slack payload accepted, bigger ones are answered with 413.")
+	fs.DurationVar(&secretTTL, "secret-ttl", time.Hour, "How long secrets fetched from secrets backends are cached before being fetched again to pick up rotations, 0 fetches them once.")
+	fs.DurationVar(&apiCacheTTL, "api-cache-ttl", 10*time.Minute, "How long the REST endpoints cache upstream responses, 0 disables caching.")
+	fs.IntVar(&feedDays, "feed-days", 5, "Days of events /feed.json returns unless asked for more or fewer.")
+	return cmd
+}
+
+//runLambda answers the invocations of the function until Lambda shuts it down
+func runLambda() error {
+	//a frozen function can't wait out quiet hours, posts during them are skipped
+	oneShot = true
+	runCron = false
+	bootstrap()
+	apiCache = newTTLCache(apiCacheTTL)
+	handler := newRouter(withMiddleware(serveRoutes()))
+	setReady(true)
+
+	return lambda.Start(func(ctx context.Context, event json.RawMessage) (interface{}, error) {
+		if p, ok := lambda.ParseProxyRequest(event); ok {
+			r, err := p.HTTPRequest(ctx)
+			if err != nil {
+				return lambda.ProxyResponse{StatusCode: http.StatusBadRequest}, nil
+			}
+			if !isSlackEvent(handler, r) {
+				defer waitInflight(ctx)
+				return lambda.Serve(handler, r, p), nil
+			}
+			//answer Slack right away, the work its request started is replayed asynchronously
+			deferWork, workDeferred = true, false
+			resp := lambda.Serve(handler, r, p)
+			deferWork = false
+			if workDeferred {
+				if err := invokeSelf(ctx, lambdaJob{Replay: event}); err != nil {
+					logger.Errorw("Error invoking the function to do the work of a request, doing it before answering", zap.Error(err))
+					replay(ctx, handler, p)
+				}
+			}
+			return resp, nil
+		}
+
+		var job lambdaJob
+		if err := json.Unmarshal(event, &job); err != nil {
+			return nil, fmt.Errorf("unknown invocation %s", event)
+		}
+		if len(job.Replay) > 0 {
+			if p, ok := lambda.ParseProxyRequest(job.Replay); ok {
+				replay(ctx, handler, p)
+			}
+			return nil, nil
+		}
+		if len(job.Job) == 0 {
+			return nil, fmt.Errorf("unknown invocation %s", event)
+		}
+		//the function is frozen once it answers, finish what jobs left running first
+		defer waitInflight(ctx)
+		if job.Job == watchJob {
+			pollChanges()
+			return job, nil
+		}
+		if err := runScheduled(job.Job, job.Channel); err != nil {
+			logger.Errorw("Error running "+job.Job, zap.Error(err))
+			return nil, err
+		}
+		return job, nil
+	})
+}
+
+//isSlackEvent reports whether r is Slack delivering an event or interaction, answered
+//in Slack's 3 seconds only when the work it starts is left to a replay
+func isSlackEvent(router *mux.Router, r *http.Request) bool {
+	var match mux.RouteMatch
+	return router.Match(r, &match) && slackEventRoutes[match.Route.GetName()]
+}
+
+//replay answers p with handler again, this time doing the work it starts, and waits
+//for the work to be done. The response was already sent and is dropped.
+func replay(ctx context.Context, handler http.Handler, p lambda.ProxyRequest) {
+	r, err := p.HTTPRequest(ctx)
+	if err != nil {
+		return
+	}
+	replaying = true
+	defer func() { replaying = false }()
+	lambda.Serve(handler, r, p)
+	waitInflight(ctx)
+}
+
+//invokeSelf invokes the running function with job without waiting for it to run
+func invokeSelf(ctx context.Context, job lambdaJob) error {
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = awslambda.New(sess).InvokeWithContext(ctx, &awslambda.InvokeInput{
+		FunctionName:   aws.String(os.Getenv("AWS_LAMBDA_FUNCTION_NAME")),
+		InvocationType: aws.String(awslambda.InvocationTypeEvent),
+		Payload:        payload,
+	})
+	return err
+}
+
+//waitInflight waits for the work safeGo started until ctx is done
+func waitInflight(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		logger.Warn("Invocation ended with work still running")
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultWindowDays = 30
+)
+
+//countVotes counts poll votes and rsvps per truck since since
+func countVotes(since time.Time) (map[string]int, error) {
+	counts := make(map[string]int)
+
+	polls, err := kv.List(store.Polls)
+	if err != nil {
+		return nil, err
+	}
+	for _, data := range polls {
+		var p Poll
+		if err := json.Unmarshal(data, &p); err != nil || p.Closes.Before(since) {
+			continue
+		}
+		for id, n := range p.tally() {
+			counts[id] += n
+		}
+	}
+
+	rsvps, err := kv.List(store.RSVPs)
+	if err != nil {
+		return nil, err
+	}
+	from := since.Format(dayLayout)
+	for _, data := range rsvps {
+		var r RSVP
+		if err := json.Unmarshal(data, &r); err != nil || r.Day < from {
+			continue
+		}
+		for id, users := range r.Going {
+			counts[id] += len(users)
+		}
+	}
+	return counts, nil
+}
+
+//leaderboardLine renders a ranked truck with its rating and categories
+func leaderboardLine(rank int, t ArchivedTruck, count int, unit string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%v. *<%s|%s>* %v %s", rank, fmt.Sprintf(truckURL, t.ID), t.Name, count, unit))
+	if truck, err := proxy.GetTruck(t.ID); err == nil {
+		sb.WriteString(fmt.Sprintf(" · %s (%.1f)", render.Stars(truck.Rating), truck.Rating))
+	}
+	for _, fc := range t.FoodCategories {
+		sb.WriteString(" " + render.Emoji(fc))
+	}
+	return sb.String()
+}
+
+//postLeaderboard posts the most booked and most voted trucks of the last days into channel
+func postLeaderboard(channel, args string) {
+	locale := localeFor(channel)
+	days, err := commands.ParseWindow(args, defaultWindowDays)
+	if err != nil {
+		slackAPI().PostMessage(channel, slack.MsgOptionText(i18n.T(locale, i18n.LeaderboardUsage), false))
+		return
+	}
+	since := time.Now().In(tz).AddDate(0, 0, -days)
+	archived, err := archivedSince(since)
+	if err != nil {
+		logger.Errorw("Error reading archive", zap.Error(err))
+		slackAPI().PostMessage(channel, slack.MsgOptionText(i18n.T(locale, i18n.ArchiveReadFailed), false))
+		return
+	}
+	votes, err := countVotes(since)
+	if err != nil {
+		logger.Errorw("Error counting votes", zap.Error(err))
+	}
+
+	booked := countTrucks(archived)
+	trucks := make(map[string]ArchivedTruck, len(booked))
+	var mostBooked []string
+	for i, c := range booked {
+		trucks[c.Truck.ID] = c.Truck
+		if i < topTrucks {
+			mostBooked = append(mostBooked, leaderboardLine(i+1, c.Truck, c.Count, i18n.T(locale, i18n.LeaderboardDays)))
+		}
+	}
+
+	voted := make([]truckCount, 0, len(votes))
+	for id, n := range votes {
+		t, ok := trucks[id]
+		if !ok {
+			t = ArchivedTruck{ID: id, Name: id}
+		}
+		voted = append(voted, truckCount{Truck: t, Count: n})
+	}
+	sort.Slice(voted, func(i, j int) bool {
+		if voted[i].Count != voted[j].Count {
+			return voted[i].Count > voted[j].Count
+		}
+		return voted[i].Truck.Name < voted[j].Truck.Name
+	})
+	var mostVoted []string
+	for i, c := range voted {
+		if i == topTrucks {
+			break
+		}
+		mostVoted = append(mostVoted, leaderboardLine(i+1, c.Truck, c.Count, i18n.T(locale, i18n.LeaderboardVotes)))
+	}
+
+	if len(mostBooked) == 0 {
+		mostBooked = []string{i18n.T(locale, i18n.LeaderboardNoBookings)}
+	}
+	if len(mostVoted) == 0 {
+		mostVoted = []string{i18n.T(locale, i18n.LeaderboardNoVotes)}
+	}
+	msg := slack.NewBlockMessage(
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", i18n.T(locale, i18n.LeaderboardTitle, days), false, false), nil, nil),
+		slack.NewDividerBlock(),
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", i18n.T(locale, i18n.LeaderboardMostBooked)+"\n"+strings.Join(mostBooked, "\n"), false, false), nil, nil),
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", i18n.T(locale, i18n.LeaderboardMostVoted)+"\n"+strings.Join(mostVoted, "\n"), false, false), nil, nil),
+	)
+	msg.Text = i18n.T(locale, i18n.LeaderboardText, days)
+	if len(booked) > 0 {
+		msg.Text += i18n.T(locale, i18n.LeaderboardLeads, booked[0].Truck.Name)
+	}
+	if _, _, err := slackAPI().PostMessage(channel, slack.MsgOptionText(msg.Text, false), slack.MsgOptionBlocks(msg.Blocks.BlockSet...)); err != nil {
+		logger.Errorw("Error posting leaderboard", zap.Error(err))
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"github.com/slack-go/slack/slackevents"
+	"go.uber.org/zap"
+)
+
+//localeFor returns the language posts in channel are in, its schedule's or else LOCALE
+func localeFor(channel string) string {
+	schedulesMu.RLock()
+	defer schedulesMu.RUnlock()
+
+	if sch, ok := schedules[channel]; ok && len(sch.Locale) > 0 {
+		return sch.Locale
+	}
+	return locale
+}
+
+//localeOf returns the language user is answered in within channel, theirs or else the
+//channel's
+func localeOf(user, channel string) string {
+	data, err := kv.Get(store.Locales, user)
+	if err != nil {
+		if err != store.ErrNotFound {
+			logger.Warnw("Error loading locale of "+user, zap.Error(err))
+		}
+		return localeFor(channel)
+	}
+	return string(data)
+}
+
+//tr returns the message key in the language of whoever mentioned the bot
+func tr(event *slackevents.AppMentionEvent, key i18n.Key, args ...interface{}) string {
+	return i18n.T(localeOf(event.User, event.Channel), key, args...)
+}
+
+//dayWord returns today or tomorrow in locale
+func dayWord(locale, day string) string {
+	if day == tomorrow {
+		return i18n.T(locale, i18n.Tomorrow)
+	}
+	return i18n.T(locale, i18n.Today)
+}
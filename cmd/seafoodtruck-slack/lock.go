@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-redis/redis"
+	"go.uber.org/zap"
+)
+
+const (
+	lockKeyPrefix  = "seafoodtruck-slack:cron"
+	defaultLockTTL = 30 * time.Minute
+)
+
+//Locker guards scheduled runs so only one replica fires each of them
+type Locker interface {
+	//Acquire reports whether the caller obtained key, which expires after ttl
+	Acquire(key string, ttl time.Duration) (bool, error)
+}
+
+//NewLocker returns a Redis backed locker for redisURL, or a locker that always
+//succeeds when redisURL is empty and the bot runs as a single replica
+func NewLocker(redisURL string) (Locker, error) {
+	if len(redisURL) == 0 {
+		return noopLocker{}, nil
+	}
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, err
+	}
+	owner, _ := os.Hostname()
+	return &redisLocker{client: redis.NewClient(opts), owner: owner}, nil
+}
+
+type noopLocker struct{}
+
+func (noopLocker) Acquire(key string, ttl time.Duration) (bool, error) {
+	return true, nil
+}
+
+type redisLocker struct {
+	client *redis.Client
+	owner  string
+}
+
+func (l *redisLocker) Acquire(key string, ttl time.Duration) (bool, error) {
+	return l.client.SetNX(key, l.owner, ttl).Result()
+}
+
+//withLock wraps a scheduled run so it only fires on the replica that wins the lock
+//for this minute. If the lock backend is unreachable the run proceeds, a duplicate
+//post being preferable to a missed one.
+func withLock(name, channel string, run func()) func() {
+	return func() {
+		at := time.Now().In(tz).Truncate(time.Minute)
+		key := fmt.Sprintf("%s:%s:%s:%s", lockKeyPrefix, name, channel, at.Format(time.RFC3339))
+
+		ok, err := locker.Acquire(key, defaultLockTTL)
+		if err != nil {
+			logger.Warnw("Error acquiring lock, running anyway", "key", key, zap.Error(err))
+			ok = true
+		}
+		if !ok {
+			logger.Infof("Skipping %s for channel %s, another replica holds %s", name, channel, key)
+			return
+		}
+		run()
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+//appManifest is a Slack App Manifest, https://api.slack.com/reference/manifests
+type appManifest struct {
+	DisplayInformation struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	} `json:"display_information"`
+	Features struct {
+		BotUser struct {
+			DisplayName  string `json:"display_name"`
+			AlwaysOnline bool   `json:"always_online"`
+		} `json:"bot_user"`
+	} `json:"features"`
+	OAuthConfig struct {
+		Scopes struct {
+			Bot []string `json:"bot"`
+		} `json:"scopes"`
+	} `json:"oauth_config"`
+	Settings struct {
+		EventSubscriptions struct {
+			RequestURL string   `json:"request_url,omitempty"`
+			BotEvents  []string `json:"bot_events"`
+		} `json:"event_subscriptions"`
+		Interactivity struct {
+			IsEnabled  bool   `json:"is_enabled"`
+			RequestURL string `json:"request_url,omitempty"`
+		} `json:"interactivity"`
+		OrgDeployEnabled     bool `json:"org_deploy_enabled"`
+		SocketModeEnabled    bool `json:"socket_mode_enabled"`
+		TokenRotationEnabled bool `json:"token_rotation_enabled"`
+	} `json:"settings"`
+}
+
+//newAppManifest returns the manifest of the app served at baseURL named name, asking
+//for the scopes the features enabled in c need
+func newAppManifest(c Config, baseURL, name string) appManifest {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	var m appManifest
+	m.DisplayInformation.Name = name
+	m.DisplayInformation.Description = "Posts the Seattle food trucks booked at your locations"
+	m.Features.BotUser.DisplayName = name
+	m.Features.BotUser.AlwaysOnline = true
+
+	//mentions are answered and schedules, polls and alerts posted in every setup, and
+	//validate-config looks up the channels of the schedules
+	scopes := []string{"app_mentions:read", "channels:read", "chat:write"}
+	if c.DiscoverChannels {
+		//private channels the bot is a member of are listed to post into too
+		scopes = append(scopes, "groups:read")
+	}
+	m.OAuthConfig.Scopes.Bot = scopes
+
+	m.Settings.EventSubscriptions.BotEvents = []string{"app_mention"}
+	//rsvp and poll buttons are on every schedule post
+	m.Settings.Interactivity.IsEnabled = true
+	//with Socket Mode Slack sends events and clicks over the bot's connection
+	m.Settings.SocketModeEnabled = len(c.AppToken) > 0
+	if !m.Settings.SocketModeEnabled {
+		m.Settings.EventSubscriptions.RequestURL = baseURL + "/"
+		m.Settings.Interactivity.RequestURL = baseURL + "/interactions"
+	}
+	return m
+}
+
+//writeManifest writes the manifest of the app served at baseURL as JSON, which Slack
+//accepts when creating an app from a manifest
+func writeManifest(w io.Writer, c Config, baseURL, name string) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(newAppManifest(c, baseURL, name))
+}
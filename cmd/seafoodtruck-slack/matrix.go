@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"html"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/internal/matrix"
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+)
+
+//matrixChat is the CHAT posting schedules as the Matrix user of MATRIX_ACCESS_TOKEN,
+//schedule channels being room ids or #aliases the bot joins
+const matrixChat = "matrix"
+
+var (
+	matrixHomeserverURL string
+	matrixAccessToken   string
+	matrixOnce          sync.Once
+	matrixBot           *matrix.Client
+)
+
+//matrixClient returns the client of the bot user on MATRIX_HOMESERVER_URL
+func matrixClient() *matrix.Client {
+	matrixOnce.Do(func() {
+		matrixBot = matrix.New(matrixHomeserverURL, matrixAccessToken, &http.Client{Timeout: 10 * time.Second})
+	})
+	return matrixBot
+}
+
+//postMatrixEvents sends the events on day at forLocations into the Matrix room channel,
+//a message per location
+func postMatrixEvents(channel, day string, forLocations []string) error {
+	all, err := eventsAt(day, forLocations)
+	if err != nil {
+		return err
+	}
+	roomID, err := matrixClient().JoinRoom(context.TODO(), channel)
+	if err != nil {
+		return err
+	}
+	locale, order := localeFor(channel), orderFor(channel)
+	for _, le := range all {
+		text := strings.Replace(i18n.T(locale, i18n.NoTrucks, le.loc.Name, dayWord(locale, day), dayOf(day).Format("Mon Jan 2")), "*", "", -1)
+		msg := matrix.Notice(text, html.EscapeString(text))
+		if len(le.events) > 0 {
+			opts := render.Options{Order: order, Locale: locale, Distance: distanceFrom(channel, le.loc), New: newTrucksAt(le.loc.ID, dayOf(day).Format(dayLayout), le.events)}
+			msg = matrix.Notice(render.Matrix(le.loc, le.events, proxy.GetTruck, tz, opts))
+		}
+		if err := matrixClient().Send(context.TODO(), roomID, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
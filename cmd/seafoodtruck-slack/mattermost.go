@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/internal/mattermost"
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+//mattermostChat is the CHAT posting schedules through a Mattermost incoming webhook
+const mattermostChat = "mattermost"
+
+var (
+	mattermostClient = &http.Client{Timeout: 10 * time.Second}
+	//mattermostWebhookURL is the incoming webhook schedules are posted through and
+	//mattermostToken the token of the slash command
+	mattermostWebhookURL string
+	mattermostToken      string
+)
+
+//mattermostEvents renders the events on day at forLocations as one Mattermost
+//message: the Markdown header of each location followed by its truck attachments
+func mattermostEvents(day string, forLocations []string, order, locale string) (string, []slack.Attachment, error) {
+	all, err := eventsAt(day, forLocations)
+	if err != nil {
+		return "", nil, err
+	}
+	var texts []string
+	var attachments []slack.Attachment
+	for i, le := range all {
+		if len(le.events) == 0 {
+			texts = append(texts, i18n.T(locale, i18n.NoTrucks, le.loc.Name, dayWord(locale, day), dayOf(day).Format("Mon Jan 2")))
+			continue
+		}
+		opts := render.Options{Order: order, Locale: locale, New: newTrucksAt(le.loc.ID, dayOf(day).Format(dayLayout), le.events)}
+		text, as := render.Attachments(le.loc, le.events, proxy.GetTruck, tz, opts)
+		//attachments follow the whole text, so only the last location can have its own
+		if i < len(all)-1 && len(as) > 0 {
+			text, as = text+"\n"+attachmentsText(as), nil
+		}
+		texts = append(texts, text)
+		attachments = append(attachments, as...)
+	}
+	return strings.Join(texts, "\n\n"), attachments, nil
+}
+
+//attachmentsText flattens attachments into Markdown lines, for when they can't follow
+//the text they belong to
+func attachmentsText(attachments []slack.Attachment) string {
+	var lines []string
+	for _, a := range attachments {
+		if len(a.Pretext) > 0 {
+			lines = append(lines, a.Pretext)
+		}
+		lines = append(lines, "- ["+a.Title+"]("+a.TitleLink+") "+strings.Replace(a.Text, "\n", " · ", -1))
+	}
+	return strings.Join(lines, "\n")
+}
+
+//postMattermostEvents posts the events on day at forLocations into the Mattermost
+//channel named channel through the incoming webhook
+func postMattermostEvents(channel, day string, forLocations []string) error {
+	text, attachments, err := mattermostEvents(day, forLocations, orderFor(channel), localeFor(channel))
+	if err != nil {
+		return err
+	}
+	msg := mattermost.Message{Channel: channel, Text: text, Attachments: attachments}
+	return mattermost.Post(context.TODO(), mattermostClient, mattermostWebhookURL, msg)
+}
+
+//mattermostCommandHandler answers the bot's Mattermost slash command with the events,
+//as in /trucks find events for today, or help
+func mattermostCommandHandler(w http.ResponseWriter, r *http.Request) {
+	sc, err := mattermost.ParseSlashCommand(r)
+	if err != nil {
+		http.Error(w, "Error reading slash command", http.StatusBadRequest)
+		return
+	}
+	if err := sc.Verify(mattermostToken); err != nil {
+		logger.Warnw("Rejecting Mattermost slash command", zap.Error(err))
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+	locale := localeFor(sc.ChannelName)
+	resp := mattermost.Response{ResponseType: mattermost.Ephemeral}
+	cmd := commands.Parse(sc.Text)
+	switch cmd.Name {
+	case commands.FindEvents:
+		forLocations := locationsFor(sc.ChannelName)
+		day, at, by := commands.FindEventsArgs(cmd.Args)
+		if at != nil {
+			forLocations = expandLocations(at)
+		}
+		order, ok := render.ParseOrder(by)
+		if !ok {
+			resp.Text = i18n.T(locale, i18n.KnownOrders, strings.Replace(render.KnownOrders(), ",", ", ", -1))
+			break
+		}
+		if len(order) == 0 {
+			order = orderFor(sc.ChannelName)
+		}
+		if len(forLocations) == 0 {
+			resp.Text = i18n.T(locale, i18n.ErrNoLocations)
+			break
+		}
+		text, attachments, err := mattermostEvents(day, forLocations, order, locale)
+		if err != nil {
+			logger.Errorw("Error getting events for Mattermost", zap.Error(err))
+			resp.Text = i18n.T(locale, i18n.ErrEvents)
+			break
+		}
+		resp = mattermost.Response{ResponseType: mattermost.InChannel, Text: text, Attachments: attachments}
+	case commands.Help:
+		resp.Text = strings.Join([]string{i18n.T(locale, i18n.HelpTitle),
+			sc.Command + " " + i18n.T(locale, i18n.HelpFindEvents, commands.FindEvents),
+			sc.Command + " " + commands.Help,
+		}, "\n")
+	default:
+		resp.Text = i18n.T(locale, i18n.UnknownCommand)
+	}
+	w.Header().Set(contentTypeHeader, "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Errorw("Error answering Mattermost slash command", zap.Error(err))
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	s "github.com/appsbyram/pkg/http"
+	"go.uber.org/zap"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+type contextKey int
+
+const requestInfoKey contextKey = iota
+
+//requestInfo carries the request scoped logger, handlers fill in what the access log
+//can't see from the outside
+type requestInfo struct {
+	logger    *zap.SugaredLogger
+	eventType string
+}
+
+//statusRecorder remembers the status written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
+
+//requestLogger returns the logger of the request of ctx, tagged with its request id
+func requestLogger(ctx context.Context) *zap.SugaredLogger {
+	if info, ok := ctx.Value(requestInfoKey).(*requestInfo); ok {
+		return info.logger
+	}
+	return logger
+}
+
+//setEventType records the Slack event type handled by the request of ctx for the access log
+func setEventType(ctx context.Context, eventType string) {
+	if info, ok := ctx.Value(requestInfoKey).(*requestInfo); ok {
+		info.eventType = eventType
+	}
+}
+
+//withRequestLogging assigns each request an id, taken from X-Request-ID when the caller
+//sent one, and logs method, path, status and duration once the route handled it
+func withRequestLogging(route s.Route) http.HandlerFunc {
+	next := route.HandlerFunc
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if len(id) == 0 {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+
+		info := &requestInfo{logger: logger.With("request_id", id)}
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next(rec, r.WithContext(context.WithValue(r.Context(), requestInfoKey, info)))
+
+		fields := []interface{}{
+			"method", r.Method,
+			"path", r.URL.Path,
+			"route", route.Name,
+			"status", rec.status,
+			"duration", time.Since(start),
+		}
+		if len(info.eventType) > 0 {
+			fields = append(fields, "slack_event", info.eventType)
+		}
+		info.logger.Infow("Handled request", fields...)
+	}
+}
+
+//withMiddleware wraps the handler of every route in the shared middleware,
+//throttling the public ones when a rate limit is configured
+func withMiddleware(routes s.Routes) s.Routes {
+	var limiter *ipLimiter
+	if rateLimit > 0 {
+		limiter = newIPLimiter(rateLimit, rateBurst)
+	}
+	wrapped := make(s.Routes, 0, len(routes))
+	for _, route := range routes {
+		if doc, ok := routeDocs[route.Name]; ok {
+			route.HandlerFunc = withValidation(doc, route.HandlerFunc)
+		}
+		if limiter != nil && publicRoutes[route.Name] {
+			route.HandlerFunc = withRateLimit(limiter, route.HandlerFunc)
+		}
+		route.HandlerFunc = withRecovery(route.HandlerFunc)
+		route.HandlerFunc = withRequestLogging(route)
+		wrapped = append(wrapped, route)
+	}
+	return wrapped
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/mqtt"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMQTTScheduleTopic = "seafoodtruck/schedule"
+	defaultMQTTChangeTopic   = "seafoodtruck/changes"
+	mqttTimeout              = 10 * time.Second
+)
+
+var (
+	mqttURL           string
+	mqttScheduleTopic string
+	mqttChangeTopic   string
+)
+
+//mqttTopicEscaper keeps channels and location ids from adding levels or wildcards to
+//the topics they're appended to
+var mqttTopicEscaper = strings.NewReplacer("/", "_", "+", "_", "#", "_")
+
+//mqttClient returns a client of the broker at MQTT_URL, identified by the host name so
+//replicas don't kick each other off
+func mqttClient() (*mqtt.Client, error) {
+	host, _ := os.Hostname()
+	return mqtt.New(mqttURL, "seafoodtruck-"+host, mqttTimeout)
+}
+
+//publishScheduleMQTT publishes the schedule of channel as a retained message to
+//MQTT_SCHEDULE_TOPIC/<channel> in the background, so displays subscribing later still
+//get the day's trucks
+func publishScheduleMQTT(channel, day string, forLocations []string) {
+	if len(mqttURL) == 0 {
+		return
+	}
+	safeGo("", func() {
+		p, err := webhookSchedulePayload(channel, day, forLocations)
+		if err != nil {
+			logger.Errorw("Error building MQTT schedule", zap.Error(err))
+			return
+		}
+		p.Sent = time.Now().UTC()
+		payload, err := json.Marshal(p)
+		if err != nil {
+			logger.Errorw("Error encoding MQTT schedule", zap.Error(err))
+			return
+		}
+		publishMQTT(mqtt.Message{Topic: mqttScheduleTopic + "/" + mqttTopicEscaper.Replace(channel), Payload: payload, QoS: 1, Retain: true})
+	})
+}
+
+//publishChangesMQTT publishes each of changes to MQTT_CHANGE_TOPIC/<location id> in
+//the background
+func publishChangesMQTT(changes []ScheduleChange) {
+	if len(mqttURL) == 0 {
+		return
+	}
+	safeGo("", func() {
+		var msgs []mqtt.Message
+		for i := range changes {
+			p := WebhookPayload{Type: webhookChange, Date: changes[i].Start.In(tz).Format(dayLayout), Sent: time.Now().UTC(), Change: &changes[i]}
+			payload, err := json.Marshal(p)
+			if err != nil {
+				logger.Errorw("Error encoding MQTT change", zap.Error(err))
+				continue
+			}
+			msgs = append(msgs, mqtt.Message{Topic: mqttChangeTopic + "/" + mqttTopicEscaper.Replace(changes[i].Location.ID), Payload: payload, QoS: 1})
+		}
+		publishMQTT(msgs...)
+	})
+}
+
+//publishMQTT publishes msgs to the broker of MQTT_URL, retrying a few times
+func publishMQTT(msgs ...mqtt.Message) {
+	if len(msgs) == 0 {
+		return
+	}
+	client, err := mqttClient()
+	if err != nil {
+		logger.Errorw("Error creating MQTT client", zap.Error(err))
+		return
+	}
+	backoff := retryBackoff
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), mqttTimeout)
+		err = client.Publish(ctx, msgs...)
+		cancel()
+		if err == nil {
+			return
+		}
+		logger.Warnw("Error publishing to MQTT", "topic", msgs[0].Topic, "attempt", attempt, zap.Error(err))
+		if attempt < webhookAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+}
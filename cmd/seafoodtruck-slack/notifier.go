@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+)
+
+//slackChat is the chat the bot posts into unless CHAT names another notifier
+const slackChat = "slack"
+
+//notifier posts the events booked on day at forLocations into channel of a chat other
+//than Slack. Chats without blocks get no RSVP buttons, threads or weekly previews.
+type notifier func(channel, day string, forLocations []string) error
+
+//notifiers are the chats besides Slack schedules can post into, keyed by CHAT
+var notifiers = map[string]notifier{
+	mattermostChat:   postMattermostEvents,
+	telegramChat:     postTelegramEvents,
+	googleChat:       postGoogleChatEvents,
+	slackWebhookChat: postSlackWebhookEvents,
+	matrixChat:       postMatrixEvents,
+}
+
+//isChat reports whether name is slackChat or has a notifier
+func isChat(name string) bool {
+	_, ok := notifiers[name]
+	return ok || name == slackChat
+}
+
+//knownChats returns the chats schedules can post into comma separated
+func knownChats() string {
+	chats := []string{slackChat}
+	for name := range notifiers {
+		chats = append(chats, name)
+	}
+	sort.Strings(chats[1:])
+	return strings.Join(chats, ",")
+}
+
+//locationEvents are the events booked at a location
+type locationEvents struct {
+	loc    seattlefoodtruck.Location
+	events []seattlefoodtruck.Event
+}
+
+//eventsAt returns the events booked on day at each of forLocations in order
+func eventsAt(day string, forLocations []string) ([]locationEvents, error) {
+	var all []locationEvents
+	for _, id := range forLocations {
+		loc, err := proxy.GetLocation(id)
+		if err != nil {
+			return nil, fmt.Errorf("getting location %s: %v", id, err)
+		}
+		events, err := proxy.GetEvents(id, day)
+		if err != nil {
+			return nil, fmt.Errorf("getting events at %s: %v", id, err)
+		}
+		archiveEvents(loc, dayOf(day).Format(dayLayout), events)
+		all = append(all, locationEvents{loc, events})
+	}
+	return all, nil
+}
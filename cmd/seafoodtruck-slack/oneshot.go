@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	//runCron posts the schedules from the in-process cron, turned off when an external
+	//scheduler runs the post command instead
+	runCron = true
+	//oneShot is set by the post command, which exits once it posted
+	oneShot bool
+)
+
+//runScheduled makes the post job kind makes for every schedule, or only for channel
+//when set, once. Posts are retried, skip holidays and take the lock like the cron's
+//do so replicas of an external scheduler don't post twice. The error counts the
+//schedules whose post failed.
+func runScheduled(kind, channel string) error {
+	switch kind {
+	case postsJob, weeklyJob, eveningJob:
+	default:
+		return fmt.Errorf("unknown job %s, use %s, %s or %s", kind, postsJob, weeklyJob, eveningJob)
+	}
+
+	schedulesMu.RLock()
+	var due []Schedule
+	for ch, sch := range schedules {
+		if len(channel) == 0 || ch == channel {
+			sch.Locations = expandLocations(sch.Locations)
+			due = append(due, sch)
+		}
+	}
+	schedulesMu.RUnlock()
+	if len(due) == 0 && len(channel) > 0 {
+		return fmt.Errorf("channel %s has no schedule", channel)
+	}
+	if len(due) == 0 {
+		return errors.New("no schedules configured")
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].Channel < due[j].Channel })
+
+	failed := 0
+	for _, sch := range due {
+		if len(sch.Locations) == 0 {
+			logger.Warnf("Skipping schedule for channel %s without locations", sch.Channel)
+			continue
+		}
+		var err error
+		withLock(kind, sch.Channel, func() {
+			err = retry(kind, sch.Channel, scheduledPost(kind, sch))
+		})()
+		if err != nil {
+			failed++
+			continue
+		}
+		logger.Infof("Ran %s for channel %s", kind, sch.Channel)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%s failed for %v of %v channel(s)", kind, failed, len(due))
+	}
+	return nil
+}
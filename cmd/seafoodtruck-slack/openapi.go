@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	s "github.com/appsbyram/pkg/http"
+	"github.com/appsbyram/seafoodtruck-slack/version"
+	"github.com/gorilla/mux"
+)
+
+//paramDoc documents a parameter of a REST route
+type paramDoc struct {
+	Name     string
+	In       string
+	Type     string
+	Required bool
+	Enum     []string
+	Help     string
+}
+
+//routeDoc documents a REST route, keyed by route name in routeDocs
+type routeDoc struct {
+	Summary string
+	Params  []paramDoc
+	//Result describes the json returned on success
+	Result string
+	//ContentType of the result, json unless set
+	ContentType string
+}
+
+var routeDocs = map[string]routeDoc{
+	"HomeGet": {Summary: "Version of the bot", Result: "object"},
+	"EventsGet": {Summary: "Events booked at a location", Result: "array", Params: []paramDoc{
+		{Name: "id", In: "query", Type: "string", Required: true, Help: "location id"},
+		{Name: "day", In: "query", Type: "string", Enum: []string{today, tomorrow}},
+	}},
+	"LocationsGet": {Summary: "Locations matching a query", Result: "array", Params: []paramDoc{
+		{Name: "query", In: "query", Type: "string"},
+		{Name: "neighborhood", In: "query", Type: "string", Help: "neighborhood id or name"},
+	}},
+	"LocationGet": {Summary: "A location", Result: "object", Params: []paramDoc{
+		{Name: "id", In: "path", Type: "string", Required: true},
+	}},
+	"TrucksGet": {Summary: "Active trucks by category and name", Result: "array", Params: []paramDoc{
+		{Name: "category", In: "query", Type: "string"},
+		{Name: "query", In: "query", Type: "string", Help: "part of the truck name"},
+	}},
+	"TruckGet": {Summary: "A truck with menu, photos and ratings", Result: "object", Params: []paramDoc{
+		{Name: "id", In: "path", Type: "string", Required: true},
+	}},
+	"NeighborhoodsGet": {Summary: "Neighborhoods with their coordinates", Result: "array"},
+	"FeedGet": {Summary: "Upcoming events at the configured location groups by day", Result: "array", Params: []paramDoc{
+		{Name: "days", In: "query", Type: "integer", Help: "days to return, defaults to feed-days"},
+		{Name: "group", In: "query", Type: "string", Help: "only the locations of this group"},
+	}},
+	"TriggerBookingsGet": {Summary: "Upcoming bookings latest first with stable ids, for Zapier and IFTTT polling triggers", Result: "array", Params: []paramDoc{
+		{Name: "days", In: "query", Type: "integer", Help: "days to return, defaults to feed-days"},
+		{Name: "group", In: "query", Type: "string", Help: "only the locations of this group"},
+		{Name: "category", In: "query", Type: "string", Help: "only trucks with a food category containing this, e.g. taco"},
+		{Name: "truck", In: "query", Type: "string", Help: "only trucks whose name contains this"},
+		{Name: "limit", In: "query", Type: "integer", Help: "most bookings returned, 50 unless set, at most 100"},
+	}},
+	"BriefingGet": {Summary: "Today's trucks as an Alexa flash briefing feed", Result: "array", Params: []paramDoc{
+		{Name: "group", In: "query", Type: "string", Help: "only the locations of this group"},
+		{Name: "locale", In: "query", Type: "string", Help: "language read out, defaults to the locale setting"},
+	}},
+	"AtomGet": {Summary: "Schedules posted over the last two weeks as an atom feed", Result: "string", ContentType: "application/atom+xml"},
+	"ExportGet": {Summary: "Archived bookings as csv", Result: "string", ContentType: "text/csv", Params: []paramDoc{
+		{Name: "from", In: "query", Type: "string", Help: "first day, 2006-01-02, defaults to 30 days ago"},
+		{Name: "to", In: "query", Type: "string", Help: "last day, 2006-01-02, defaults to today"},
+		{Name: "location", In: "query", Type: "string", Help: "only this location id"},
+	}},
+	"GraphQLGet": {Summary: "Result of a graphql query over locations, events, bookings and trucks", Result: "object", Params: []paramDoc{
+		{Name: "query", In: "query", Type: "string", Required: true},
+		{Name: "operationName", In: "query", Type: "string"},
+	}},
+	"GraphQLPost": {Summary: "Result of a graphql query posted as json", Result: "object"},
+	"ReadyGet":    {Summary: "Readiness of the bot, 503 while starting or when the store doesn't answer", Result: "object"},
+	"CalendarGet": {Summary: "Upcoming events at the configured location groups as iCalendar", Result: "string", ContentType: "text/calendar", Params: []paramDoc{
+		{Name: "days", In: "query", Type: "integer", Help: "days to return, defaults to feed-days"},
+		{Name: "group", In: "query", Type: "string", Help: "only the locations of this group"},
+	}},
+}
+
+//openAPISpec builds an OpenAPI 3 document of the documented routes
+func openAPISpec(routes s.Routes) map[string]interface{} {
+	paths := map[string]interface{}{
+		"/health": map[string]interface{}{
+			"get": map[string]interface{}{
+				"summary":   "Health of the bot",
+				"responses": map[string]interface{}{"200": map[string]interface{}{"description": "UP"}},
+			},
+		},
+	}
+	for _, route := range routes {
+		doc, ok := routeDocs[route.Name]
+		if !ok {
+			continue
+		}
+		var params []interface{}
+		for _, p := range doc.Params {
+			schema := map[string]interface{}{"type": p.Type}
+			if len(p.Enum) > 0 {
+				schema["enum"] = p.Enum
+			}
+			param := map[string]interface{}{"name": p.Name, "in": p.In, "required": p.Required, "schema": schema}
+			if len(p.Help) > 0 {
+				param["description"] = p.Help
+			}
+			params = append(params, param)
+		}
+		contentType := doc.ContentType
+		if len(contentType) == 0 {
+			contentType = "application/json"
+		}
+		op := map[string]interface{}{
+			"operationId": route.Name,
+			"summary":     doc.Summary,
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": doc.Summary,
+					"content": map[string]interface{}{
+						contentType: map[string]interface{}{"schema": map[string]interface{}{"type": doc.Result}},
+					},
+				},
+				"default": map[string]interface{}{
+					"description": "error",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{"schema": map[string]interface{}{"$ref": "#/components/schemas/APIError"}},
+					},
+				},
+			},
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+		ops, ok := paths[route.Pattern].(map[string]interface{})
+		if !ok {
+			ops = make(map[string]interface{})
+			paths[route.Pattern] = ops
+		}
+		ops[strings.ToLower(route.Method)] = op
+	}
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "seafoodtruck-slack",
+			"version": version.Version,
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"APIError": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"status":  map[string]interface{}{"type": "integer"},
+						"message": map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+}
+
+//openAPIHandler serves the OpenAPI document of routes
+func openAPIHandler(routes s.Routes) http.HandlerFunc {
+	spec := openAPISpec(routes)
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.NewPayload().WriteResponse(s.ContentTypeJSON, http.StatusOK, &spec, w)
+	}
+}
+
+//validateParams checks the parameters of r against the documentation of its route
+func validateParams(doc routeDoc, r *http.Request) error {
+	vars := mux.Vars(r)
+	for _, p := range doc.Params {
+		var value string
+		switch p.In {
+		case "path":
+			value = vars[p.Name]
+		default:
+			value = r.URL.Query().Get(p.Name)
+		}
+		if len(value) == 0 {
+			if p.Required {
+				return fmt.Errorf("%s is required", p.Name)
+			}
+			continue
+		}
+		switch p.Type {
+		case "integer":
+			if _, err := strconv.Atoi(value); err != nil {
+				return fmt.Errorf("%s must be an integer", p.Name)
+			}
+		case "boolean":
+			if _, err := strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("%s must be a boolean", p.Name)
+			}
+		}
+		if len(p.Enum) > 0 && !contains(p.Enum, value) {
+			return fmt.Errorf("%s must be one of %s", p.Name, strings.Join(p.Enum, ", "))
+		}
+	}
+	return nil
+}
+
+//withValidation answers 400 when a request doesn't match the documented parameters
+func withValidation(doc routeDoc, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := validateParams(doc, r); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		next(w, r)
+	}
+}
+
+func contains(values []string, v string) bool {
+	for _, candidate := range values {
+		if candidate == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultOpsUpstreamErrors = 5
+	defaultOpsCheckInterval  = time.Minute
+	//opsMissedGrace is how late a cron run may be before it counts as missed
+	opsMissedGrace = 5 * time.Minute
+)
+
+var (
+	opsChannel        string
+	opsWebhookURL     string
+	opsUpstreamErrors int
+	opsCheckInterval  time.Duration
+)
+
+//slackAuthErrors are the errors Slack answers when the bot token stopped working
+var slackAuthErrors = []string{"invalid_auth", "not_authed", "token_revoked", "token_expired", "account_inactive"}
+
+//opsState tracks the failure conditions the ops channel is alerted about, each alerted
+//once until it clears
+type opsState struct {
+	mu sync.Mutex
+
+	upstreamFailures int
+	upstreamSince    time.Time
+	upstreamErr      string
+	upstreamAlerted  bool
+
+	authAlerted bool
+	//missed holds the job runs already alerted, keyed by job id and due time
+	missed map[string]bool
+}
+
+var opsMonitor = &opsState{missed: make(map[string]bool)}
+
+//upstreamMonitor counts consecutive failed requests to seattlefoodtruck.com, errors,
+//429s and 5xx, alerting the ops channel after OPS_UPSTREAM_ERRORS of them
+type upstreamMonitor struct {
+	next http.RoundTripper
+}
+
+func (m *upstreamMonitor) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := m.next.RoundTrip(req)
+	switch {
+	case err != nil:
+		opsMonitor.upstreamFailed(req, err.Error())
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		opsMonitor.upstreamFailed(req, resp.Status)
+	default:
+		opsMonitor.upstreamSucceeded()
+	}
+	return resp, err
+}
+
+func (o *opsState) upstreamFailed(req *http.Request, reason string) {
+	o.mu.Lock()
+	o.upstreamFailures++
+	if o.upstreamFailures == 1 {
+		o.upstreamSince = time.Now()
+	}
+	o.upstreamErr = req.Method + " " + req.URL.Path + ": " + reason
+	alert := !o.upstreamAlerted && opsUpstreamErrors > 0 && o.upstreamFailures >= opsUpstreamErrors
+	if alert {
+		o.upstreamAlerted = true
+	}
+	failures, since, last := o.upstreamFailures, o.upstreamSince, o.upstreamErr
+	o.mu.Unlock()
+
+	if alert {
+		alertAdmins(fmt.Sprintf(":rotating_light: %v requests in a row to seattlefoodtruck.com failed since %s, schedules can't be fetched. Last: %s",
+			failures, since.In(tz).Format("Mon Jan 2 3:04:05PM"), last))
+	}
+}
+
+func (o *opsState) upstreamSucceeded() {
+	o.mu.Lock()
+	recovered := o.upstreamAlerted
+	failures, since := o.upstreamFailures, o.upstreamSince
+	o.upstreamFailures, o.upstreamAlerted = 0, false
+	o.mu.Unlock()
+
+	if recovered {
+		alertAdmins(fmt.Sprintf(":white_check_mark: seattlefoodtruck.com answers again after %v failed requests over %s",
+			failures, time.Since(since).Round(time.Second)))
+	}
+}
+
+//monitorOps checks every interval that the bot token works and that no cron run was
+//missed, alerting the ops channel
+func monitorOps(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		opsMonitor.checkSlackAuth()
+		opsMonitor.checkMissedRuns(time.Now())
+	}
+}
+
+//checkSlackAuth alerts when Slack rejects the bot token, and when it's accepted again
+func (o *opsState) checkSlackAuth() {
+	if chat != slackChat || slackAPI() == nil {
+		return
+	}
+	_, err := slackAPI().AuthTest()
+	failed := err != nil && isSlackAuthError(err)
+	if err != nil && !failed {
+		logger.Warnw("Error checking Slack auth", zap.Error(err))
+		return
+	}
+
+	o.mu.Lock()
+	changed := failed != o.authAlerted
+	o.authAlerted = failed
+	o.mu.Unlock()
+
+	switch {
+	case changed && failed:
+		//the bot can't post the alert itself, it reaches the channel through OPS_WEBHOOK_URL only
+		alertAdmins(fmt.Sprintf(":rotating_light: Slack rejects the bot token (%v), nothing can be posted until it's replaced or the app reinstalled", err))
+	case changed:
+		alertAdmins(":white_check_mark: Slack accepts the bot token again")
+	}
+}
+
+func isSlackAuthError(err error) bool {
+	for _, e := range slackAuthErrors {
+		if strings.Contains(err.Error(), e) {
+			return true
+		}
+	}
+	return false
+}
+
+//checkMissedRuns alerts about jobs whose run is more than opsMissedGrace overdue at now
+func (o *opsState) checkMissedRuns(now time.Time) {
+	for _, j := range listJobs() {
+		if j.Paused || j.Next.IsZero() || now.Sub(j.Next) < opsMissedGrace {
+			continue
+		}
+		key := fmt.Sprintf("%v:%v", j.ID, j.Next.Unix())
+		o.mu.Lock()
+		alerted := o.missed[key]
+		o.missed[key] = true
+		o.mu.Unlock()
+		if alerted {
+			continue
+		}
+		alertAdmins(fmt.Sprintf(":rotating_light: Job %v, the %s of <#%s> (%s), was due at %s and hasn't run, check the scheduler with jobs",
+			j.ID, j.Kind, j.Channel, j.Spec, j.Next.In(tz).Format("Mon Jan 2 3:04PM")))
+	}
+}
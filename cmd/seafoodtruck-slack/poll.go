@@ -0,0 +1,243 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	voteActionID  = "poll_vote"
+	defaultCutoff = "11:30"
+	//polls started after the cutoff stay open this long
+	latePollWindow = time.Hour
+)
+
+//Poll is a vote on which of today's trucks to go to
+type Poll struct {
+	Channel string          `json:"channel"`
+	TS      string          `json:"ts"`
+	Trucks  []ArchivedTruck `json:"trucks"`
+	//Votes maps users to the id of the truck they voted for
+	Votes  map[string]string `json:"votes"`
+	Closes time.Time         `json:"closes"`
+	Closed bool              `json:"closed"`
+}
+
+//pollsMu serializes votes so concurrent clicks don't overwrite each other
+var pollsMu sync.Mutex
+
+func messageKey(channel, ts string) string {
+	return channel + ":" + ts
+}
+
+//tally returns the votes of each truck
+func (p *Poll) tally() map[string]int {
+	counts := make(map[string]int)
+	for _, id := range p.Votes {
+		counts[id]++
+	}
+	return counts
+}
+
+//winner returns the truck with most votes, ties going to the truck listed first
+func (p *Poll) winner() (ArchivedTruck, int) {
+	counts := p.tally()
+	var best ArchivedTruck
+	most := 0
+	for _, t := range p.Trucks {
+		if counts[t.ID] > most {
+			best, most = t, counts[t.ID]
+		}
+	}
+	return best, most
+}
+
+func pollMessage(p *Poll) slack.Message {
+	locale := localeFor(p.Channel)
+	header := i18n.T(locale, i18n.PollHeader, p.Closes.In(tz).Format(time.Kitchen))
+	if p.Closed {
+		header = i18n.T(locale, i18n.PollClosedHeader)
+	}
+	msg := slack.NewBlockMessage(
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", header, false, false), nil, nil),
+		slack.NewDividerBlock(),
+	)
+	counts := p.tally()
+	for _, t := range p.Trucks {
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("*<%s|%s>* ", fmt.Sprintf(truckURL, t.ID), t.Name))
+		for _, fc := range t.FoodCategories {
+			sb.WriteString(render.Emoji(fc))
+		}
+		sb.WriteString("\n" + strings.Repeat(":large_blue_circle:", counts[t.ID]) + " " + i18n.T(locale, i18n.PollVotes, counts[t.ID]))
+
+		var accessory *slack.Accessory
+		if !p.Closed {
+			accessory = slack.NewAccessory(slack.NewButtonBlockElement(voteActionID, t.ID, slack.NewTextBlockObject("plain_text", i18n.T(locale, i18n.PollVote), false, false)))
+		}
+		msg = slack.AddBlockMessage(msg, slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", sb.String(), false, false), nil, accessory))
+	}
+	if p.Closed {
+		text := i18n.T(locale, i18n.PollNoWinner)
+		if t, n := p.winner(); n > 0 {
+			text = i18n.T(locale, i18n.PollWinner, t.Name, n)
+		}
+		msg = slack.AddBlockMessage(msg, slack.NewContextBlock("", slack.NewTextBlockObject("mrkdwn", text, false, false)))
+	}
+	msg.Text = i18n.T(locale, i18n.PollText, len(p.Trucks), p.Closes.In(tz).Format(time.Kitchen))
+	if p.Closed {
+		msg.Text = i18n.T(locale, i18n.PollClosedText)
+		if t, n := p.winner(); n > 0 {
+			msg.Text = i18n.T(locale, i18n.PollClosedWinner, t.Name, n)
+		}
+	}
+	return msg
+}
+
+//pollCloses returns when a poll started at now closes, the POLL_CUTOFF time of day or an
+//hour from now when that has passed
+func pollCloses(now time.Time) time.Time {
+	cutoff, err := commands.ParseTime(firstNonEmpty(pollCutoff, defaultCutoff), tz)
+	if err != nil {
+		logger.Warnw("Ignoring POLL_CUTOFF", zap.Error(err))
+		cutoff, _ = commands.ParseTime(defaultCutoff, tz)
+	}
+	closes := time.Date(now.Year(), now.Month(), now.Day(), cutoff.Hour(), cutoff.Minute(), 0, 0, tz)
+	if !closes.After(now) {
+		closes = now.Add(latePollWindow)
+	}
+	return closes
+}
+
+//startPoll posts today's trucks at the locations of channel as a poll
+func startPoll(channel string) {
+	p := &Poll{Channel: channel, Votes: make(map[string]string), Closes: pollCloses(time.Now().In(tz))}
+	seen := make(map[string]bool)
+	for _, id := range locationsFor(channel) {
+		events, err := proxy.GetEvents(id, today)
+		if err != nil {
+			logger.Errorw("Error getting events for poll", zap.Error(err))
+			slackAPI().PostMessage(channel, slack.MsgOptionText(i18n.T(localeFor(channel), i18n.ErrEvents), false))
+			return
+		}
+		for _, e := range events {
+			for _, b := range e.Bookings {
+				if !seen[b.Truck.ID] {
+					seen[b.Truck.ID] = true
+					p.Trucks = append(p.Trucks, ArchivedTruck{ID: b.Truck.ID, Name: b.Truck.Name, FoodCategories: b.Truck.FoodCategories})
+				}
+			}
+		}
+	}
+	if len(p.Trucks) == 0 {
+		slackAPI().PostMessage(channel, slack.MsgOptionText(i18n.T(localeFor(channel), i18n.PollNoTrucks), false))
+		return
+	}
+
+	msg := pollMessage(p)
+	_, ts, err := slackAPI().PostMessage(channel, slack.MsgOptionText(msg.Text, false), slack.MsgOptionBlocks(msg.Blocks.BlockSet...))
+	if err != nil {
+		logger.Errorw("Error posting poll", zap.Error(err))
+		return
+	}
+	p.TS = ts
+	if err := store.PutJSON(kv, store.Polls, messageKey(channel, ts), p); err != nil {
+		logger.Errorw("Error saving poll", zap.Error(err))
+		return
+	}
+	scheduleClose(p)
+}
+
+//votePoll records the vote of the clicking user and refreshes the counts
+func votePoll(cb *slack.InteractionCallback, action *slack.BlockAction) {
+	key := messageKey(cb.Channel.ID, cb.Message.Timestamp)
+
+	pollsMu.Lock()
+	defer pollsMu.Unlock()
+
+	var p Poll
+	if err := store.GetJSON(kv, store.Polls, key, &p); err != nil {
+		logger.Errorw("Error loading poll "+key, zap.Error(err))
+		return
+	}
+	if p.Closed {
+		return
+	}
+	p.Votes[cb.User.ID] = action.Value
+	if err := store.PutJSON(kv, store.Polls, key, &p); err != nil {
+		logger.Errorw("Error saving vote", zap.Error(err))
+		return
+	}
+	updatePoll(&p)
+}
+
+func updatePoll(p *Poll) {
+	msg := pollMessage(p)
+	if _, _, _, err := slackAPI().UpdateMessage(p.Channel, p.TS, slack.MsgOptionText(msg.Text, false), slack.MsgOptionBlocks(msg.Blocks.BlockSet...)); err != nil {
+		logger.Errorw("Error updating poll", zap.Error(err))
+	}
+}
+
+//scheduleClose closes p at its cutoff
+func scheduleClose(p *Poll) {
+	time.AfterFunc(time.Until(p.Closes), func() {
+		defer recoverPanic("")
+		closePoll(messageKey(p.Channel, p.TS))
+	})
+}
+
+//closePoll stops voting on the poll stored under key and announces the winner
+func closePoll(key string) {
+	pollsMu.Lock()
+	defer pollsMu.Unlock()
+
+	var p Poll
+	if err := store.GetJSON(kv, store.Polls, key, &p); err != nil {
+		logger.Errorw("Error loading poll "+key, zap.Error(err))
+		return
+	}
+	if p.Closed {
+		return
+	}
+	p.Closed = true
+	if err := store.PutJSON(kv, store.Polls, key, &p); err != nil {
+		logger.Errorw("Error closing poll", zap.Error(err))
+		return
+	}
+	updatePoll(&p)
+
+	locale := localeFor(p.Channel)
+	text := i18n.T(locale, i18n.PollNoVotes)
+	if t, n := p.winner(); n > 0 {
+		text = i18n.T(locale, i18n.PollGoing, fmt.Sprintf(truckURL, t.ID), t.Name, n)
+	}
+	if _, _, err := slackAPI().PostMessage(p.Channel, slack.MsgOptionText(text, false), slack.MsgOptionTS(p.TS), slack.MsgOptionBroadcast()); err != nil {
+		logger.Errorw("Error announcing poll winner", zap.Error(err))
+	}
+}
+
+//resumePolls schedules the close of polls still open after a restart
+func resumePolls() {
+	values, err := kv.List(store.Polls)
+	if err != nil {
+		logger.Errorw("Error listing polls", zap.Error(err))
+		return
+	}
+	for _, data := range values {
+		var p Poll
+		if err := json.Unmarshal(data, &p); err != nil || p.Closed {
+			continue
+		}
+		scheduleClose(&p)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/appsbyram/seafoodtruck-slack/internal/schedule"
+	"github.com/appsbyram/seafoodtruck-slack/internal/slackbot"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"go.uber.org/zap"
+)
+
+//weeklyPostID stands in for the location id in the keys of weekly previews
+const weeklyPostID = "weekly"
+
+//publisher posts the schedules into Slack channels
+var publisher *slackbot.Publisher
+
+//newPublisher returns the publisher of schedules posting with the current Slack client,
+//scheduled posts honouring quiet hours
+func newPublisher() *slackbot.Publisher {
+	return &slackbot.Publisher{
+		Slack:     func() slackbot.Client { return slackAPI() },
+		Post:      postMessage,
+		Proxy:     proxy,
+		Posts:     slackbot.Posts{Store: kv, Logger: logger},
+		Channels:  channelSettings{},
+		Hooks:     publishHooks{},
+		Location:  tz,
+		Logger:    logger,
+		Social:    socialLinks,
+		MenuItems: menuItems,
+	}
+}
+
+//channelSettings answers the publisher from the channel schedules
+type channelSettings struct{}
+
+func (channelSettings) Locale(channel string) string    { return localeFor(channel) }
+func (channelSettings) Order(channel string) string     { return orderFor(channel) }
+func (channelSettings) Style(channel string) string     { return styleFor(channel) }
+func (channelSettings) NotifyEmpty(channel string) bool { return notifyEmptyFor(channel) }
+
+//publishHooks keep the archive, RSVPs, favorites and reminders in step with the posts
+type publishHooks struct{}
+
+func (publishHooks) Fetched(loc seattlefoodtruck.Location, day string, events []seattlefoodtruck.Event) {
+	archiveEvents(loc, day, events)
+}
+
+func (publishHooks) Decorate(channel string, loc seattlefoodtruck.Location, snap schedule.Snapshot, events []seattlefoodtruck.Event, opts *render.Options) {
+	opts.Weather = weatherLine(loc, events, opts.Locale)
+	opts.Distance = distanceFrom(channel, loc)
+	opts.New = newTrucksAt(loc.ID, snap.Day, events)
+}
+
+func (publishHooks) Going(post slackbot.PostRecord) map[string][]string {
+	return goingAt(post)
+}
+
+//Posted records who is going to the trucks of each part, a new post also alerts who
+//favorited its trucks and schedules its reminders
+func (publishHooks) Posted(post slackbot.Post) {
+	opts := post.Options
+	for part, partTS := range post.TS {
+		going := make(map[string][]string)
+		for _, truckID := range render.RSVPTrucks(post.Parts[part]) {
+			if users, ok := opts.Going[truckID]; ok {
+				going[truckID] = users
+			}
+		}
+		recordRSVP(RSVP{Channel: post.Channel, TS: partTS, Part: part, LocationID: post.Location.ID, Day: post.Snapshot.Day, More: opts.More, Weather: opts.Weather, Distance: opts.Distance, Order: opts.Order, New: opts.New, Style: opts.Style, DayName: opts.Day, Going: going})
+	}
+	if post.Updated || post.Day == tomorrow {
+		return
+	}
+	if !post.Interactive {
+		notifyFavorites(post.Location, post.Events)
+	}
+	scheduleReminders(post.Channel, post.TS[0], post.Location, post.Events)
+}
+
+func (publishHooks) Deleted(channel, ts string) {
+	if err := kv.Delete(store.RSVPs, messageKey(channel, ts)); err != nil {
+		logger.Warnw("Error deleting rsvp", zap.Error(err))
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"go.uber.org/zap"
+)
+
+//servePprof serves the runtime profiles on their own listener so they never share
+//the public port, it is meant to be bound to localhost or an internal interface
+func servePprof(pprofAddr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	logger.Infof("Serving pprof on %s", pprofAddr)
+	if err := http.ListenAndServe(pprofAddr, mux); err != nil {
+		logger.Errorw("Error serving pprof", zap.Error(err))
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"go.uber.org/zap"
+)
+
+//dietsOf returns the dietary preferences of user, none when unset
+func dietsOf(user string) []string {
+	var prefs []string
+	if err := store.GetJSON(kv, store.Preferences, user, &prefs); err != nil && err != store.ErrNotFound {
+		logger.Warnw("Error loading preferences of "+user, zap.Error(err))
+	}
+	return prefs
+}
+
+//styleOf returns how user wants events rendered, empty for the channel's style
+func styleOf(user string) string {
+	data, err := kv.Get(store.Styles, user)
+	if err != nil {
+		if err != store.ErrNotFound {
+			logger.Warnw("Error loading style of "+user, zap.Error(err))
+		}
+		return ""
+	}
+	return string(data)
+}
+
+//prefsCommand handles prefs, prefs set <diets>, prefs compact/accessible on/off, prefs
+//locale <locale> and prefs clear
+func prefsCommand(event *slackevents.AppMentionEvent, args string) {
+	switch {
+	case len(args) == 0:
+		prefs := dietsOf(event.User)
+		if style := styleOf(event.User); len(style) > 0 {
+			prefs = append(prefs, style)
+		}
+		if len(prefs) > 0 {
+			postEphemeral(event, tr(event, i18n.PrefsList, strings.Join(prefs, ", ")))
+			return
+		}
+		postEphemeral(event, tr(event, i18n.PrefsNone, render.KnownDiets()))
+	case args == "compact on" || args == "compact off" || args == "accessible on" || args == "accessible off":
+		style, on := strings.Fields(args)[0], strings.HasSuffix(args, " on")
+		var err error
+		if on {
+			err = kv.Put(store.Styles, event.User, []byte(style))
+		} else {
+			err = kv.Delete(store.Styles, event.User)
+		}
+		if err != nil {
+			logger.Errorw("Error saving style", zap.Error(err))
+			postEphemeral(event, tr(event, i18n.PrefsSaveFailed))
+			return
+		}
+		switch {
+		case on && style == render.StyleCompact:
+			postEphemeral(event, tr(event, i18n.PrefsCompactOn))
+		case on:
+			postEphemeral(event, tr(event, i18n.PrefsAccessibleOn))
+		default:
+			postEphemeral(event, tr(event, i18n.PrefsCompactOff))
+		}
+	case strings.HasPrefix(args, "locale "):
+		l := strings.ToLower(strings.TrimSpace(args[7:]))
+		if len(l) == 0 || !i18n.IsLocale(l) {
+			postEphemeral(event, tr(event, i18n.PrefsUnknownLocale, l, i18n.Locales()))
+			return
+		}
+		if err := kv.Put(store.Locales, event.User, []byte(l)); err != nil {
+			logger.Errorw("Error saving locale", zap.Error(err))
+			postEphemeral(event, tr(event, i18n.PrefsSaveFailed))
+			return
+		}
+		postEphemeral(event, i18n.T(l, i18n.PrefsLocaleSaved))
+	case args == "clear":
+		if err := kv.Delete(store.Preferences, event.User); err != nil {
+			logger.Errorw("Error clearing preferences", zap.Error(err))
+			postEphemeral(event, tr(event, i18n.PrefsClearFailed))
+			return
+		}
+		postEphemeral(event, tr(event, i18n.PrefsCleared))
+	case strings.HasPrefix(args, "set "):
+		var prefs []string
+		for _, p := range commands.SplitIDs(strings.Replace(args[4:], " ", ",", -1)) {
+			p = strings.Replace(strings.ToLower(p), "-", "_", -1)
+			if !render.IsDiet(p) {
+				postEphemeral(event, tr(event, i18n.PrefsUnknownDiet, p, render.KnownDiets()))
+				return
+			}
+			prefs = append(prefs, p)
+		}
+		if err := store.PutJSON(kv, store.Preferences, event.User, prefs); err != nil {
+			logger.Errorw("Error saving preferences", zap.Error(err))
+			postEphemeral(event, tr(event, i18n.PrefsSaveFailed))
+			return
+		}
+		postEphemeral(event, tr(event, i18n.PrefsSaved, strings.Join(prefs, ", ")))
+	default:
+		postEphemeral(event, tr(event, i18n.PrefsUsage, render.KnownDiets(), i18n.Locales()))
+	}
+}
+
+//postPreferredEvents answers find events for a user with preferences, showing only
+//them the trucks that fit in order, or the channel's when empty, rendered in style
+func postPreferredEvents(event *slackevents.AppMentionEvent, day string, forLocations []string, prefs []string, order, style string) {
+	if len(order) == 0 {
+		order = orderFor(event.Channel)
+	}
+	locale := localeOf(event.User, event.Channel)
+	for i, id := range forLocations {
+		loc, err := proxy.GetLocation(id)
+		if err != nil {
+			logger.Errorw("Error getting location", zap.Error(err))
+			postEphemeral(event, i18n.T(locale, i18n.ErrLocation))
+			return
+		}
+		events, err := proxy.GetEvents(id, day)
+		if err != nil {
+			logger.Errorw("Error getting events", zap.Error(err))
+			postEphemeral(event, i18n.T(locale, i18n.ErrEvents))
+			return
+		}
+		if len(events) == 0 {
+			postEphemeral(event, i18n.T(locale, i18n.NoTrucks, loc.Name, dayWord(locale, day), dayOf(day).Format("Mon Jan 2")))
+			continue
+		}
+		msg := render.Events(loc, events, proxy.GetTruck, tz, render.Options{More: i < len(forLocations)-1, Diets: prefs, Order: order, Social: socialLinks, MenuItems: menuItems, Day: dayName(day, locale), Style: style, Locale: locale})
+		for _, part := range render.Split(msg) {
+			if _, err := slackAPI().PostEphemeral(event.Channel, event.User, slack.MsgOptionText(part.Text, false), slack.MsgOptionBlocks(part.Blocks.BlockSet...)); err != nil {
+				logger.Errorw("Error posting ephemeral message", zap.Error(err))
+				break
+			}
+		}
+	}
+}
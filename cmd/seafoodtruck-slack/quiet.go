@@ -0,0 +1,59 @@
+package main
+
+import (
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/internal/schedule"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+//quietFor returns the quiet hours and days of channel, falling back to QUIET_HOURS and QUIET_DAYS
+func quietFor(channel string) (*schedule.QuietWindow, []string) {
+	schedulesMu.RLock()
+	sch, ok := schedules[channel]
+	schedulesMu.RUnlock()
+
+	hours, days := quietHours, commands.SplitIDs(quietDays)
+	if ok && len(sch.QuietHours) > 0 {
+		hours = sch.QuietHours
+	}
+	if ok && len(sch.QuietDays) > 0 {
+		days = sch.QuietDays
+	}
+	w, err := schedule.ParseQuietHours(hours)
+	if err != nil {
+		logger.Warnw("Ignoring quiet hours of channel "+channel, zap.Error(err))
+	}
+	return w, days
+}
+
+//postMessage posts to channel unless it is quiet there. Posts during quiet hours are
+//deferred to the end of the window when that is still the same day, anything else is
+//dropped, as are posts of the post command which exits before it could make them.
+//Every unsolicited post goes through here, direct replies to users don't.
+func postMessage(channel string, options ...slack.MsgOption) (string, string, error) {
+	now := time.Now().In(tz)
+	w, days := quietFor(channel)
+	if schedule.IsQuietDay(days, now) {
+		logger.Infof("Suppressing post to channel %s on quiet day %s", channel, now.Weekday())
+		return "", "", nil
+	}
+	wait := w.Until(now)
+	if wait == 0 {
+		return slackAPI().PostMessage(channel, options...)
+	}
+	if at := now.Add(wait); oneShot || at.YearDay() != now.YearDay() || schedule.IsQuietDay(days, at) {
+		logger.Infof("Suppressing post to channel %s during quiet hours", channel)
+		return "", "", nil
+	}
+	logger.Infof("Deferring post to channel %s by %s for quiet hours", channel, wait)
+	time.AfterFunc(wait, func() {
+		defer recoverPanic("")
+		if _, _, err := slackAPI().PostMessage(channel, options...); err != nil {
+			logger.Errorw("Error posting deferred message", zap.Error(err))
+		}
+	})
+	return "", "", nil
+}
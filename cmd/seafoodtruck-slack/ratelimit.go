@@ -0,0 +1,122 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+//idleBucketTTL drops the buckets of clients quiet for this long
+const idleBucketTTL = 10 * time.Minute
+
+//publicRoutes are reachable without credentials and throttled per client ip. The
+//chat webhooks are throttled too, their signatures and tokens are only checked by the
+//handlers. Exempt are the Admin routes, which take the admin token, and ReadyGet, whose
+//probes come from the orchestrator and mustn't fail for being frequent.
+var publicRoutes = map[string]bool{
+	"HomeGet":            true,
+	"HomePost":           true,
+	"EventsGet":          true,
+	"LocationsGet":       true,
+	"LocationGet":        true,
+	"TrucksGet":          true,
+	"TruckGet":           true,
+	"NeighborhoodsGet":   true,
+	"FeedGet":            true,
+	"CalendarGet":        true,
+	"AtomGet":            true,
+	"ExportGet":          true,
+	"GraphQLGet":         true,
+	"GraphQLPost":        true,
+	"TriggerBookingsGet": true,
+	"BriefingGet":        true,
+	"OpenAPIGet":         true,
+
+	"InteractionsPost":      true,
+	"MattermostCommandPost": true,
+	"GoogleChatPost":        true,
+}
+
+//bucket is a token bucket refilled at the configured rate
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+//ipLimiter keeps a token bucket per client ip
+type ipLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+func newIPLimiter(rate float64, burst int) *ipLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &ipLimiter{rate: rate, burst: float64(burst), buckets: make(map[string]*bucket)}
+}
+
+//allow takes a token from the bucket of ip and reports whether one was left
+func (l *ipLimiter) allow(ip string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.swept) > idleBucketTTL {
+		for k, b := range l.buckets {
+			if now.Sub(b.last) > idleBucketTTL {
+				delete(l.buckets, k)
+			}
+		}
+		l.swept = now
+	}
+	b, ok := l.buckets[ip]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[ip] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+//clientIP returns the ip of the caller, the last X-Forwarded-For hop when the bot runs
+//behind a trusted proxy. That's the hop the proxy appended, the ones before it are
+//whatever the client sent.
+func clientIP(r *http.Request) string {
+	if fwd := r.Header.Values("X-Forwarded-For"); trustForwardedFor && len(fwd) > 0 {
+		hops := strings.Split(fwd[len(fwd)-1], ",")
+		if hop := strings.TrimSpace(hops[len(hops)-1]); len(hop) > 0 {
+			return hop
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+//withRateLimit answers 429 once a client ip exceeds the limit of l
+func withRateLimit(l *ipLimiter, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !l.allow(clientIP(r), time.Now()) {
+			requestLogger(r.Context()).Warnw("Rate limited", "ip", clientIP(r))
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+		next(w, r)
+	}
+}
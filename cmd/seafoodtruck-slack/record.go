@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/appsbyram/pkg/logging"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+)
+
+//runRecord saves the upstream responses the bot needs to post ids over days starting
+//today as fixtures under dir: the locations, their events and the booked trucks
+func runRecord(w io.Writer, ids []string, days int, dir string) error {
+	forLocations, err := renderLocations("", ids)
+	if err != nil {
+		return err
+	}
+
+	ctx := logging.WithLogger(context.TODO(), logger)
+	cfg := seattlefoodtruck.NewConfiguration()
+	cfg.Location = tz
+	cfg.HTTPClient = &http.Client{
+		Transport: &seattlefoodtruck.RecordingTransport{Dir: dir, Location: tz},
+		Timeout:   30 * time.Second,
+	}
+	recorder := seattlefoodtruck.NewFoodTruckClientFromConfig(ctx, cfg)
+
+	trucks := make(map[string]bool)
+	now := time.Now().In(tz)
+	for _, id := range forLocations {
+		if _, err := recorder.GetLocation(id); err != nil {
+			return fmt.Errorf("location %s: %v", id, err)
+		}
+		for day := 0; day < days; day++ {
+			events, err := recorder.GetEventsOn(ctx, id, now.AddDate(0, 0, day))
+			if err != nil {
+				return fmt.Errorf("events of location %s: %v", id, err)
+			}
+			for _, e := range events {
+				for _, b := range e.Bookings {
+					trucks[b.Truck.ID] = true
+				}
+			}
+		}
+	}
+	for id := range trucks {
+		if _, err := recorder.GetTruck(id); err != nil {
+			return fmt.Errorf("truck %s: %v", id, err)
+		}
+	}
+	fmt.Fprintf(w, "Recorded %d locations, %d days and %d trucks under %s\n", len(forLocations), days, len(trucks), dir)
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+//recoverPanic is deferred by goroutines and handlers to log a panic with its stack,
+//apologizing in channel when set
+func recoverPanic(channel string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	logger.Errorw("Recovered panic", "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+	if len(channel) > 0 {
+		if _, _, err := slackAPI().PostMessage(channel, slack.MsgOptionText(i18n.T(localeFor(channel), i18n.PanicApology), false)); err != nil {
+			logger.Errorw("Error posting apology", zap.Error(err))
+		}
+	}
+}
+
+//withJobRecovery keeps a panicking scheduled job from crashing the bot, alerting the admins
+func withJobRecovery(name, channel string, run func()) func() {
+	return func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Errorw("Recovered panic in job", "job", name, "channel", channel, "stack", string(debug.Stack()))
+				alertAdmins(fmt.Sprintf(":rotating_light: %s for <#%s> panicked: %v", name, channel, r))
+			}
+		}()
+		run()
+	}
+}
+
+//withRecovery answers 500 when a handler panics instead of dropping the connection
+func withRecovery(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if p := recover(); p != nil {
+				requestLogger(r.Context()).Errorw("Recovered panic in handler", "panic", fmt.Sprint(p), "stack", string(debug.Stack()))
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+			}
+		}()
+		next(w, r)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+//reloadDebounce collapses the burst of events editors cause when saving a file
+const reloadDebounce = 500 * time.Millisecond
+
+//watchConfig reloads the configuration file at path on SIGHUP and whenever it changes
+func watchConfig(path string) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	var changes <-chan fsnotify.Event
+	var errs <-chan error
+	abs, err := filepath.Abs(path)
+	if err == nil {
+		var watcher *fsnotify.Watcher
+		if watcher, err = fsnotify.NewWatcher(); err == nil {
+			defer watcher.Close()
+			//watch the directory, editors and config maps replace the file rather than write it
+			if err = watcher.Add(filepath.Dir(abs)); err == nil {
+				changes, errs = watcher.Events, watcher.Errors
+			}
+		}
+	}
+	if err != nil {
+		logger.Warnw("Not watching configuration file, reload with SIGHUP", zap.Error(err))
+	}
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-hup:
+			logger.Info("Received SIGHUP, reloading configuration")
+			reloadConfig(path)
+		case ev := <-changes:
+			if filepath.Clean(ev.Name) == abs && ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				debounce = time.After(reloadDebounce)
+			}
+		case err := <-errs:
+			logger.Warnw("Error watching configuration file", zap.Error(err))
+		case <-debounce:
+			logger.Info("Configuration file changed, reloading")
+			reloadConfig(path)
+		}
+	}
+}
+
+//reloadConfig re-reads location groups, schedules and emoji from the file at path and
+//reschedules the cron. An invalid file is logged and leaves everything as it was.
+func reloadConfig(path string) {
+	config, err := loadConfig(path)
+	if err == nil {
+		err = config.validate()
+	}
+	if err != nil {
+		logger.Errorw("Error reloading configuration, keeping the current one", zap.Error(err))
+		return
+	}
+
+	groups := withStoredGroups(loadLocationGroups(config.LocationGroups))
+	logDiff("location group", currentLocationGroups(), groups)
+	setLocationGroups(groups)
+
+	emoji := render.ConfiguredEmoji()
+	render.SetEmoji(config.Emoji)
+	logDiff("emoji", emoji, render.ConfiguredEmoji())
+
+	reloaded, err := loadSchedules(config.Schedules, scheduleStore)
+	if err != nil {
+		logger.Errorw("Error reloading schedules, keeping the current ones", zap.Error(err))
+		return
+	}
+	replaceSchedules(reloaded)
+}
+
+//replaceSchedules swaps in schedules and rebuilds the cron from them, so no job runs
+//against a half replaced set. Discovered channels keep their schedules.
+func replaceSchedules(reloaded map[string]Schedule) {
+	runner.Restart(func() {
+		schedulesMu.Lock()
+		defer schedulesMu.Unlock()
+
+		for ch := range discovered {
+			if _, ok := reloaded[ch]; !ok {
+				reloaded[ch] = schedules[ch]
+			}
+		}
+		logDiff("schedule", schedules, reloaded)
+		schedules = reloaded
+	})
+}
+
+//logDiff logs which keys of the maps before and after were added, removed or changed
+func logDiff(what string, before, after interface{}) {
+	b, a := reflect.ValueOf(before), reflect.ValueOf(after)
+	var added, removed, changed []string
+	for _, k := range a.MapKeys() {
+		old := b.MapIndex(k)
+		switch {
+		case !old.IsValid():
+			added = append(added, k.String())
+		case !reflect.DeepEqual(old.Interface(), a.MapIndex(k).Interface()):
+			changed = append(changed, k.String())
+		}
+	}
+	for _, k := range b.MapKeys() {
+		if !a.MapIndex(k).IsValid() {
+			removed = append(removed, k.String())
+		}
+	}
+	if len(added)+len(removed)+len(changed) == 0 {
+		return
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(changed)
+	logger.Infow("Reloaded "+what+"s", "added", added, "removed", removed, "changed", changed)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+var (
+	remindersMu sync.Mutex
+	reminders   = make(map[string]*time.Timer)
+)
+
+//reminderMinutesFor returns how many minutes before an event channel wants a reminder,
+//falling back to REMINDER_MINUTES; zero disables reminders
+func reminderMinutesFor(channel string) int {
+	schedulesMu.RLock()
+	defer schedulesMu.RUnlock()
+
+	if sch, ok := schedules[channel]; ok && sch.ReminderMinutes > 0 {
+		return sch.ReminderMinutes
+	}
+	return reminderMinutes
+}
+
+//scheduleReminders arranges a threaded reminder under the post ts before each of events
+//starts. Reminders are keyed by channel and event so repeated posts don't duplicate them.
+func scheduleReminders(channel, ts string, loc seattlefoodtruck.Location, events []seattlefoodtruck.Event) {
+	minutes := reminderMinutesFor(channel)
+	if minutes <= 0 {
+		return
+	}
+	lead := time.Duration(minutes) * time.Minute
+
+	remindersMu.Lock()
+	defer remindersMu.Unlock()
+
+	for _, e := range events {
+		st, err := time.Parse(time.RFC3339, e.StartTime)
+		if err != nil {
+			continue
+		}
+		wait := time.Until(st.Add(-lead))
+		if wait <= 0 {
+			continue
+		}
+		key := channel + ":" + strconv.Itoa(e.ID)
+		if _, ok := reminders[key]; ok {
+			continue
+		}
+		text := i18n.T(localeFor(channel), i18n.Reminder, len(e.Bookings), loc.Name, minutes)
+		reminders[key] = time.AfterFunc(wait, func() {
+			defer recoverPanic("")
+			remindersMu.Lock()
+			delete(reminders, key)
+			remindersMu.Unlock()
+
+			opts := []slack.MsgOption{slack.MsgOptionText(text, false)}
+			if len(ts) > 0 {
+				opts = append(opts, slack.MsgOptionTS(ts))
+			}
+			if _, _, err := postMessage(channel, opts...); err != nil {
+				logger.Errorw("Error posting reminder", zap.Error(err))
+			}
+		})
+		logger.Infof("Scheduled reminder for %s at %s", key, st.Add(-lead).In(tz).Format(time.Kitchen))
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	s "github.com/appsbyram/pkg/http"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+//APIError is the body of failed REST requests
+type APIError struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	s.NewPayload().WriteResponse(s.ContentTypeJSON, status, &APIError{Status: status, Message: message}, w)
+}
+
+//locationsHandler lists locations matching ?query= in ?neighborhood=, given as id or name
+func locationsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("query")
+	var neighborhoodID int
+	if n := r.URL.Query().Get("neighborhood"); len(n) > 0 {
+		id, err := strconv.Atoi(n)
+		if err != nil {
+			neighborhood, lerr := proxy.LookupNeighborhood(n)
+			if lerr != nil {
+				writeError(w, http.StatusBadRequest, "unknown neighborhood "+n)
+				return
+			}
+			id = neighborhood.ID
+		}
+		neighborhoodID = id
+	}
+	locations, err := proxy.FindLocations(context.TODO(), query, neighborhoodID)
+	if err != nil {
+		requestLogger(r.Context()).Errorw("Error finding locations", zap.Error(err))
+		writeError(w, http.StatusBadGateway, "error getting locations")
+		return
+	}
+	s.NewPayload().WriteResponse(s.ContentTypeJSON, http.StatusOK, &locations, w)
+}
+
+//locationHandler returns the location {id}
+func locationHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	loc, err := proxy.GetLocation(id)
+	if err != nil {
+		requestLogger(r.Context()).Errorw("Error getting location", zap.Error(err))
+		writeError(w, http.StatusBadGateway, "error getting location")
+		return
+	}
+	if len(loc.ID) == 0 {
+		writeError(w, http.StatusNotFound, "no location "+id)
+		return
+	}
+	s.NewPayload().WriteResponse(s.ContentTypeJSON, http.StatusOK, &loc, w)
+}
+
+//trucksHandler searches active trucks by ?category= and a ?query= matched against names
+func trucksHandler(w http.ResponseWriter, r *http.Request) {
+	category := r.URL.Query().Get("category")
+	query := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("query")))
+	if len(category) == 0 && len(query) == 0 {
+		writeError(w, http.StatusBadRequest, "category or query is required")
+		return
+	}
+	v, err := apiCache.fetch("trucks:"+category, func() (interface{}, error) {
+		if len(category) > 0 {
+			return proxy.TrucksByCategory(context.TODO(), category)
+		}
+		var trucks []seattlefoodtruck.Truck
+		it := proxy.IterateTrucks(context.TODO(), map[string]string{"active": "true"})
+		for it.Next() {
+			trucks = append(trucks, it.Truck())
+		}
+		return trucks, it.Err()
+	})
+	if err != nil {
+		requestLogger(r.Context()).Errorw("Error searching trucks", zap.Error(err))
+		writeError(w, http.StatusBadGateway, "error getting trucks")
+		return
+	}
+	trucks := []seattlefoodtruck.Truck{}
+	for _, t := range v.([]seattlefoodtruck.Truck) {
+		if len(query) == 0 || strings.Contains(strings.ToLower(t.Name), query) {
+			trucks = append(trucks, t)
+		}
+	}
+	s.NewPayload().WriteResponse(s.ContentTypeJSON, http.StatusOK, &trucks, w)
+}
+
+//truckHandler returns the truck {id} with its menu, photos and ratings
+func truckHandler(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	v, err := apiCache.fetch("truck:"+id, func() (interface{}, error) {
+		return proxy.GetTruck(id)
+	})
+	if err != nil {
+		requestLogger(r.Context()).Errorw("Error getting truck", zap.Error(err))
+		writeError(w, http.StatusBadGateway, "error getting truck")
+		return
+	}
+	truck := v.(seattlefoodtruck.Truck)
+	if len(truck.ID) == 0 {
+		writeError(w, http.StatusNotFound, "no truck "+id)
+		return
+	}
+	s.NewPayload().WriteResponse(s.ContentTypeJSON, http.StatusOK, &truck, w)
+}
+
+//neighborhoodsHandler lists the neighborhoods with their ids and coordinates, sorted by name
+func neighborhoodsHandler(w http.ResponseWriter, r *http.Request) {
+	v, err := apiCache.fetch("neighborhoods", func() (interface{}, error) {
+		neighborhoods, err := proxy.GetNeighborhoods()
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(neighborhoods, func(i, j int) bool {
+			return neighborhoods[i].Name < neighborhoods[j].Name
+		})
+		return neighborhoods, nil
+	})
+	if err != nil {
+		requestLogger(r.Context()).Errorw("Error getting neighborhoods", zap.Error(err))
+		writeError(w, http.StatusBadGateway, "error getting neighborhoods")
+		return
+	}
+	neighborhoods := v.([]seattlefoodtruck.Neighborhood)
+	s.NewPayload().WriteResponse(s.ContentTypeJSON, http.StatusOK, &neighborhoods, w)
+}
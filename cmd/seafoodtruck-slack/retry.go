@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRetryAttempts = 3
+	defaultRetryBackoff  = time.Minute
+)
+
+//withRetry wraps a scheduled post so it is retried with exponential backoff,
+//alerting the admin channel once every attempt has failed
+func withRetry(name, channel string, post func() error) func() {
+	return func() {
+		retry(name, channel, post)
+	}
+}
+
+//retry runs post until it succeeds or retryAttempts are used up, returning the error
+//of the last attempt
+func retry(name, channel string, post func() error) error {
+	var err error
+
+	backoff := retryBackoff
+	for attempt := 1; attempt <= retryAttempts; attempt++ {
+		if err = post(); err == nil {
+			return nil
+		}
+		logger.Warnw(fmt.Sprintf("Scheduled %s for channel %s failed", name, channel),
+			"attempt", attempt, zap.Error(err))
+		if attempt < retryAttempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	alertAdmins(fmt.Sprintf(":rotating_light: Scheduled %s for <#%s> failed after %v attempt(s): %v",
+		name, channel, retryAttempts, err))
+	return err
+}
+
+//alertAdmins posts text through the ops webhook, which works even when the bot token
+//doesn't, or else to the ops or admin channel, when one is configured
+func alertAdmins(text string) {
+	logger.Error(text)
+	if len(opsWebhookURL) > 0 {
+		if err := slack.PostWebhook(opsWebhookURL, &slack.WebhookMessage{Text: text}); err != nil {
+			logger.Errorw("Error posting alert to ops webhook", zap.Error(err))
+		}
+		return
+	}
+	channel := opsChannel
+	if len(channel) == 0 {
+		channel = adminChannel
+	}
+	if len(channel) == 0 || slackAPI() == nil {
+		return
+	}
+	if _, _, err := slackAPI().PostMessage(channel, slack.MsgOptionText(text, false)); err != nil {
+		logger.Errorw("Error posting alert to ops channel", zap.Error(err))
+	}
+}
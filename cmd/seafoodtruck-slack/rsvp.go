@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/appsbyram/seafoodtruck-slack/internal/slackbot"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const rsvpActionID = render.RSVPActionID
+
+//RSVP tracks who is going to which truck of a schedule post
+type RSVP struct {
+	Channel    string `json:"channel"`
+	TS         string `json:"ts"`
+	LocationID string `json:"location_id"`
+	Day        string `json:"day"`
+	More       bool   `json:"more"`
+	//Weather is the forecast line of the post, kept as posted rather than forecast again
+	Weather string `json:"weather,omitempty"`
+	//Distance is the distance of the post's location from the channel's office
+	Distance string `json:"distance,omitempty"`
+	//Part is the index of the message among those the post was split into, the
+	//first one being in channel and the rest threaded under it
+	Part int `json:"part,omitempty"`
+	//Order is the order the post lists trucks in
+	Order string `json:"order,omitempty"`
+	//New holds the ids of the trucks badged new at the location
+	New map[string]bool `json:"new,omitempty"`
+	//Style is the render style of the post, the default one when empty
+	Style string `json:"style,omitempty"`
+	//DayName names the day in the post's notification text, Day being its date
+	DayName string `json:"day_name,omitempty"`
+	//Going lists user ids keyed by truck id
+	Going map[string][]string `json:"going"`
+}
+
+//rsvpsMu serializes clicks so concurrent RSVPs don't overwrite each other
+var rsvpsMu sync.Mutex
+
+//recordRSVP remembers what a schedule post shows so it can be rendered again when
+//someone RSVPs, keeping who already said they're going
+func recordRSVP(r RSVP) {
+	if r.Going == nil {
+		r.Going = make(map[string][]string)
+	}
+	if err := store.PutJSON(kv, store.RSVPs, messageKey(r.Channel, r.TS), r); err != nil {
+		logger.Warnw("Error saving rsvp", zap.Error(err))
+	}
+}
+
+//goingAt returns who is going to which truck of post, across the messages it was split
+//into
+func goingAt(post slackbot.PostRecord) map[string][]string {
+	going := make(map[string][]string)
+	for _, ts := range append([]string{post.TS}, post.Parts...) {
+		var r RSVP
+		if err := store.GetJSON(kv, store.RSVPs, messageKey(post.Channel, ts), &r); err != nil {
+			continue
+		}
+		for truckID, users := range r.Going {
+			//a truck booked twice at the location is in two messages
+			for _, u := range users {
+				if !contains(going[truckID], u) {
+					going[truckID] = append(going[truckID], u)
+				}
+			}
+		}
+	}
+	return going
+}
+
+//toggleRSVP adds the clicking user to the attendees of a truck, or removes them
+//when they already said they're going, and refreshes the post
+func toggleRSVP(cb *slack.InteractionCallback, action *slack.BlockAction) {
+	key := messageKey(cb.Channel.ID, cb.Message.Timestamp)
+
+	rsvpsMu.Lock()
+	defer rsvpsMu.Unlock()
+
+	var r RSVP
+	if err := store.GetJSON(kv, store.RSVPs, key, &r); err != nil {
+		logger.Errorw("Error loading rsvp "+key, zap.Error(err))
+		return
+	}
+	r.toggle(cb.User.ID, action.Value)
+	if err := store.PutJSON(kv, store.RSVPs, key, r); err != nil {
+		logger.Errorw("Error saving rsvp", zap.Error(err))
+		return
+	}
+
+	loc, err := proxy.GetLocation(r.LocationID)
+	if err != nil {
+		logger.Errorw("Error getting location for rsvp", zap.Error(err))
+		return
+	}
+	on, err := time.ParseInLocation(dayLayout, r.Day, tz)
+	if err != nil {
+		logger.Errorw("Error parsing rsvp day", zap.Error(err))
+		return
+	}
+	events, err := proxy.GetEventsOn(context.TODO(), r.LocationID, on)
+	if err != nil {
+		logger.Errorw("Error getting events for rsvp", zap.Error(err))
+		return
+	}
+	msg := render.Events(loc, events, proxy.GetTruck, tz, r.options())
+	if parts := render.Split(msg); r.Part < len(parts) {
+		msg = parts[r.Part]
+	}
+	if _, _, _, err := slackAPI().UpdateMessage(r.Channel, r.TS, slack.MsgOptionText(msg.Text, false), slack.MsgOptionBlocks(msg.Blocks.BlockSet...)); err != nil {
+		logger.Errorw("Error updating post with rsvps", zap.Error(err))
+	}
+}
+
+//toggle adds user to those going to truckID, or removes them when they already are
+func (r *RSVP) toggle(user, truckID string) {
+	if r.Going == nil {
+		r.Going = make(map[string][]string)
+	}
+	going := r.Going[truckID][:0]
+	found := false
+	for _, u := range r.Going[truckID] {
+		if u == user {
+			found = true
+			continue
+		}
+		going = append(going, u)
+	}
+	if !found {
+		going = append(going, user)
+	}
+	r.Going[truckID] = going
+}
+
+//options returns the render options the post was rendered with, with who is going now
+func (r RSVP) options() render.Options {
+	return render.Options{More: r.More, RSVP: true, Going: r.Going, Weather: r.Weather, Distance: r.Distance, Order: r.Order, New: r.New,
+		Social: socialLinks, MenuItems: menuItems, Day: r.DayName, Style: r.Style, Locale: localeFor(r.Channel)}
+}
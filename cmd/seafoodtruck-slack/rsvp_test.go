@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+)
+
+func TestToggleRSVPKeepsStyle(t *testing.T) {
+	loc := seattlefoodtruck.Location{ID: "69", Name: "Westlake Park", Address: "401 Pine St"}
+	events := []seattlefoodtruck.Event{{StartTime: "2020-06-01T11:00:00-07:00", EndTime: "2020-06-01T14:00:00-07:00",
+		Bookings: []seattlefoodtruck.Booking{{Truck: seattlefoodtruck.BookingTruck{ID: "marination", Name: "Marination", FeaturedPhoto: "marination.jpg"}}}}}
+	lookup := func(id string) (seattlefoodtruck.Truck, error) {
+		return seattlefoodtruck.Truck{ID: id, Name: "Marination", Rating: 4, RatingCount: 10}, nil
+	}
+	opts := render.Options{RSVP: true, Day: "today", Style: render.StyleAccessible, Locale: locale}
+
+	//the record goes through the store as json between the post and the click
+	data, err := json.Marshal(RSVP{Channel: "C1", TS: "1.1", LocationID: loc.ID, Day: "2020-06-01", Style: opts.Style, DayName: opts.Day})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r RSVP
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatal(err)
+	}
+	r.toggle("U1", "marination")
+
+	opts.Going = map[string][]string{"marination": {"U1"}}
+	got := render.Events(loc, events, lookup, time.UTC, r.options())
+	want := render.Events(loc, events, lookup, time.UTC, opts)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("post after toggle = %+v, want it still %s: %+v", got.Blocks.BlockSet, opts.Style, want.Blocks.BlockSet)
+	}
+	opts.Style = ""
+	if reflect.DeepEqual(got, render.Events(loc, events, lookup, time.UTC, opts)) {
+		t.Error("post after toggle is the same in the default style, the test doesn't tell styles apart")
+	}
+
+	r.toggle("U1", "marination")
+	if len(r.Going["marination"]) != 0 {
+		t.Errorf("going after toggling twice = %q, want nobody", r.Going["marination"])
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/appsbyram/seafoodtruck-slack/internal/schedule"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/scheduler"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+)
+
+var (
+	schedulesMu   sync.RWMutex
+	runner        *schedule.Runner
+	scheduleStore ScheduleStore
+	kv            store.Store
+)
+
+//Schedule describes a recurring post of events into a channel
+type Schedule = schedule.Schedule
+
+//loadSchedules builds the channel schedules from the configured schedules, falling back
+//to the single channel/location_ids pair, leaving out the channels that unsubscribed.
+//Schedules persisted in the store at runtime take precedence over configured ones.
+func loadSchedules(configured []Schedule, stored ScheduleStore) (map[string]Schedule, error) {
+	schedules := make(map[string]Schedule)
+
+	if len(configured) > 0 {
+		for _, sch := range configured {
+			schedules[sch.Channel] = sch
+		}
+	} else if len(channel) > 0 && len(locations) > 0 {
+		schedules[channel] = Schedule{Locations: commands.SplitIDs(locations)}
+	}
+	for ch := range schedules {
+		_, err := kv.Get(store.OptOuts, ch)
+		if err == nil {
+			delete(schedules, ch)
+			continue
+		}
+		if err != store.ErrNotFound {
+			return nil, err
+		}
+	}
+	persisted, err := stored.Load()
+	if err != nil {
+		return nil, err
+	}
+	for ch, sch := range persisted {
+		schedules[ch] = sch
+	}
+	for ch, sch := range schedules {
+		schedules[ch] = schedule.WithDefaults(ch, sch)
+	}
+	return schedules, nil
+}
+
+//saveSchedule persists sch and reschedules the cron jobs
+func saveSchedule(sch Schedule) error {
+	sch = schedule.WithDefaults(sch.Channel, sch)
+	if _, err := scheduler.Parse(sch.Spec); err != nil {
+		return err
+	}
+	if err := scheduleStore.Save(sch); err != nil {
+		return err
+	}
+	if err := optOut(sch.Channel, false); err != nil {
+		return err
+	}
+	schedulesMu.Lock()
+	schedules[sch.Channel] = sch
+	delete(discovered, sch.Channel)
+	schedulesMu.Unlock()
+
+	runner.Restart(nil)
+	return nil
+}
+
+//deleteSchedule removes the schedule of channel and reschedules the cron jobs. The
+//channel is opted out so neither its configured schedule nor discovery bring it back.
+func deleteSchedule(channel string) error {
+	if err := scheduleStore.Delete(channel); err != nil {
+		return err
+	}
+	if err := optOut(channel, true); err != nil {
+		return err
+	}
+	schedulesMu.Lock()
+	delete(schedules, channel)
+	delete(discovered, channel)
+	schedulesMu.Unlock()
+
+	runner.Restart(nil)
+	return nil
+}
+
+//locationsFor returns the location ids to use when posting events into channel,
+//with location groups expanded
+func locationsFor(channel string) []string {
+	schedulesMu.RLock()
+	defer schedulesMu.RUnlock()
+
+	if sch, ok := schedules[channel]; ok && len(sch.Locations) > 0 {
+		return expandLocations(sch.Locations)
+	}
+	return expandLocations(commands.SplitIDs(locations))
+}
+
+//notifyEmptyFor reports whether channel wants a note when a location has no events,
+//falling back to the NOTIFY_NO_EVENTS setting
+func notifyEmptyFor(channel string) bool {
+	schedulesMu.RLock()
+	defer schedulesMu.RUnlock()
+
+	if sch, ok := schedules[channel]; ok && sch.NotifyEmpty != nil {
+		return *sch.NotifyEmpty
+	}
+	return notifyNoEvents
+}
+
+//orderFor returns the order trucks are listed in for channel, falling back to the
+//TRUCK_ORDER setting
+func orderFor(channel string) string {
+	schedulesMu.RLock()
+	defer schedulesMu.RUnlock()
+
+	if sch, ok := schedules[channel]; ok {
+		if order, ok := render.ParseOrder(sch.Order); ok && len(order) > 0 {
+			return order
+		}
+	}
+	return truckOrder
+}
+
+//styleFor returns how events are rendered in channel, render.StyleAccessible or
+//render.StyleCompact when its schedule asks for it
+func styleFor(channel string) string {
+	schedulesMu.RLock()
+	defer schedulesMu.RUnlock()
+
+	sch, ok := schedules[channel]
+	switch {
+	case ok && sch.Accessible:
+		return render.StyleAccessible
+	case ok && sch.Compact:
+		return render.StyleCompact
+	}
+	return ""
+}
+
+//newRunner returns the runner of the cron posting the schedules, each post recovering
+//from panics, locked across replicas and retried
+func newRunner() *schedule.Runner {
+	return &schedule.Runner{
+		Location: tz,
+		Clock:    scheduler.SystemClock,
+		Logger:   logger,
+		Jobs:     scheduledJobs,
+		Wrap: func(j schedule.Job) func() {
+			return withJobRecovery(j.Kind, j.Channel, withLock(j.Kind, j.Channel, withRetry(j.Kind, j.Channel, j.Run)))
+		},
+		Added: trackJob,
+	}
+}
+
+//scheduledJobs returns the jobs of the current schedules, none when the cron can't or
+//shouldn't run
+func scheduledJobs() []schedule.Job {
+	schedulesMu.RLock()
+	defer schedulesMu.RUnlock()
+
+	if (chat == slackChat && len(token) == 0) || len(schedules) == 0 {
+		logger.Warn("Cannot start cron job due to missing config values")
+		return nil
+	}
+	if !runCron {
+		logger.Info("Not starting the cron job, posts are left to the post command")
+		return nil
+	}
+	resetJobs()
+	var jobs []schedule.Job
+	for _, sch := range schedules {
+		sch := sch
+		sch.Locations = expandLocations(sch.Locations)
+		if len(sch.Locations) == 0 {
+			logger.Warnf("Skipping schedule for channel %s without locations", sch.Channel)
+			continue
+		}
+		jobs = append(jobs, schedule.Job{Kind: postsJob, Channel: sch.Channel, Spec: sch.Spec, Run: scheduledPost(postsJob, sch)})
+		if chat == slackChat {
+			jobs = append(jobs, schedule.Job{Kind: weeklyJob, Channel: sch.Channel, Spec: firstNonEmpty(sch.WeeklySpec, weeklyPreviewSpec), Run: scheduledPost(weeklyJob, sch)})
+		}
+		jobs = append(jobs, schedule.Job{Kind: eveningJob, Channel: sch.Channel, Spec: firstNonEmpty(sch.EveningSpec, eveningSpec), Run: scheduledPost(eveningJob, sch)})
+	}
+	return jobs
+}
+
+//scheduledPost returns the post the job kind makes for sch, whose locations are expanded.
+//Retries of a post skip the locations its earlier attempts posted that day.
+func scheduledPost(kind string, sch Schedule) func() error {
+	var posted schedule.PostedToday
+	switch kind {
+	case weeklyJob:
+		return func() error {
+			return postWeeklyPreview(sch.Channel, sch.Locations)
+		}
+	case eveningJob:
+		return skipHolidays(sch.Channel, tomorrow, func() error {
+			return postEventsOnce(sch.Channel, tomorrow, sch.Locations, posted.Keys(time.Now().In(tz)))
+		})
+	}
+	return skipHolidays(sch.Channel, sch.Day, func() error {
+		if err := postEventsOnce(sch.Channel, sch.Day, sch.Locations, posted.Keys(time.Now().In(tz))); err != nil {
+			return err
+		}
+		sendScheduleWebhooks(sch.Channel, sch.Day, sch.Locations)
+		publishScheduleMQTT(sch.Channel, sch.Day, sch.Locations)
+		return nil
+	})
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if len(v) > 0 {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+)
+
+func TestLoadSchedulesSkipsOptOuts(t *testing.T) {
+	oldKV := kv
+	kv = store.NewMemoryStore()
+	defer func() { kv = oldKV }()
+
+	configured := []Schedule{{Channel: "C1", Locations: []string{"69"}}, {Channel: "C2", Locations: []string{"123"}}}
+	if err := optOut("C1", true); err != nil {
+		t.Fatal(err)
+	}
+	schedules, err := loadSchedules(configured, NewScheduleStore("", kv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := schedules["C1"]; ok {
+		t.Error("the configured schedule of a channel that unsubscribed is loaded")
+	}
+	if _, ok := schedules["C2"]; !ok {
+		t.Error("the configured schedule of C2 isn't loaded")
+	}
+
+	if err := optOut("C1", false); err != nil {
+		t.Fatal(err)
+	}
+	if schedules, err = loadSchedules(configured, NewScheduleStore("", kv)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := schedules["C1"]; !ok {
+		t.Error("the configured schedule of a channel that subscribed again isn't loaded")
+	}
+}
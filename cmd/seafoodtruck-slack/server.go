@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	s "github.com/appsbyram/pkg/http"
+	"github.com/appsbyram/pkg/metrics"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const serverShutdownTimeout = 10 * time.Second
+
+//serve serves routes until SIGINT or SIGTERM. With an autocert host certificates are
+//obtained from Let's Encrypt, with a cert and key file those are served, otherwise plain http.
+//An admin client CA additionally asks clients for certificates to authorize admin requests.
+func serve(routes s.Routes) {
+	if len(autocertHost) == 0 && len(adminClientCA) == 0 {
+		tls := len(tlsCertFile) > 0 && len(tlsKeyFile) > 0
+		s.NewServer(addr, tls, tlsCertFile, tlsKeyFile, routes).Start()
+		return
+	}
+
+	srv := &http.Server{Addr: addr, Handler: newRouter(routes)}
+	servers := []*http.Server{srv}
+	if len(autocertHost) > 0 {
+		m := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(autocertHost),
+			Cache:      autocert.DirCache(autocertCacheDir),
+		}
+		srv.TLSConfig = m.TLSConfig()
+		//answer http-01 challenges and redirect everything else to https
+		challenges := &http.Server{Addr: autocertHTTPAddr, Handler: m.HTTPHandler(nil)}
+		servers = append(servers, challenges)
+		go func() {
+			if err := challenges.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Errorw("Error serving acme challenges", zap.Error(err))
+			}
+		}()
+	} else {
+		cert, err := tls.LoadX509KeyPair(tlsCertFile, tlsKeyFile)
+		if err != nil {
+			logger.Fatalw("admin-client-ca needs tls-cert and tls-key or autocert-host", zap.Error(err))
+		}
+		srv.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+	}
+	if len(adminClientCA) > 0 {
+		pem, err := ioutil.ReadFile(adminClientCA)
+		if err != nil {
+			logger.Fatalw("Error reading admin-client-ca", zap.Error(err))
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			logger.Fatal("No certificates in admin-client-ca")
+		}
+		srv.TLSConfig.ClientCAs = pool
+		srv.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	go func() {
+		logger.Infof("Serving https on %s", addr)
+		if err := srv.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			logger.Fatalw("Error serving https", zap.Error(err))
+		}
+	}()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	logger.Infof("Shutdown request (signal: %v)", <-sig)
+
+	ctx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+	defer cancel()
+	for _, hs := range servers {
+		if err := hs.Shutdown(ctx); err != nil {
+			logger.Warnw("Error shutting down server", zap.Error(err))
+		}
+	}
+}
+
+//newRouter mirrors the router of s.NewServer, including its health and metrics routes,
+//for servers that need their own tls config
+func newRouter(routes s.Routes) *mux.Router {
+	router := mux.NewRouter().StrictSlash(true)
+	for _, route := range routes {
+		router.Methods(route.Method).Path(route.Pattern).Name(route.Name).Handler(route.HandlerFunc)
+	}
+	router.Methods("GET").Path("/health").Name("health").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.NewPayload().WriteResponse(s.ContentTypeJSON, http.StatusOK, s.HealthReport{Status: "UP"}, w)
+	})
+	router.Methods("GET").Path("/metrics").Name("metrics").Handler(metrics.PrometheusHandler())
+	return router
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+//inflight counts responses and interactions still being handled
+var inflight sync.WaitGroup
+
+//safeGo runs work in its own goroutine that shutdown waits for. A panic in work is
+//logged and, when channel is set, apologized for there instead of crashing the bot.
+//On Lambda work is dropped while deferWork is set, to be redone by a replay.
+func safeGo(channel string, work func()) {
+	if deferWork {
+		workDeferred = true
+		return
+	}
+	inflight.Add(1)
+	go func() {
+		defer inflight.Done()
+		defer recoverPanic(channel)
+		work()
+	}()
+}
+
+//shutdown stops the cron and waits up to timeout for running jobs and in-flight
+//responses so deploys don't cut posts off halfway, then closes the store
+func shutdown(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	stopGRPC()
+
+	jobs := runner.Stop().Done()
+
+	responses := make(chan struct{})
+	go func() {
+		inflight.Wait()
+		close(responses)
+	}()
+
+	logger.Info("Waiting for running jobs and responses")
+	select {
+	case <-jobs:
+	case <-ctx.Done():
+		logger.Warn("Timed out waiting for running jobs")
+	}
+	select {
+	case <-responses:
+	case <-ctx.Done():
+		logger.Warn("Timed out waiting for in-flight responses")
+	}
+	if err := kv.Close(); err != nil {
+		logger.Warnw("Error closing store", zap.Error(err))
+	}
+	logger.Info("Shutdown complete")
+}
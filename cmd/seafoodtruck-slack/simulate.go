@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+//simulatedToken is the bot token of the fake Slack the simulate command talks to
+const simulatedToken = "xoxb-simulated"
+
+//fakeSlack answers the web api calls of the bot like Slack would and writes each of
+//them to w instead, so the handlers can run without a workspace
+func fakeSlack(w io.Writer) *httptest.Server {
+	var mu sync.Mutex
+	ts := time.Now().Unix()
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		mu.Lock()
+		ts++
+		stamp := fmt.Sprintf("%d.000100", ts)
+		fmt.Fprintf(w, "--> %s\n", path.Base(r.URL.Path))
+		keys := make([]string, 0, len(r.PostForm))
+		for k := range r.PostForm {
+			if k != "token" {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v := r.PostForm.Get(k)
+			var indented bytes.Buffer
+			if json.Indent(&indented, []byte(v), "    ", "  ") == nil {
+				v = indented.String()
+			}
+			fmt.Fprintf(w, "    %s: %s\n", k, v)
+		}
+		mu.Unlock()
+
+		channel := r.PostForm.Get("channel")
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"ok":         true,
+			"channel":    channel,
+			"ts":         stamp,
+			"message_ts": stamp,
+			"permalink":  "https://simulated.slack.com/archives/" + channel + "/p" + stamp,
+			"channel_id": channel,
+		})
+	}))
+}
+
+//simulate sets the bot up against a fake Slack writing the calls it makes to w. The
+//returned func tears the fake down once the simulated handlers are done.
+func simulate(w io.Writer) (func(), error) {
+	config, err := loadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+	applyConfig(config)
+	newFoodTruckClient()
+	if locker, err = NewLocker(config.LockRedisURL); err != nil {
+		return nil, err
+	}
+	if err := openSchedules(config); err != nil {
+		return nil, err
+	}
+
+	srv := fakeSlack(w)
+	credentialsMu.Lock()
+	token = simulatedToken
+	slackClient = slack.New(token, slack.OptionAPIURL(srv.URL+"/"))
+	//simulated requests aren't signed
+	unsignedRequests = true
+	credentialsMu.Unlock()
+	return func() {
+		inflight.Wait()
+		srv.Close()
+	}, nil
+}
+
+//simulateMention posts an app_mention of text by user in channel to the events handler
+func simulateMention(channel, user, text string) int {
+	now := time.Now()
+	payload, _ := json.Marshal(map[string]interface{}{
+		"type":     "event_callback",
+		"event_id": fmt.Sprintf("EvSIM%d", now.UnixNano()),
+		"event": map[string]interface{}{
+			"type":     "app_mention",
+			"user":     user,
+			"channel":  channel,
+			"text":     "<@USIMBOT> " + text,
+			"ts":       fmt.Sprintf("%d.000100", now.Unix()),
+			"event_ts": fmt.Sprintf("%d.000100", now.Unix()),
+		},
+	})
+	return simulateRequest("/", "application/json", payload, newEventsHandler().ServeHTTP)
+}
+
+//simulateAction posts a click of the button actionID with value by user on the message
+//ts in channel to the interactions handler
+func simulateAction(channel, user, ts, actionID, value string) int {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"type":    "block_actions",
+		"user":    map[string]string{"id": user},
+		"channel": map[string]string{"id": channel},
+		"message": map[string]string{"ts": ts},
+		"actions": []map[string]string{{
+			"type":      "button",
+			"block_id":  "simulated",
+			"action_id": actionID,
+			"value":     value,
+		}},
+	})
+	form := url.Values{"payload": {string(payload)}}
+	return simulateRequest("/interactions", contentTypeFormURLEncoded, []byte(form.Encode()), interactionsHandler)
+}
+
+func simulateRequest(target, contentType string, body []byte, handler http.HandlerFunc) int {
+	r := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	r.Header.Set(contentTypeHeader, contentType)
+	rec := httptest.NewRecorder()
+	handler(rec, r)
+	return rec.Code
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	s "github.com/appsbyram/pkg/http"
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/appsbyram/seafoodtruck-slack/internal/slackbot"
+	"github.com/slack-go/slack"
+)
+
+//slackWebhookChat is the CHAT posting schedules through Slack incoming webhooks, for
+//workspaces that won't install a bot. Schedule channels name webhooks of SLACK_WEBHOOKS,
+//and without a bot token there are no commands, buttons, threads or weekly previews.
+const slackWebhookChat = "slack-webhook"
+
+//slackWebhooks are incoming webhook urls keyed by the channel names schedules use
+var slackWebhooks map[string]string
+
+//slackEventRoutes are the routes Slack calls a bot on, left out when posting through
+//incoming webhooks only
+var slackEventRoutes = map[string]bool{"HomePost": true, "InteractionsPost": true}
+
+//postSlackWebhookEvents posts the events on day at forLocations through the incoming
+//webhook of channel
+func postSlackWebhookEvents(channel, day string, forLocations []string) error {
+	webhook, ok := slackWebhooks[channel]
+	if !ok {
+		return &slackbot.PostError{Reason: i18n.ErrPostEvents, Err: errors.New("no slack webhook for channel " + channel)}
+	}
+	all, err := eventsAt(day, forLocations)
+	if err != nil {
+		return &slackbot.PostError{Reason: i18n.ErrEvents, Err: err}
+	}
+	locale := localeFor(channel)
+	for i, le := range all {
+		if len(le.events) == 0 {
+			if !notifyEmptyFor(channel) {
+				continue
+			}
+			msg := &slack.WebhookMessage{Text: i18n.T(locale, i18n.NoTrucks, le.loc.Name, dayWord(locale, day), dayOf(day).Format("Mon Jan 2"))}
+			if err := slack.PostWebhookContext(context.TODO(), webhook, msg); err != nil {
+				return &slackbot.PostError{Reason: i18n.ErrPostEvents, Err: err}
+			}
+			continue
+		}
+		opts := render.Options{More: i < len(all)-1, Weather: weatherLine(le.loc, le.events, locale), Distance: distanceFrom(channel, le.loc), Order: orderFor(channel),
+			New: newTrucksAt(le.loc.ID, dayOf(day).Format(dayLayout), le.events), Social: socialLinks, MenuItems: menuItems, Day: dayName(day, locale), Style: styleFor(channel), Locale: locale}
+		for _, part := range render.Split(render.Events(le.loc, le.events, proxy.GetTruck, tz, opts)) {
+			blocks := part.Blocks
+			if err := slack.PostWebhookContext(context.TODO(), webhook, &slack.WebhookMessage{Text: part.Text, Blocks: &blocks}); err != nil {
+				return &slackbot.PostError{Reason: i18n.ErrPostEvents, Err: err}
+			}
+		}
+	}
+	return nil
+}
+
+//withoutSlackEvents returns routes without those Slack calls a bot on
+func withoutSlackEvents(routes s.Routes) s.Routes {
+	kept := routes[:0]
+	for _, route := range routes {
+		if !slackEventRoutes[route.Name] {
+			kept = append(kept, route)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/internal/twilio"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"github.com/slack-go/slack/slackevents"
+	"go.uber.org/zap"
+)
+
+var (
+	twilioAccountSID string
+	twilioAuthToken  string
+	twilioFrom       string
+	twilioOnce       sync.Once
+	twilioSender     *twilio.Client
+)
+
+//SMSAlert is where a user wants a text when a favorite truck or cuisine is booked
+type SMSAlert struct {
+	Phone string `json:"phone"`
+	//Location is the id of the user's office location
+	Location string `json:"location"`
+	//Cuisines are food categories, e.g. thai, texted about like favorite trucks
+	Cuisines []string `json:"cuisines,omitempty"`
+}
+
+const (
+	//smsCodeTTL is how long a verification code is good for. A user, and a number, get
+	//one code per smsCodeTTL so the bot can't be used to text strangers.
+	smsCodeTTL = 10 * time.Minute
+	//smsCodeAttempts is how many wrong codes a user may tell before the code is dropped,
+	//so it can't be guessed
+	smsCodeAttempts = 5
+)
+
+//smsPending is a number waiting for the user to tell the code texted to it
+type smsPending struct {
+	Phone    string `json:"phone"`
+	Location string `json:"location"`
+	Code     string `json:"code"`
+	//Attempts counts the wrong codes told so far
+	Attempts int `json:"attempts,omitempty"`
+	//Expires is when the code is no longer good
+	Expires time.Time `json:"expires"`
+}
+
+//smsMu serializes updates of the registrations by location
+var smsMu sync.Mutex
+
+//smsEnabled reports whether TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM are set
+func smsEnabled() bool {
+	return len(twilioAccountSID) > 0 && len(twilioAuthToken) > 0 && len(twilioFrom) > 0
+}
+
+//twilioClient returns the client texting from TWILIO_FROM
+func twilioClient() *twilio.Client {
+	twilioOnce.Do(func() {
+		twilioSender = twilio.New(twilioAccountSID, twilioAuthToken, twilioFrom, &http.Client{Timeout: 10 * time.Second})
+	})
+	return twilioSender
+}
+
+func smsAlertOf(user string) (SMSAlert, error) {
+	var alert SMSAlert
+	err := store.GetJSON(kv, store.SMS, user, &alert)
+	return alert, err
+}
+
+//smsCommand handles sms <number> [at <location id>], sms verify <code>, sms cuisines
+//<categories>, sms off and sms
+func smsCommand(event *slackevents.AppMentionEvent, args string) {
+	if !smsEnabled() {
+		postEphemeral(event, tr(event, i18n.SMSDisabled))
+		return
+	}
+	alert, err := smsAlertOf(event.User)
+	if err != nil && err != store.ErrNotFound {
+		logger.Errorw("Error loading SMS alert", zap.Error(err))
+		postEphemeral(event, tr(event, i18n.SMSSaveFailed))
+		return
+	}
+	registered := err == nil
+
+	switch {
+	case len(args) == 0:
+		if !registered {
+			postEphemeral(event, tr(event, i18n.SMSUsage))
+			return
+		}
+		postEphemeral(event, tr(event, i18n.SMSStatus, alert.Phone, alert.Location, strings.Join(alert.Cuisines, ", ")))
+	case args == "off":
+		if err := indexSMS(event.User, alert.Location, ""); err != nil {
+			logger.Errorw("Error removing SMS alert", zap.Error(err))
+			postEphemeral(event, tr(event, i18n.SMSSaveFailed))
+			return
+		}
+		if err := kv.Delete(store.SMS, event.User); err != nil {
+			logger.Errorw("Error removing SMS alert", zap.Error(err))
+			postEphemeral(event, tr(event, i18n.SMSSaveFailed))
+			return
+		}
+		postEphemeral(event, tr(event, i18n.SMSOff))
+	case strings.HasPrefix(args, "verify"):
+		verifySMS(event, alert, strings.TrimSpace(strings.TrimPrefix(args, "verify")))
+	case args == "cuisines" || strings.HasPrefix(args, "cuisines "):
+		if !registered {
+			postEphemeral(event, tr(event, i18n.SMSUsage))
+			return
+		}
+		alert.Cuisines = nil
+		for _, c := range commands.SplitIDs(strings.TrimPrefix(args, "cuisines")) {
+			alert.Cuisines = append(alert.Cuisines, strings.ToLower(c))
+		}
+		if err := store.PutJSON(kv, store.SMS, event.User, alert); err != nil {
+			logger.Errorw("Error saving SMS alert", zap.Error(err))
+			postEphemeral(event, tr(event, i18n.SMSSaveFailed))
+			return
+		}
+		postEphemeral(event, tr(event, i18n.SMSCuisinesSaved, strings.Join(alert.Cuisines, ", ")))
+	default:
+		number, at := args, ""
+		if i := strings.Index(args, " at "); i >= 0 {
+			number, at = args[:i], strings.TrimSpace(args[i+4:])
+		}
+		phone, ok := twilio.NormalizeNumber(number)
+		if !ok {
+			postEphemeral(event, tr(event, i18n.SMSBadNumber, number))
+			return
+		}
+		if len(at) == 0 {
+			if forLocations := locationsFor(event.Channel); len(forLocations) > 0 {
+				at = forLocations[0]
+			}
+		}
+		loc, err := proxy.GetLocation(at)
+		if len(at) == 0 || err != nil {
+			postEphemeral(event, tr(event, i18n.SMSWhere))
+			return
+		}
+		sendSMSCode(event, phone, loc)
+	}
+}
+
+//sendSMSCode texts phone a code the user has to tell the bot before phone is texted
+//alerts, at most once per smsCodeTTL per user and per number
+func sendSMSCode(event *slackevents.AppMentionEvent, phone string, loc seattlefoodtruck.Location) {
+	code, err := smsCode()
+	if err != nil {
+		logger.Errorw("Error generating SMS code", zap.Error(err))
+		postEphemeral(event, tr(event, i18n.SMSSaveFailed))
+		return
+	}
+	pending, err := json.Marshal(smsPending{Phone: phone, Location: loc.ID, Code: code, Expires: time.Now().Add(smsCodeTTL)})
+	if err != nil {
+		logger.Errorw("Error saving SMS code", zap.Error(err))
+		postEphemeral(event, tr(event, i18n.SMSSaveFailed))
+		return
+	}
+	userKey := "sms-code:" + event.User
+	ok, err := kv.PutIfAbsent(store.Dedup, userKey, pending, smsCodeTTL)
+	if err == nil && ok {
+		if ok, err = kv.PutIfAbsent(store.Dedup, "sms-number:"+phone, []byte(event.User), smsCodeTTL); !ok {
+			kv.Delete(store.Dedup, userKey)
+		}
+	}
+	if err != nil {
+		logger.Errorw("Error saving SMS code", zap.Error(err))
+		postEphemeral(event, tr(event, i18n.SMSSaveFailed))
+		return
+	}
+	if !ok {
+		postEphemeral(event, tr(event, i18n.SMSCodePending))
+		return
+	}
+	//let whoever owns the number know why they're texted and how to stop
+	if err := twilioClient().Send(context.TODO(), phone, tr(event, i18n.SMSCode, code, code, loc.Name)); err != nil {
+		logger.Errorw("Error texting SMS code", zap.Error(err))
+		//the number keeps its limit, the user may try another one
+		kv.Delete(store.Dedup, userKey)
+		postEphemeral(event, tr(event, i18n.SMSSendFailed, phone))
+		return
+	}
+	postEphemeral(event, tr(event, i18n.SMSCodeSent, phone))
+}
+
+//verifySMS saves the number a code was texted to as the one alert is texted to once
+//the user tells the code
+func verifySMS(event *slackevents.AppMentionEvent, alert SMSAlert, code string) {
+	var pending smsPending
+	if err := store.GetJSON(kv, store.Dedup, "sms-code:"+event.User, &pending); err != nil {
+		if err != store.ErrNotFound {
+			logger.Errorw("Error loading SMS code", zap.Error(err))
+		}
+		postEphemeral(event, tr(event, i18n.SMSUsage))
+		return
+	}
+	if len(code) == 0 || subtle.ConstantTimeCompare([]byte(code), []byte(pending.Code)) != 1 {
+		wrongSMSCode(event, pending)
+		return
+	}
+	if err := indexSMS(event.User, alert.Location, pending.Location); err != nil {
+		logger.Errorw("Error saving SMS alert", zap.Error(err))
+		postEphemeral(event, tr(event, i18n.SMSSaveFailed))
+		return
+	}
+	alert.Phone, alert.Location = pending.Phone, pending.Location
+	if err := store.PutJSON(kv, store.SMS, event.User, alert); err != nil {
+		logger.Errorw("Error saving SMS alert", zap.Error(err))
+		postEphemeral(event, tr(event, i18n.SMSSaveFailed))
+		return
+	}
+	if err := kv.Delete(store.Dedup, "sms-code:"+event.User); err != nil {
+		logger.Warnw("Error removing SMS code", zap.Error(err))
+	}
+	name := pending.Location
+	if loc, err := proxy.GetLocation(pending.Location); err == nil {
+		name = loc.Name
+	}
+	postEphemeral(event, tr(event, i18n.SMSSaved, pending.Phone, name))
+}
+
+//wrongSMSCode counts a wrong code told for pending, dropping the code once the user runs
+//out of attempts
+func wrongSMSCode(event *slackevents.AppMentionEvent, pending smsPending) {
+	key := "sms-code:" + event.User
+	pending.Attempts++
+	ttl := time.Until(pending.Expires)
+	if pending.Attempts >= smsCodeAttempts || ttl <= 0 {
+		if err := kv.Delete(store.Dedup, key); err != nil {
+			logger.Warnw("Error removing SMS code", zap.Error(err))
+		}
+		postEphemeral(event, tr(event, i18n.SMSNoAttempts))
+		return
+	}
+	//Put doesn't expire keys, the code is stored again with what is left of its ttl
+	data, err := json.Marshal(pending)
+	if err == nil {
+		err = kv.Delete(store.Dedup, key)
+	}
+	if err == nil {
+		_, err = kv.PutIfAbsent(store.Dedup, key, data, ttl)
+	}
+	if err != nil {
+		logger.Errorw("Error saving SMS code attempts", zap.Error(err))
+	}
+	postEphemeral(event, tr(event, i18n.SMSBadCode))
+}
+
+//smsUsersAt returns the users registered for texts at the location
+func smsUsersAt(locationID string) ([]string, error) {
+	var users []string
+	if err := store.GetJSON(kv, store.SMSLocations, locationID, &users); err != nil && err != store.ErrNotFound {
+		return nil, err
+	}
+	return users, nil
+}
+
+//indexSMS moves user from the registrations at the location from to those at to, either
+//empty to only add or remove them
+func indexSMS(user, from, to string) error {
+	if from == to {
+		return nil
+	}
+	smsMu.Lock()
+	defer smsMu.Unlock()
+
+	if len(from) > 0 {
+		users, err := smsUsersAt(from)
+		if err != nil {
+			return err
+		}
+		kept := users[:0]
+		for _, u := range users {
+			if u != user {
+				kept = append(kept, u)
+			}
+		}
+		if len(kept) == 0 {
+			err = kv.Delete(store.SMSLocations, from)
+		} else {
+			err = store.PutJSON(kv, store.SMSLocations, from, kept)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if len(to) == 0 {
+		return nil
+	}
+	users, err := smsUsersAt(to)
+	if err != nil || contains(users, user) {
+		return err
+	}
+	return store.PutJSON(kv, store.SMSLocations, to, append(users, user))
+}
+
+//indexSMSAlerts adds the alerts saved before registrations were indexed by location
+func indexSMSAlerts() error {
+	all, err := kv.List(store.SMS)
+	if err != nil {
+		return err
+	}
+	for user, data := range all {
+		var alert SMSAlert
+		if err := json.Unmarshal(data, &alert); err != nil {
+			return err
+		}
+		if err := indexSMS(user, "", alert.Location); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//smsCode returns a random 6 digit code
+func smsCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+//textAlerts texts every user registered at loc whose favorite truck or cuisine is
+//booked there today
+func textAlerts(loc seattlefoodtruck.Location, events []seattlefoodtruck.Event) {
+	users, err := smsUsersAt(loc.ID)
+	if err != nil {
+		logger.Errorw("Error loading SMS alerts at "+loc.ID, zap.Error(err))
+		return
+	}
+	day := time.Now().In(tz).Format(dayLayout)
+	for _, user := range users {
+		alert, err := smsAlertOf(user)
+		if err != nil {
+			logger.Warnw("Error loading SMS alert of "+user, zap.Error(err))
+			continue
+		}
+		if alert.Location != loc.ID {
+			continue
+		}
+		favorites, err := favoritesOf(user)
+		if err != nil {
+			logger.Warnw("Error loading favorites of "+user, zap.Error(err))
+		}
+		for _, e := range events {
+			for _, b := range e.Bookings {
+				if !isFavorite(favorites, b.Truck.ID) && !hasCuisine(b.Truck.FoodCategories, alert.Cuisines) {
+					continue
+				}
+				key := fmt.Sprintf("sms:%s:%s:%s:%s", user, b.Truck.ID, loc.ID, day)
+				if ok, err := kv.PutIfAbsent(store.Dedup, key, []byte(day), favoriteAlertTTL); err != nil || !ok {
+					continue
+				}
+				st, _ := time.Parse(time.RFC3339, e.StartTime)
+				et, _ := time.Parse(time.RFC3339, e.EndTime)
+				text := i18n.T(localeOf(user, ""), i18n.SMSAlert, b.Truck.Name, strings.Join(b.Truck.FoodCategories, ", "), loc.Name,
+					st.In(tz).Format(time.Kitchen), et.In(tz).Format(time.Kitchen))
+				if err := twilioClient().Send(context.TODO(), alert.Phone, text); err != nil {
+					logger.Errorw("Error texting favorite alert", zap.Error(err))
+				}
+			}
+		}
+	}
+}
+
+//hasCuisine reports whether any of categories is one of cuisines
+func hasCuisine(categories, cuisines []string) bool {
+	for _, fc := range categories {
+		for _, c := range cuisines {
+			if strings.EqualFold(fc, c) {
+				return true
+			}
+		}
+	}
+	return false
+}
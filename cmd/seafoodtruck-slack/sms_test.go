@@ -0,0 +1,52 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+)
+
+func TestIndexSMS(t *testing.T) {
+	oldKV := kv
+	kv = store.NewMemoryStore()
+	defer func() { kv = oldKV }()
+
+	//U1 registered before registrations were indexed
+	if err := store.PutJSON(kv, store.SMS, "U1", SMSAlert{Phone: "+12065550100", Location: "69"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := indexSMSAlerts(); err != nil {
+		t.Fatal(err)
+	}
+	steps := []struct {
+		name     string
+		user     string
+		from, to string
+		want     map[string][]string
+	}{
+		{"indexed", "", "", "", map[string][]string{"69": {"U1"}}},
+		{"register", "U2", "", "69", map[string][]string{"69": {"U1", "U2"}}},
+		{"register again", "U2", "", "69", map[string][]string{"69": {"U1", "U2"}}},
+		{"move", "U1", "69", "123", map[string][]string{"69": {"U2"}, "123": {"U1"}}},
+		{"off", "U2", "69", "", map[string][]string{"123": {"U1"}}},
+	}
+	for _, step := range steps {
+		if len(step.user) > 0 {
+			if err := indexSMS(step.user, step.from, step.to); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, loc := range []string{"69", "123"} {
+			users, err := smsUsersAt(loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sort.Strings(users)
+			if want := step.want[loc]; !reflect.DeepEqual(users, want) {
+				t.Errorf("%s: users at %s = %q, want %q", step.name, loc, users, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"github.com/slack-go/slack/socketmode"
+	"go.uber.org/zap"
+)
+
+//runSocketMode receives mentions and button clicks over a Socket Mode connection opened
+//with the app-level token, for bots Slack can't reach over http. The client reconnects
+//by itself, it only returns when connecting fails for good.
+func runSocketMode() {
+	client := socketmode.New(slackAPI())
+	go func() {
+		for evt := range client.Events {
+			switch evt.Type {
+			case socketmode.EventTypeConnecting:
+				logger.Info("Connecting to Slack with Socket Mode")
+			case socketmode.EventTypeConnected:
+				logger.Info("Connected to Slack with Socket Mode")
+			case socketmode.EventTypeConnectionError:
+				logger.Warn("Socket Mode connection failed, retrying")
+			case socketmode.EventTypeEventsAPI:
+				client.Ack(*evt.Request)
+				event, ok := evt.Data.(slackevents.EventsAPIEvent)
+				if !ok || event.Type != slackevents.CallbackEvent {
+					continue
+				}
+				var eventID string
+				if cb, ok := event.Data.(*slackevents.EventsAPICallbackEvent); ok {
+					eventID = cb.EventID
+				}
+				if !firstDelivery(eventID) {
+					logger.Infow("Skipping event already handled", "event_id", eventID)
+					continue
+				}
+				answerEvent(logger.With("event_id", eventID), event.InnerEvent)
+			case socketmode.EventTypeInteractive:
+				client.Ack(*evt.Request)
+				cb, ok := evt.Data.(slack.InteractionCallback)
+				if !ok {
+					continue
+				}
+				dispatchActions(&cb)
+			}
+		}
+	}()
+	if err := client.Run(); err != nil {
+		logger.Errorw("Error running Socket Mode", zap.Error(err))
+	}
+}
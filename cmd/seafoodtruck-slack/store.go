@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+)
+
+//ScheduleStore persists schedules created at runtime so they survive restarts
+type ScheduleStore interface {
+	Load() (map[string]Schedule, error)
+	Save(sch Schedule) error
+	Delete(channel string) error
+}
+
+//NewScheduleStore returns a store backed by the json file at path,
+//or keeping schedules in the subscriptions bucket of kv when path is empty
+func NewScheduleStore(path string, kv store.Store) ScheduleStore {
+	if len(path) == 0 {
+		return &kvScheduleStore{kv: kv}
+	}
+	return &fileScheduleStore{path: path}
+}
+
+type kvScheduleStore struct {
+	kv store.Store
+}
+
+func (k *kvScheduleStore) Load() (map[string]Schedule, error) {
+	values, err := k.kv.List(store.Subscriptions)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]Schedule, len(values))
+	for ch, data := range values {
+		var sch Schedule
+		if err := json.Unmarshal(data, &sch); err != nil {
+			return nil, err
+		}
+		result[ch] = sch
+	}
+	return result, nil
+}
+
+func (k *kvScheduleStore) Save(sch Schedule) error {
+	return store.PutJSON(k.kv, store.Subscriptions, sch.Channel, sch)
+}
+
+func (k *kvScheduleStore) Delete(channel string) error {
+	return k.kv.Delete(store.Subscriptions, channel)
+}
+
+type fileScheduleStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (f *fileScheduleStore) Load() (map[string]Schedule, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.read()
+}
+
+func (f *fileScheduleStore) Save(sch Schedule) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	schedules, err := f.read()
+	if err != nil {
+		return err
+	}
+	schedules[sch.Channel] = sch
+	return f.write(schedules)
+}
+
+func (f *fileScheduleStore) Delete(channel string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	schedules, err := f.read()
+	if err != nil {
+		return err
+	}
+	delete(schedules, channel)
+	return f.write(schedules)
+}
+
+func (f *fileScheduleStore) read() (map[string]Schedule, error) {
+	schedules := make(map[string]Schedule)
+
+	data, err := ioutil.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return schedules, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &schedules); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (f *fileScheduleStore) write(schedules map[string]Schedule) error {
+	data, err := json.MarshalIndent(schedules, "", "  ")
+	if err != nil {
+		return err
+	}
+	//write to a temp file and rename so a crash never leaves a truncated store
+	tmp, err := ioutil.TempFile(filepath.Dir(f.path), filepath.Base(f.path))
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
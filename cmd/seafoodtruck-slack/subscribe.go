@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"go.uber.org/zap"
+)
+
+const (
+	weekdaysSpec = "%d %d * * MON-FRI"
+)
+
+func subscribe(event *slackevents.AppMentionEvent, args string) {
+	if !isAdmin(event.User) {
+		postEphemeral(event, tr(event, i18n.SubscribeAdmin))
+		return
+	}
+	var ids, at string
+
+	i := strings.LastIndex(args, " at ")
+	if i < 0 {
+		ids = args
+	} else {
+		ids, at = args[:i], args[i+4:]
+	}
+	forLocations := commands.SplitIDs(strings.Replace(ids, " ", ",", -1))
+	if len(forLocations) == 0 {
+		postEphemeral(event, tr(event, i18n.SubscribeWhich))
+		return
+	}
+	t := time.Date(0, 1, 1, 8, 0, 0, 0, tz)
+	if len(at) > 0 {
+		var err error
+		if t, err = commands.ParseTime(at, tz); err != nil {
+			postEphemeral(event, tr(event, i18n.SubscribeBadTime, at))
+			return
+		}
+	}
+	sch := Schedule{
+		Channel:   event.Channel,
+		Locations: forLocations,
+		Spec:      fmt.Sprintf(weekdaysSpec, t.Minute(), t.Hour()),
+		Day:       today,
+	}
+	if err := saveSchedule(sch); err != nil {
+		logger.Errorw("Error saving subscription", zap.Error(err))
+		postEphemeral(event, tr(event, i18n.SubscribeFailed))
+		return
+	}
+	postEphemeral(event, tr(event, i18n.Subscribed, event.Channel, strings.Join(forLocations, ", "), t.Format(time.Kitchen), tz))
+}
+
+func unsubscribe(event *slackevents.AppMentionEvent) {
+	if !isAdmin(event.User) {
+		postEphemeral(event, tr(event, i18n.SubscribeAdmin))
+		return
+	}
+	schedulesMu.RLock()
+	_, ok := schedules[event.Channel]
+	schedulesMu.RUnlock()
+
+	if !ok {
+		postEphemeral(event, tr(event, i18n.NoSubscription))
+		return
+	}
+	if err := deleteSchedule(event.Channel); err != nil {
+		logger.Errorw("Error removing subscription", zap.Error(err))
+		postEphemeral(event, tr(event, i18n.UnsubscribeFailed))
+		return
+	}
+	postEphemeral(event, tr(event, i18n.Unsubscribed, event.Channel))
+}
+
+func postEphemeral(event *slackevents.AppMentionEvent, text string) {
+	if _, err := slackAPI().PostEphemeral(event.Channel, event.User, slack.MsgOptionText(text, false)); err != nil {
+		logger.Errorw("Error posting ephemeral message", zap.Error(err))
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/appsbyram/seafoodtruck-slack/internal/telegram"
+	"go.uber.org/zap"
+)
+
+const (
+	//telegramChat is the CHAT posting schedules with the Telegram bot of TELEGRAM_TOKEN,
+	//schedule channels being chat ids or @channel usernames
+	telegramChat = "telegram"
+	//telegramPoll is how many seconds a long poll for updates waits
+	telegramPoll = 50
+)
+
+var (
+	telegramToken string
+	telegramOnce  sync.Once
+	//telegramBot outlives long polls, its requests time out only after them
+	telegramBot *telegram.Client
+)
+
+//telegramClient returns the client of the bot of TELEGRAM_TOKEN
+func telegramClient() *telegram.Client {
+	telegramOnce.Do(func() {
+		telegramBot = telegram.New(telegramToken, &http.Client{Timeout: (telegramPoll + 10) * time.Second})
+	})
+	return telegramBot
+}
+
+//sendTelegramEvents sends the events on day at forLocations to the Telegram chat
+//chatID, a message per location
+func sendTelegramEvents(chatID, day string, forLocations []string, order, locale string) error {
+	all, err := eventsAt(day, forLocations)
+	if err != nil {
+		return err
+	}
+	for _, le := range all {
+		text := telegram.Escape(strings.Replace(i18n.T(locale, i18n.NoTrucks, le.loc.Name, dayWord(locale, day), dayOf(day).Format("Mon Jan 2")), "*", "", -1))
+		if len(le.events) > 0 {
+			opts := render.Options{Order: order, Locale: locale, New: newTrucksAt(le.loc.ID, dayOf(day).Format(dayLayout), le.events)}
+			text = render.Telegram(le.loc, le.events, proxy.GetTruck, tz, opts)
+		}
+		if err := telegramClient().SendMessage(context.TODO(), chatID, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//postTelegramEvents posts the events on day at forLocations into the Telegram chat of
+//channel
+func postTelegramEvents(channel, day string, forLocations []string) error {
+	return sendTelegramEvents(channel, day, forLocations, orderFor(channel), localeFor(channel))
+}
+
+//runTelegram long polls for the commands sent to the Telegram bot and answers them
+//until the process exits, backing off while Telegram can't be reached
+func runTelegram() {
+	var offset int64
+	backoff := time.Second
+	for {
+		updates, err := telegramClient().GetUpdates(context.TODO(), offset, telegramPoll)
+		if err != nil {
+			logger.Warnw("Error polling Telegram for updates", zap.Error(err))
+			time.Sleep(backoff)
+			if backoff *= 2; backoff > time.Minute {
+				backoff = time.Minute
+			}
+			continue
+		}
+		backoff = time.Second
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			if u.Message == nil {
+				continue
+			}
+			msg := *u.Message
+			safeGo("", func() { answerTelegram(msg) })
+		}
+	}
+}
+
+//answerTelegram answers /today and /tomorrow [location ids or group], /truck <truck>
+//and /help
+func answerTelegram(msg telegram.Message) {
+	chatID := strconv.FormatInt(msg.Chat.ID, 10)
+	locale := localeFor(chatID)
+	command, args := telegram.CommandOf(msg.Text)
+	var err error
+	switch command {
+	case "":
+		return
+	case today, tomorrow:
+		forLocations := locationsFor(chatID)
+		if len(args) > 0 {
+			forLocations = expandLocations(commands.SplitIDs(strings.Replace(args, " ", ",", -1)))
+		}
+		if len(forLocations) == 0 {
+			err = telegramClient().SendMessage(context.TODO(), chatID, telegram.Escape(i18n.T(locale, i18n.ErrNoLocations)))
+			break
+		}
+		if err = sendTelegramEvents(chatID, command, forLocations, orderFor(chatID), locale); err != nil {
+			logger.Errorw("Error sending events to Telegram", zap.Error(err))
+			err = telegramClient().SendMessage(context.TODO(), chatID, telegram.Escape(i18n.T(locale, i18n.ErrEvents)))
+		}
+	case commands.Truck:
+		t, lookupErr := lookupTruck(args)
+		if lookupErr != nil {
+			err = telegramClient().SendMessage(context.TODO(), chatID, telegram.Escape(strings.Replace(i18n.T(locale, i18n.TruckNotFound, args), "*", "", -1)))
+			break
+		}
+		photo, caption := render.TelegramTruck(t)
+		if len(photo) == 0 {
+			err = telegramClient().SendMessage(context.TODO(), chatID, caption)
+			break
+		}
+		err = telegramClient().SendPhoto(context.TODO(), chatID, photo, caption)
+	default:
+		err = telegramClient().SendMessage(context.TODO(), chatID, telegram.Escape(strings.Join([]string{i18n.T(locale, i18n.HelpTitle),
+			"/today [location ids or group]",
+			"/tomorrow [location ids or group]",
+			"/truck <truck>",
+		}, "\n")))
+	}
+	if err != nil {
+		logger.Errorw("Error answering Telegram", "chat", chatID, zap.Error(err))
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	s "github.com/appsbyram/pkg/http"
+)
+
+const (
+	defaultTriggerLimit = 50
+	maxTriggerLimit     = 100
+)
+
+//TriggerBooking is a truck booked for an event, flat as polling triggers like Zapier's
+//and IFTTT's expect. ID stays the same across polls so they only fire for new bookings.
+type TriggerBooking struct {
+	ID         string    `json:"id"`
+	EventID    int       `json:"event_id"`
+	TruckID    string    `json:"truck_id"`
+	Truck      string    `json:"truck"`
+	Categories string    `json:"categories"`
+	TruckURL   string    `json:"truck_url"`
+	Group      string    `json:"group,omitempty"`
+	LocationID string    `json:"location_id"`
+	Location   string    `json:"location"`
+	Address    string    `json:"address"`
+	Date       string    `json:"date"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+}
+
+//triggerBookings flattens feed into its bookings matching category and truck, parts of
+//a food category and truck name, latest first
+func triggerBookings(feed []FeedDay, category, truck string) []TriggerBooking {
+	category, truck = strings.ToLower(category), strings.ToLower(truck)
+	bookings := []TriggerBooking{}
+	for _, day := range feed {
+		for _, e := range day.Events {
+			for _, t := range e.Trucks {
+				categories := strings.Join(t.Categories, ", ")
+				if !strings.Contains(strings.ToLower(categories), category) || !strings.Contains(strings.ToLower(t.Name), truck) {
+					continue
+				}
+				bookings = append(bookings, TriggerBooking{
+					ID:         fmt.Sprintf("%v-%s", e.ID, t.ID),
+					EventID:    e.ID,
+					TruckID:    t.ID,
+					Truck:      t.Name,
+					Categories: categories,
+					TruckURL:   fmt.Sprintf(truckURL, t.ID),
+					Group:      e.Group,
+					LocationID: e.LocationID,
+					Location:   e.Location,
+					Address:    e.Address,
+					Date:       day.Date,
+					Start:      e.Start,
+					End:        e.End,
+				})
+			}
+		}
+	}
+	sort.SliceStable(bookings, func(i, j int) bool {
+		if !bookings[i].Start.Equal(bookings[j].Start) {
+			return bookings[i].Start.After(bookings[j].Start)
+		}
+		return bookings[i].ID > bookings[j].ID
+	})
+	return bookings
+}
+
+//triggerHandler serves the upcoming bookings at the configured location groups, latest
+//first, for Zapier and IFTTT polling triggers such as new taco truck at HQ
+func triggerHandler(w http.ResponseWriter, r *http.Request) {
+	limit := defaultTriggerLimit
+	if l := r.URL.Query().Get("limit"); len(l) > 0 {
+		limit, _ = strconv.Atoi(l)
+	}
+	if limit < 1 || limit > maxTriggerLimit {
+		writeError(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxTriggerLimit))
+		return
+	}
+	feed, ok := loadFeed(w, r)
+	if !ok {
+		return
+	}
+	bookings := triggerBookings(feed, r.URL.Query().Get("category"), r.URL.Query().Get("truck"))
+	if len(bookings) > limit {
+		bookings = bookings[:limit]
+	}
+	s.NewPayload().WriteResponse(s.ContentTypeJSON, http.StatusOK, &bookings, w)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+	"go.uber.org/zap"
+)
+
+//truckCommand shows the user who asked the details and photos of the truck named by args
+func truckCommand(event *slackevents.AppMentionEvent, args string) {
+	t, err := lookupTruck(args)
+	if err != nil {
+		postEphemeral(event, tr(event, i18n.TruckNotFound, args))
+		return
+	}
+	postTruckDetail(event.Channel, event.User, render.TruckDetail(t))
+}
+
+//showTruckDetails answers the details option of a truck's menu in a post with a modal
+func showTruckDetails(cb *slack.InteractionCallback, action *slack.BlockAction) {
+	t, err := proxy.GetTruck(action.SelectedOption.Value)
+	if err != nil {
+		logger.Errorw("Error getting truck details", "truck", action.SelectedOption.Value, zap.Error(err))
+		return
+	}
+	showModal(cb, "Truck details", render.TruckDetail(t))
+}
+
+//showFullMenu answers the Full menu button under a menu preview with a modal
+func showFullMenu(cb *slack.InteractionCallback, action *slack.BlockAction) {
+	t, err := proxy.GetTruck(action.Value)
+	if err != nil {
+		logger.Errorw("Error getting truck menu", "truck", action.Value, zap.Error(err))
+		return
+	}
+	showModal(cb, "Menu", render.Menu(t))
+}
+
+//showModal opens msg in a modal for the user who clicked, falling back to an ephemeral
+//message when the modal can't be opened
+func showModal(cb *slack.InteractionCallback, title string, msg slack.Message) {
+	view := slack.ModalViewRequest{
+		Type:   slack.VTModal,
+		Title:  slack.NewTextBlockObject("plain_text", title, false, false),
+		Close:  slack.NewTextBlockObject("plain_text", "Close", false, false),
+		Blocks: msg.Blocks,
+	}
+	if len(cb.TriggerID) > 0 {
+		_, err := slackAPI().OpenView(cb.TriggerID, view)
+		if err == nil {
+			return
+		}
+		logger.Warnw("Error opening modal, posting ephemeral message", zap.Error(err))
+	}
+	postTruckDetail(cb.Channel.ID, cb.User.ID, msg)
+}
+
+func postTruckDetail(channel, user string, msg slack.Message) {
+	if _, err := slackAPI().PostEphemeral(channel, user, slack.MsgOptionText(msg.Text, false), slack.MsgOptionBlocks(msg.Blocks.BlockSet...)); err != nil {
+		logger.Errorw("Error posting truck details", zap.Error(err))
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/scheduler"
+	"github.com/slack-go/slack"
+)
+
+//checkDeployment checks what the configuration points at exists: the bot token, the
+//channel and locations of every schedule, and the specs of every job. It writes a
+//table of what will run where and when to out and returns the problems found.
+func checkDeployment(out io.Writer) []string {
+	var problems []string
+
+	if _, err := slackAPI().AuthTest(); err != nil {
+		problems = append(problems, "token: "+err.Error())
+	}
+
+	schedulesMu.RLock()
+	configured := make(map[string]Schedule, len(schedules))
+	channels := make([]string, 0, len(schedules))
+	for ch, sch := range schedules {
+		configured[ch] = sch
+		channels = append(channels, ch)
+	}
+	schedulesMu.RUnlock()
+	sort.Strings(channels)
+
+	now := time.Now().In(tz)
+	checked := make(map[string]string)
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHANNEL\tJOB\tSPEC\tNEXT RUN\tLOCATIONS")
+	for _, ch := range channels {
+		sch := configured[ch]
+		name := ch
+		if info, err := slackAPI().GetConversationInfo(&slack.GetConversationInfoInput{ChannelID: ch}); err != nil {
+			problems = append(problems, fmt.Sprintf("channel %s: %v", ch, err))
+		} else {
+			name = "#" + info.Name
+		}
+
+		ids := expandLocations(sch.Locations)
+		names := make([]string, 0, len(ids))
+		for _, id := range ids {
+			if _, ok := checked[id]; !ok {
+				loc, err := proxy.GetLocation(id)
+				if err != nil {
+					problems = append(problems, fmt.Sprintf("location %s of channel %s: %v", id, ch, err))
+				}
+				checked[id] = loc.Name
+			}
+			names = append(names, firstNonEmpty(checked[id], id))
+		}
+		if len(ids) == 0 {
+			problems = append(problems, "channel "+ch+" has no locations")
+		}
+
+		for _, job := range []struct{ kind, spec string }{
+			{postsJob, sch.Spec},
+			{eveningJob, firstNonEmpty(sch.EveningSpec, eveningSpec)},
+			{weeklyJob, firstNonEmpty(sch.WeeklySpec, weeklyPreviewSpec)},
+		} {
+			if len(job.spec) == 0 {
+				continue
+			}
+			next := "-"
+			if parsed, err := scheduler.Parse(job.spec); err != nil {
+				problems = append(problems, fmt.Sprintf("%s spec of channel %s: %v", job.kind, ch, err))
+			} else {
+				next = parsed.Next(now).Format("Mon Jan 2 15:04 MST")
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", name, job.kind, job.spec, next, strings.Join(names, ", "))
+		}
+	}
+	tw.Flush()
+	return problems
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/internal/schedule"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	changeAdded     = "added"
+	changeCancelled = "cancelled"
+	changeMoved     = "moved"
+)
+
+//ScheduleChange describes how the booking of a truck at a location changed
+type ScheduleChange struct {
+	Kind     string                    `json:"kind"`
+	Location seattlefoodtruck.Location `json:"location"`
+	TruckID  string                    `json:"truck_id"`
+	Truck    string                    `json:"truck"`
+	Start    time.Time                 `json:"start"`
+	End      time.Time                 `json:"end"`
+}
+
+var (
+	snapshotsMu sync.Mutex
+	//snapshots are keyed by channel and location id
+	snapshots = make(map[string]schedule.Snapshot)
+)
+
+//watch re-fetches today's events every interval and announces changes
+//to the channels with schedules for the affected locations
+func watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		pollChanges()
+	}
+}
+
+func pollChanges() {
+	schedulesMu.RLock()
+	watched := make(map[string][]string)
+	for ch, sch := range schedules {
+		for _, id := range expandLocations(sch.Locations) {
+			watched[id] = append(watched[id], ch)
+		}
+	}
+	schedulesMu.RUnlock()
+
+	day := time.Now().In(tz).Format(dayLayout)
+	for id, channels := range watched {
+		loc, err := proxy.GetLocation(id)
+		if err != nil {
+			logger.Warnw("Error getting location while watching", zap.Error(err))
+			continue
+		}
+		events, err := proxy.GetEvents(id, today)
+		if err != nil {
+			logger.Warnw("Error getting events while watching", zap.Error(err))
+			continue
+		}
+		archiveEvents(loc, day, events)
+		notifyFavorites(loc, events)
+		current := schedule.TakeSnapshot(day, events)
+		if changes := diffSnapshot(changeStreamKey, loc, current); len(changes) > 0 {
+			publishChanges(changes)
+			sendChangeWebhooks(changes)
+			publishChangesMQTT(changes)
+		}
+		for _, ch := range channels {
+			if changes := diffSnapshot(ch, loc, current); len(changes) > 0 {
+				announceChanges(ch, loc, changes)
+			}
+		}
+	}
+}
+
+//diffSnapshot stores current as the latest snapshot of channel and location and returns
+//the changes since the previous one. The first snapshot of a day is only a baseline.
+func diffSnapshot(channel string, loc seattlefoodtruck.Location, current schedule.Snapshot) []ScheduleChange {
+	var changes []ScheduleChange
+
+	key := channel + ":" + loc.ID
+	snapshotsMu.Lock()
+	previous, ok := snapshots[key]
+	snapshots[key] = current
+	snapshotsMu.Unlock()
+
+	if !ok || previous.Day != current.Day {
+		return nil
+	}
+	var added, cancelled []schedule.Slot
+	for key, s := range current.Slots {
+		p, existed := previous.Slots[key]
+		switch {
+		case !existed:
+			added = append(added, s)
+		case !p.End.Equal(s.End):
+			changes = append(changes, ScheduleChange{changeMoved, loc, s.TruckID, s.Truck, s.Start, s.End})
+		}
+	}
+	for key, p := range previous.Slots {
+		if _, exists := current.Slots[key]; !exists {
+			cancelled = append(cancelled, p)
+		}
+	}
+	schedule.SortSlots(added)
+	schedule.SortSlots(cancelled)
+	//a truck whose start changed left one slot for another, it moved
+	for _, s := range added {
+		kind := changeAdded
+		for i, p := range cancelled {
+			if p.TruckID == s.TruckID {
+				kind = changeMoved
+				cancelled = append(cancelled[:i], cancelled[i+1:]...)
+				break
+			}
+		}
+		changes = append(changes, ScheduleChange{kind, loc, s.TruckID, s.Truck, s.Start, s.End})
+	}
+	for _, p := range cancelled {
+		changes = append(changes, ScheduleChange{changeCancelled, loc, p.TruckID, p.Truck, p.Start, p.End})
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Truck < changes[j].Truck
+	})
+	return changes
+}
+
+func announceChanges(channel string, loc seattlefoodtruck.Location, changes []ScheduleChange) {
+	var sb strings.Builder
+
+	locale := localeFor(channel)
+	sb.WriteString(i18n.T(locale, i18n.ChangesHeader, fmt.Sprintf(locationScheduleURL, loc.ID), loc.Name) + "\n")
+	for _, ch := range changes {
+		window := fmt.Sprintf("%s–%s", ch.Start.In(tz).Format(time.Kitchen), ch.End.In(tz).Format(time.Kitchen))
+		switch ch.Kind {
+		case changeAdded:
+			sb.WriteString(i18n.T(locale, i18n.ChangeAdded, ch.Truck, window) + "\n")
+		case changeCancelled:
+			sb.WriteString(i18n.T(locale, i18n.ChangeCancelled, ch.Truck) + "\n")
+		case changeMoved:
+			sb.WriteString(i18n.T(locale, i18n.ChangeMoved, ch.Truck, window) + "\n")
+		}
+	}
+	if _, _, err := postMessage(channel, slack.MsgOptionText(sb.String(), false)); err != nil {
+		logger.Errorw("Error posting schedule update", zap.Error(err))
+	}
+}
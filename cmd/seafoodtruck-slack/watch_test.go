@@ -0,0 +1,40 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/schedule"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+)
+
+//twice books a truck over lunch and again for dinner, dinner ending at end
+func twice(end string) []seattlefoodtruck.Event {
+	lunch := booked("marination")
+	dinner := booked("marination")
+	dinner[0].StartTime, dinner[0].EndTime = "2020-06-01T17:00:00-07:00", end
+	return append(lunch, dinner...)
+}
+
+func TestDiffSnapshotBookedTwice(t *testing.T) {
+	westlake := seattlefoodtruck.Location{ID: "69", Name: "Westlake"}
+	snap := schedule.TakeSnapshot("2020-06-01", twice("2020-06-01T20:00:00-07:00"))
+	if len(snap.Slots) != 2 {
+		t.Fatalf("slots of a truck booked twice = %v, want 2", len(snap.Slots))
+	}
+	diffSnapshot("test", westlake, snap)
+
+	changes := diffSnapshot("test", westlake, schedule.TakeSnapshot("2020-06-01", twice("2020-06-01T21:00:00-07:00")))
+	var kinds []string
+	for _, c := range changes {
+		kinds = append(kinds, c.Kind+" "+c.TruckID+" "+c.End.Format("15:04"))
+	}
+	if want := []string{changeMoved + " marination 21:00"}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("changes = %q, want %q", kinds, want)
+	}
+
+	changes = diffSnapshot("test", westlake, schedule.TakeSnapshot("2020-06-01", booked("marination")))
+	if len(changes) != 1 || changes[0].Kind != changeCancelled || changes[0].Start.Hour() != 17 {
+		t.Errorf("changes = %+v, want dinner cancelled", changes)
+	}
+}
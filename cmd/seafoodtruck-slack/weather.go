@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/weather"
+	"go.uber.org/zap"
+)
+
+//forecaster forecasts the weather at locations, nil unless weather_api_key is set
+var forecaster weather.Client
+
+//weatherLine returns the forecast at loc for when the first of events starts, in locale,
+//e.g. ":umbrella_with_rain_drops: 70% rain at 11:00AM, 54°F — maybe eat inside". It is
+//empty when weather is disabled, the place of loc is unknown or the forecast fails.
+func weatherLine(loc seattlefoodtruck.Location, events []seattlefoodtruck.Event, locale string) string {
+	if forecaster == nil || len(events) == 0 || (loc.Latitude == 0 && loc.Longitude == 0) {
+		return ""
+	}
+	start, err := time.Parse(time.RFC3339, events[0].StartTime)
+	if err != nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	f, err := forecaster.Forecast(ctx, loc.Latitude, loc.Longitude, start)
+	if err != nil {
+		logger.Warnw("Error forecasting weather", "location", loc.ID, zap.Error(err))
+		return ""
+	}
+	return formatForecast(f, start.In(tz), locale)
+}
+
+func formatForecast(f weather.Forecast, at time.Time, locale string) string {
+	when := i18n.T(locale, i18n.WeatherAt, at.Format(time.Kitchen))
+	if at.Hour() == 12 && at.Minute() == 0 {
+		when = i18n.T(locale, i18n.WeatherNoon)
+	}
+	chance := int(math.Round(f.PrecipitationChance * 100))
+	switch {
+	case f.Condition == weather.Snow:
+		return i18n.T(locale, i18n.WeatherSnow, chance, when, f.Temperature)
+	case f.Wet() || f.PrecipitationChance >= 0.5:
+		return i18n.T(locale, i18n.WeatherRain, chance, when, f.Temperature)
+	case f.Condition == weather.Clear && f.Temperature >= 65:
+		return i18n.T(locale, i18n.WeatherWarm, f.Description, when, f.Temperature)
+	case f.Condition == weather.Clear:
+		return i18n.T(locale, i18n.WeatherClear, f.Description, when, f.Temperature)
+	}
+	return i18n.T(locale, i18n.WeatherCloudy, firstNonEmpty(f.Description, f.Condition), when, f.Temperature)
+}
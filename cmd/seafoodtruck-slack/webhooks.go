@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"go.uber.org/zap"
+)
+
+const (
+	//webhookSchedule and webhookChange are the types of webhook payloads
+	webhookSchedule = "schedule"
+	webhookChange   = "change"
+	//webhookSignatureHeader carries sha256=<hex hmac of "<timestamp>.<body>"> keyed with
+	//WEBHOOK_SECRET, webhookTimestampHeader the unix timestamp it signs
+	webhookSignatureHeader = "X-Seafoodtruck-Signature"
+	webhookTimestampHeader = "X-Seafoodtruck-Timestamp"
+	webhookAttempts        = 3
+)
+
+var (
+	webhookClient = &http.Client{Timeout: 10 * time.Second}
+	//webhookURLs are comma separated and webhookSecret signs what is posted to them
+	webhookURLs   string
+	webhookSecret string
+)
+
+//WebhookPayload is posted as JSON to the webhook urls: the schedule of a channel when
+//it is posted, or a change the watcher found
+type WebhookPayload struct {
+	Type    string          `json:"type"`
+	Channel string          `json:"channel,omitempty"`
+	Date    string          `json:"date"`
+	Sent    time.Time       `json:"sent"`
+	Events  []FeedEvent     `json:"events,omitempty"`
+	Change  *ScheduleChange `json:"change,omitempty"`
+}
+
+//webhookSchedulePayload returns the payload of the events on day at forLocations posted
+//into channel
+func webhookSchedulePayload(channel, day string, forLocations []string) (WebhookPayload, error) {
+	p := WebhookPayload{Type: webhookSchedule, Channel: channel, Date: dayOf(day).Format(dayLayout), Events: []FeedEvent{}}
+	for _, id := range forLocations {
+		loc, err := proxy.GetLocation(id)
+		if err != nil {
+			return p, err
+		}
+		events, err := proxy.GetEvents(id, day)
+		if err != nil {
+			return p, err
+		}
+		for _, e := range events {
+			if fe, ok := feedEvent(e); ok {
+				fe.LocationID, fe.Location, fe.Address = loc.ID, loc.Name, loc.Address
+				p.Events = append(p.Events, fe)
+			}
+		}
+	}
+	return p, nil
+}
+
+//sendScheduleWebhooks posts the schedule of channel to the webhook urls in the
+//background
+func sendScheduleWebhooks(channel, day string, forLocations []string) {
+	if len(webhookURLs) == 0 {
+		return
+	}
+	safeGo("", func() {
+		p, err := webhookSchedulePayload(channel, day, forLocations)
+		if err != nil {
+			logger.Errorw("Error building schedule webhook", zap.Error(err))
+			return
+		}
+		sendWebhooks(p)
+	})
+}
+
+//sendChangeWebhooks posts each of changes to the webhook urls in the background
+func sendChangeWebhooks(changes []ScheduleChange) {
+	if len(webhookURLs) == 0 {
+		return
+	}
+	safeGo("", func() {
+		for i := range changes {
+			sendWebhooks(WebhookPayload{Type: webhookChange, Date: changes[i].Start.In(tz).Format(dayLayout), Change: &changes[i]})
+		}
+	})
+}
+
+//sendWebhooks posts p to every webhook url, retrying each a few times
+func sendWebhooks(p WebhookPayload) {
+	p.Sent = time.Now().UTC()
+	body, err := json.Marshal(p)
+	if err != nil {
+		logger.Errorw("Error encoding webhook", zap.Error(err))
+		return
+	}
+	for _, u := range commands.SplitIDs(webhookURLs) {
+		backoff := retryBackoff
+		for attempt := 1; attempt <= webhookAttempts; attempt++ {
+			if err = postWebhook(u, body); err == nil {
+				break
+			}
+			logger.Warnw("Error posting webhook", "url", u, "type", p.Type, "attempt", attempt, zap.Error(err))
+			if attempt < webhookAttempts {
+				time.Sleep(backoff)
+				backoff *= 2
+			}
+		}
+	}
+}
+
+//postWebhook posts body to url, signed with WEBHOOK_SECRET when set
+func postWebhook(url string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(contentTypeHeader, "application/json")
+	if len(webhookSecret) > 0 {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(webhookTimestampHeader, ts)
+		req.Header.Set(webhookSignatureHeader, "sha256="+signWebhook(webhookSecret, ts, body))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webhookClient.Timeout)
+	defer cancel()
+	resp, err := webhookClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+//signWebhook returns the hex HMAC-SHA256 of "<ts>.<body>" keyed with secret, which
+//receivers recompute to check a payload came from the bot
+func signWebhook(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
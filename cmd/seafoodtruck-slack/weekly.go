@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/appsbyram/seafoodtruck-slack/internal/schedule"
+	"github.com/appsbyram/seafoodtruck-slack/internal/slackbot"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/slack-go/slack"
+)
+
+const standoutTrucks = 2
+
+func postWeeklyPreview(channel string, forLocations []string) error {
+	days := schedule.WeekAhead(time.Now().In(tz))
+	ratings := make(map[string]float64)
+	booked := 0
+	counts := make([]int, len(days))
+
+	ht := fmt.Sprintf("*Week ahead* %s – %s", days[0].Format("Jan 2"), days[len(days)-1].Format("Jan 2"))
+	msg := slack.NewBlockMessage(
+		slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", ht, false, false), nil, nil),
+		slack.NewDividerBlock(),
+	)
+	for _, id := range forLocations {
+		loc, err := proxy.GetLocation(id)
+		if err != nil {
+			return err
+		}
+		events, err := proxy.GetEventsBetween(context.TODO(), id, days[0], days[len(days)-1])
+		if err != nil {
+			return err
+		}
+
+		byDay := make(map[string][]seattlefoodtruck.Booking)
+		for _, e := range events {
+			st, err := time.Parse(time.RFC3339, e.StartTime)
+			if err != nil {
+				continue
+			}
+			key := st.In(tz).Format("2006-01-02")
+			byDay[key] = append(byDay[key], e.Bookings...)
+		}
+
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("*<%s|%s>*\n", fmt.Sprintf(locationScheduleURL, loc.ID), loc.Name))
+		for i, d := range days {
+			bookings := byDay[d.Format("2006-01-02")]
+			counts[i] += len(bookings)
+			sb.WriteString(fmt.Sprintf("*%s* ", d.Format("Mon Jan 2")))
+			if len(bookings) == 0 {
+				sb.WriteString("no trucks\n")
+				continue
+			}
+			booked += len(bookings)
+			sb.WriteString(fmt.Sprintf("%v truck(s)", len(bookings)))
+			if standouts := pickStandouts(bookings, ratings); len(standouts) > 0 {
+				sb.WriteString(" — " + strings.Join(standouts, ", "))
+			}
+			sb.WriteString("\n")
+		}
+		msg = slack.AddBlockMessage(msg, slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", sb.String(), false, false), nil, nil))
+	}
+	if len(chartURL) > 0 {
+		msg = slack.AddBlockMessage(msg, render.TruckCountChart(chartURL, days, counts))
+	}
+	msg.Text = fmt.Sprintf("Week ahead %s – %s: %v truck booking(s)", days[0].Format("Jan 2"), days[len(days)-1].Format("Jan 2"), booked)
+	_, ts, err := postMessage(channel, slack.MsgOptionText(msg.Text, false), slack.MsgOptionBlocks(msg.Blocks.BlockSet...))
+	if err == nil && len(ts) > 0 {
+		publisher.Posts.Record(slackbot.PostKey(channel, weeklyPostID, days[0].Format(dayLayout)), channel, ts, nil, schedule.Snapshot{Day: days[0].Format(dayLayout)})
+	}
+	return err
+}
+
+//pickStandouts returns the best rated trucks of bookings, caching ratings across calls
+func pickStandouts(bookings []seattlefoodtruck.Booking, ratings map[string]float64) []string {
+	trucks := make([]seattlefoodtruck.BookingTruck, 0, len(bookings))
+	for _, b := range bookings {
+		if _, ok := ratings[b.Truck.ID]; !ok {
+			if truck, err := proxy.GetTruck(b.Truck.ID); err == nil {
+				ratings[b.Truck.ID] = truck.Rating
+			} else {
+				ratings[b.Truck.ID] = 0
+			}
+		}
+		trucks = append(trucks, b.Truck)
+	}
+	sort.SliceStable(trucks, func(i, j int) bool {
+		return ratings[trucks[i].ID] > ratings[trucks[j].ID]
+	})
+
+	var standouts []string
+	for i := 0; i < len(trucks) && i < standoutTrucks; i++ {
+		if r := ratings[trucks[i].ID]; r > 0 {
+			standouts = append(standouts, fmt.Sprintf("%s %.1f%s", trucks[i].Name, r, render.BlackStar))
+		}
+	}
+	return standouts
+}
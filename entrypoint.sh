@@ -6,6 +6,10 @@ echo "Command :" $CMD
 case $CMD in
     "start")
         echo "Starting Golang application"
-        exec ./bot
+        exec ./bot serve
+    ;;
+    "lambda")
+        echo "Starting Lambda runtime"
+        exec ./bot lambda
     ;;
 esac
\ No newline at end of file
@@ -3,9 +3,24 @@ module github.com/appsbyram/seafoodtruck-slack
 go 1.12
 
 require (
+	github.com/alicebob/miniredis/v2 v2.9.0
 	github.com/appsbyram/pkg v0.0.0-20190917152251-80fede0fd883
-	github.com/gorilla/websocket v1.4.1 // indirect
-	github.com/nlopes/slack v0.6.0
-	github.com/robfig/cron v1.2.0
+	github.com/aws/aws-sdk-go v1.25.43
+	github.com/fsnotify/fsnotify v1.4.7
+	github.com/go-redis/redis v6.15.9+incompatible
+	github.com/golang/protobuf v1.3.2
+	github.com/gorilla/mux v1.7.3
+	github.com/graphql-go/graphql v0.7.9
+	github.com/mattn/go-sqlite3 v1.14.22
+	github.com/mitchellh/mapstructure v1.1.2
+	github.com/onsi/ginkgo v1.10.1 // indirect
+	github.com/onsi/gomega v1.7.0 // indirect
+	github.com/robfig/cron/v3 v3.0.1
+	github.com/slack-go/slack v0.12.5
+	github.com/spf13/cobra v0.0.5
+	github.com/spf13/pflag v1.0.3
+	github.com/spf13/viper v1.4.0
 	go.uber.org/zap v1.10.0
+	golang.org/x/crypto v0.0.0-20191011191535-87dc89f01550
+	google.golang.org/grpc v1.25.1
 )
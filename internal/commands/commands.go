@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//The commands the bot answers to
+const (
+	Help          = "help"
+	FindEvents    = "find events"
+	Subscribe     = "subscribe"
+	Unsubscribe   = "unsubscribe"
+	Favorite      = "favorite"
+	Unfavorite    = "unfavorite"
+	Favorites     = "favorites"
+	Poll          = "poll"
+	Stats         = "stats"
+	Leaderboard   = "leaderboard"
+	Prefs         = "prefs"
+	Jobs          = "jobs"
+	PauseJob      = "pause job"
+	ResumeJob     = "resume job"
+	RescheduleJob = "reschedule job"
+	PostSchedule  = "post schedule"
+	Truck         = "truck"
+	SMS           = "sms"
+)
+
+//Command is a parsed mention, Name is empty when the text isn't a known command
+type Command struct {
+	Name string
+	//Args is whatever follows the command, trimmed
+	Args string
+}
+
+//prefixed are the commands taking arguments, matched in order so longer commands
+//sharing a prefix come first
+var prefixed = []struct {
+	name string
+	//bare allows the command without arguments
+	bare bool
+}{
+	{PauseJob, true},
+	{ResumeJob, true},
+	{RescheduleJob, true},
+	{PostSchedule, true},
+	{Unfavorite, false},
+	{Favorite, false},
+	{Leaderboard, true},
+	{Prefs, true},
+	{Subscribe, false},
+	{Truck, false},
+	{SMS, true},
+}
+
+//exact are the commands taking no arguments
+var exact = []string{Help, Unsubscribe, Jobs, Favorites, Poll, Stats}
+
+//Parse parses the text of a mention, dropping the leading <@bot> mention
+func Parse(text string) Command {
+	if i := strings.Index(text, ">"); i >= 0 {
+		text = text[i+1:]
+	}
+	text = strings.TrimSpace(text)
+	if strings.Contains(text, FindEvents) {
+		if i := strings.Index(text, " for"); i > 0 && strings.ToLower(strings.TrimSpace(text[:i])) == FindEvents {
+			return Command{Name: FindEvents, Args: strings.TrimSpace(text[i+4:])}
+		}
+		if text == FindEvents {
+			return Command{Name: FindEvents}
+		}
+		return Command{Args: text}
+	}
+	for _, name := range exact {
+		if text == name {
+			return Command{Name: name}
+		}
+	}
+	for _, c := range prefixed {
+		if c.bare && text == c.name {
+			return Command{Name: c.name}
+		}
+		if strings.HasPrefix(text, c.name+" ") {
+			return Command{Name: c.name, Args: strings.TrimSpace(text[len(c.name):])}
+		}
+	}
+	return Command{Args: text}
+}
+
+//FindEventsArgs splits the arguments of find events, <day> [by <order>] [at <location
+//ids or group>], locations being nil and order empty when not given
+func FindEventsArgs(args string) (string, []string, string) {
+	var order string
+	if i := strings.Index(args, " by "); i >= 0 {
+		rest := strings.TrimSpace(args[i+4:])
+		end := strings.Index(rest, " ")
+		if end < 0 {
+			end = len(rest)
+		}
+		order = rest[:end]
+		args = args[:i] + rest[end:]
+	}
+	i := strings.Index(args, " at ")
+	if i < 0 {
+		return args, nil, order
+	}
+	return strings.TrimSpace(args[:i]), SplitIDs(strings.Replace(args[i+4:], " ", ",", -1)), order
+}
+
+//SplitIDs splits a comma separated list, dropping blanks
+func SplitIDs(ids string) []string {
+	var result []string
+	for _, id := range strings.Split(ids, ",") {
+		if id = strings.TrimSpace(id); len(id) > 0 {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+var timeLayouts = []string{"15:04", "3:04pm", "3pm", "15"}
+
+//ParseTime parses a time of day such as 11:30, 11:30am or 11am in loc
+func ParseTime(s string, loc *time.Location) (time.Time, error) {
+	s = strings.Replace(strings.ToLower(s), " ", "", -1)
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("Unrecognized time " + s)
+}
+
+var windows = map[string]int{
+	"week":  7,
+	"month": 30,
+	"year":  365,
+}
+
+//ParseWindow parses week, month, year or a number of days such as 14 days,
+//defaulting to defaultDays when s is empty
+func ParseWindow(s string, defaultDays int) (int, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if len(s) == 0 {
+		return defaultDays, nil
+	}
+	if days, ok := windows[strings.TrimPrefix(s, "this ")]; ok {
+		return days, nil
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(s, "days"), "day"), "d")
+	days, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || days < 1 {
+		return 0, fmt.Errorf("Unrecognized window %s", s)
+	}
+	return days, nil
+}
@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text string
+		want Command
+	}{
+		{"<@U123> help", Command{Name: Help}},
+		{"  <@U123>   help  ", Command{Name: Help}},
+		{"help", Command{Name: Help}},
+		{"<@U123> find events for today", Command{Name: FindEvents, Args: "today"}},
+		{"<@U123> find events for tomorrow by rating at slu", Command{Name: FindEvents, Args: "tomorrow by rating at slu"}},
+		{"<@U123> find events", Command{Name: FindEvents}},
+		{"<@U123> please find events", Command{Args: "please find events"}},
+		{"<@U123> subscribe 69,123 at 8:30am", Command{Name: Subscribe, Args: "69,123 at 8:30am"}},
+		{"<@U123> subscribe", Command{Args: "subscribe"}},
+		{"<@U123> unsubscribe", Command{Name: Unsubscribe}},
+		{"<@U123> favorite marination", Command{Name: Favorite, Args: "marination"}},
+		{"<@U123> unfavorite marination", Command{Name: Unfavorite, Args: "marination"}},
+		{"<@U123> favorites", Command{Name: Favorites}},
+		{"<@U123> leaderboard", Command{Name: Leaderboard}},
+		{"<@U123> leaderboard 14 days", Command{Name: Leaderboard, Args: "14 days"}},
+		{"<@U123> prefs compact on", Command{Name: Prefs, Args: "compact on"}},
+		{"<@U123> pause job", Command{Name: PauseJob}},
+		{"<@U123> pause job 3", Command{Name: PauseJob, Args: "3"}},
+		{"<@U123> reschedule job 3 0 11 * * *", Command{Name: RescheduleJob, Args: "3 0 11 * * *"}},
+		{"<@U123> sms verify 123456", Command{Name: SMS, Args: "verify 123456"}},
+		{"<@U123> truck", Command{Args: "truck"}},
+		{"<@U123> trucks", Command{Args: "trucks"}},
+		{"<@U123> what's for lunch", Command{Args: "what's for lunch"}},
+		{"", Command{}},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.text); got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestFindEventsArgs(t *testing.T) {
+	tests := []struct {
+		args      string
+		day       string
+		locations []string
+		order     string
+	}{
+		{"today", "today", nil, ""},
+		{"tomorrow at 69", "tomorrow", []string{"69"}, ""},
+		{"today at 69, 123", "today", []string{"69", "123"}, ""},
+		{"today at 69 123", "today", []string{"69", "123"}, ""},
+		{"today by rating", "today", nil, "rating"},
+		{"today by name at slu", "today", []string{"slu"}, "name"},
+		{"today at slu by category", "today", []string{"slu"}, "category"},
+	}
+	for _, tt := range tests {
+		day, locations, order := FindEventsArgs(tt.args)
+		if day != tt.day || !reflect.DeepEqual(locations, tt.locations) || order != tt.order {
+			t.Errorf("FindEventsArgs(%q) = %q, %q, %q, want %q, %q, %q", tt.args, day, locations, order, tt.day, tt.locations, tt.order)
+		}
+	}
+}
+
+func TestSplitIDs(t *testing.T) {
+	tests := []struct {
+		ids  string
+		want []string
+	}{
+		{"", nil},
+		{"69", []string{"69"}},
+		{" 69, 123 ,,slu ", []string{"69", "123", "slu"}},
+		{",", nil},
+	}
+	for _, tt := range tests {
+		if got := SplitIDs(tt.ids); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitIDs(%q) = %q, want %q", tt.ids, got, tt.want)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		s            string
+		hour, minute int
+		ok           bool
+	}{
+		{"11:30", 11, 30, true},
+		{"8:30am", 8, 30, true},
+		{"2:15PM", 14, 15, true},
+		{"11am", 11, 0, true},
+		{"3 pm", 15, 0, true},
+		{"13", 13, 0, true},
+		{"noon", 0, 0, false},
+		{"25:00", 0, 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.s, time.UTC)
+		if (err == nil) != tt.ok {
+			t.Errorf("ParseTime(%q) error = %v, want ok %v", tt.s, err, tt.ok)
+			continue
+		}
+		if tt.ok && (got.Hour() != tt.hour || got.Minute() != tt.minute) {
+			t.Errorf("ParseTime(%q) = %v, want %02d:%02d", tt.s, got.Format("15:04"), tt.hour, tt.minute)
+		}
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+		ok   bool
+	}{
+		{"", 30, true},
+		{"week", 7, true},
+		{"this month", 30, true},
+		{"YEAR", 365, true},
+		{"14 days", 14, true},
+		{"1 day", 1, true},
+		{"10d", 10, true},
+		{"0 days", 0, false},
+		{"fortnight", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseWindow(tt.s, 30)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseWindow(%q) = %v, %v, want %v, ok %v", tt.s, got, err, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+//Package commands parses what users ask the bot when they mention it. It knows the
+//commands and their arguments but not what the bot does with them.
+package commands
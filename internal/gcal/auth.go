@@ -0,0 +1,147 @@
+package gcal
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//Scope grants reading and writing events
+	Scope = "https://www.googleapis.com/auth/calendar.events"
+	//TokenURL exchanges signed assertions for access tokens
+	TokenURL = "https://oauth2.googleapis.com/token"
+	//tokenLifetime is how long asked for access tokens last, the most Google allows
+	tokenLifetime = time.Hour
+)
+
+//ServiceAccount holds the fields of a service account JSON key the client needs
+type ServiceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+//LoadServiceAccount reads the JSON key of a service account from path
+func LoadServiceAccount(path string) (ServiceAccount, error) {
+	var sa ServiceAccount
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return sa, err
+	}
+	if err := json.Unmarshal(data, &sa); err != nil {
+		return sa, err
+	}
+	if len(sa.ClientEmail) == 0 || len(sa.PrivateKey) == 0 {
+		return sa, errors.New("gcal: " + path + " is not a service account key")
+	}
+	if len(sa.TokenURI) == 0 {
+		sa.TokenURI = TokenURL
+	}
+	return sa, nil
+}
+
+//tokenSource hands out access tokens of a service account, asking for a new one
+//shortly before the last expires
+type tokenSource struct {
+	account ServiceAccount
+	key     *rsa.PrivateKey
+	client  *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func newTokenSource(sa ServiceAccount, client *http.Client) (*tokenSource, error) {
+	block, _ := pem.Decode([]byte(sa.PrivateKey))
+	if block == nil {
+		return nil, errors.New("gcal: private_key is not PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("gcal: private_key is not an RSA key")
+	}
+	return &tokenSource{account: sa, key: key, client: client}, nil
+}
+
+//Token returns a valid access token
+func (ts *tokenSource) Token(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if len(ts.token) > 0 && time.Now().Before(ts.expires.Add(-time.Minute)) {
+		return ts.token, nil
+	}
+	assertion, err := ts.assertion(time.Now())
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"}, "assertion": {assertion}}
+	req, err := http.NewRequest(http.MethodPost, ts.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := ts.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if len(body.AccessToken) == 0 {
+		return "", errors.New("gcal: token: " + body.Error + " " + body.Description)
+	}
+	ts.token = body.AccessToken
+	ts.expires = time.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	return ts.token, nil
+}
+
+//assertion returns the JWT the service account signs to ask for a token at now
+func (ts *tokenSource) assertion(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   ts.account.ClientEmail,
+		"scope": Scope,
+		"aud":   ts.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(tokenLifetime).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, ts.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
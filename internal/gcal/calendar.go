@@ -0,0 +1,131 @@
+package gcal
+
+import (
+	"bytes"
+	"context"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//APIURL is where the events of a calendar are
+const APIURL = "https://www.googleapis.com/calendar/v3/calendars/%s/events"
+
+//Event is a calendar event, only the fields the bot sets
+type Event struct {
+	ID          string    `json:"id"`
+	Summary     string    `json:"summary"`
+	Description string    `json:"description,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	Start       EventTime `json:"start"`
+	End         EventTime `json:"end"`
+	//Status is confirmed so updating an event deleted earlier brings it back
+	Status string `json:"status"`
+}
+
+//EventTime is when an event starts or ends, DateTime being RFC 3339 in TimeZone
+type EventTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+//Client keeps the events of a calendar
+type Client struct {
+	calendarID string
+	client     *http.Client
+	tokens     *tokenSource
+}
+
+//New returns a Client of the calendar calendarID, e.g. abc@group.calendar.google.com,
+//acting as sa, which the calendar must be shared with
+func New(calendarID string, sa ServiceAccount, client *http.Client) (*Client, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	tokens, err := newTokenSource(sa, client)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{calendarID: calendarID, client: client, tokens: tokens}, nil
+}
+
+//EventID returns an event id made of parts. Calendar ids only take the digits and
+//the letters a to v, which is lowercase base32hex, so the parts are joined and encoded.
+func EventID(parts ...string) string {
+	id := eventIDEncoding.EncodeToString([]byte(strings.Join(parts, "/")))
+	return "sft" + strings.ToLower(id)
+}
+
+var eventIDEncoding = base32.HexEncoding.WithPadding(base32.NoPadding)
+
+//Upsert updates e, inserting it when the calendar doesn't have it yet
+func (c *Client) Upsert(ctx context.Context, e Event) error {
+	e.Status = "confirmed"
+	err := c.do(ctx, http.MethodPut, "/"+url.PathEscape(e.ID), e)
+	if err == errNotFound {
+		err = c.do(ctx, http.MethodPost, "", e)
+	}
+	return err
+}
+
+//Delete removes the event id, events already gone are not an error
+func (c *Client) Delete(ctx context.Context, id string) error {
+	if err := c.do(ctx, http.MethodDelete, "/"+url.PathEscape(id), nil); err != nil && err != errNotFound && err != errGone {
+		return err
+	}
+	return nil
+}
+
+var (
+	errNotFound = errors.New("gcal: not found")
+	errGone     = errors.New("gcal: gone")
+)
+
+//do sends body as JSON to the events of the calendar followed by path
+func (c *Client) do(ctx context.Context, method, path string, body interface{}) error {
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, fmt.Sprintf(APIURL, url.PathEscape(c.calendarID))+path, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errNotFound
+	case resp.StatusCode == http.StatusGone:
+		return errGone
+	case resp.StatusCode/100 != 2:
+		var e struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&e)
+		return fmt.Errorf("gcal: %s %s", resp.Status, e.Error.Message)
+	}
+	return nil
+}
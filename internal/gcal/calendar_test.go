@@ -0,0 +1,32 @@
+package gcal
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestEventID(t *testing.T) {
+	valid := regexp.MustCompile(`^[0-9a-v]{5,}$`)
+	tests := []struct {
+		parts []string
+	}{
+		{[]string{"123", "4567"}},
+		{[]string{"12", "34567"}},
+		{[]string{"seattle-center", "42"}},
+		{[]string{"1"}},
+	}
+	seen := make(map[string][]string)
+	for _, tt := range tests {
+		id := EventID(tt.parts...)
+		if !valid.MatchString(id) {
+			t.Errorf("EventID(%q) = %q, not a valid calendar event id", tt.parts, id)
+		}
+		if other, ok := seen[id]; ok {
+			t.Errorf("EventID(%q) = EventID(%q) = %q", tt.parts, other, id)
+		}
+		seen[id] = tt.parts
+		if again := EventID(tt.parts...); again != id {
+			t.Errorf("EventID(%q) = %q then %q", tt.parts, id, again)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+//Package gcal keeps events on a Google Calendar through the Calendar API v3,
+//https://developers.google.com/calendar/api/v3/reference/events, authenticating as a
+//service account the calendar is shared with.
+package gcal
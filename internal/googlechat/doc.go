@@ -0,0 +1,4 @@
+//Package googlechat holds the plumbing between the bot and Google Chat: the events a
+//Chat app receives, verifying who sent them, and the cards v2 messages it answers and
+//posts through incoming webhooks with.
+package googlechat
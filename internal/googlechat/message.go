@@ -0,0 +1,143 @@
+package googlechat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+//Event types a Chat app receives, https://developers.google.com/chat/api/reference/rest/v1/EventType
+const (
+	AddedToSpace = "ADDED_TO_SPACE"
+	MessageEvent = "MESSAGE"
+)
+
+//Event is what Google Chat posts to the app when it's messaged or added to a space
+type Event struct {
+	Type    string `json:"type"`
+	Message struct {
+		Text string `json:"text"`
+		//ArgumentText is Text without the mention of the app
+		ArgumentText string `json:"argumentText"`
+	} `json:"message"`
+	Space struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"space"`
+	User struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"user"`
+}
+
+//Message is a message of text and cards, https://developers.google.com/chat/api/reference/rest/v1/spaces.messages
+type Message struct {
+	Text    string       `json:"text,omitempty"`
+	CardsV2 []CardWithID `json:"cardsV2,omitempty"`
+}
+
+//CardWithID is a card of a message
+type CardWithID struct {
+	CardID string `json:"cardId"`
+	Card   Card   `json:"card"`
+}
+
+//Card is a cards v2 card, https://developers.google.com/chat/api/reference/rest/v1/cards
+type Card struct {
+	Header   *CardHeader `json:"header,omitempty"`
+	Sections []Section   `json:"sections"`
+}
+
+//CardHeader heads a card
+type CardHeader struct {
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle,omitempty"`
+	ImageURL string `json:"imageUrl,omitempty"`
+	ImageAlt string `json:"imageAltText,omitempty"`
+}
+
+//Section groups the widgets of a card under an optional header
+type Section struct {
+	Header  string   `json:"header,omitempty"`
+	Widgets []Widget `json:"widgets"`
+}
+
+//Widget is one of the widgets of a section, exactly one field being set
+type Widget struct {
+	DecoratedText *DecoratedText `json:"decoratedText,omitempty"`
+	TextParagraph *TextParagraph `json:"textParagraph,omitempty"`
+	ButtonList    *ButtonList    `json:"buttonList,omitempty"`
+}
+
+//DecoratedText is text with labels, an icon and a button. Text takes the basic HTML
+//Chat formats.
+type DecoratedText struct {
+	TopLabel    string   `json:"topLabel,omitempty"`
+	Text        string   `json:"text"`
+	BottomLabel string   `json:"bottomLabel,omitempty"`
+	WrapText    bool     `json:"wrapText,omitempty"`
+	StartIcon   *Icon    `json:"startIcon,omitempty"`
+	OnClick     *OnClick `json:"onClick,omitempty"`
+	Button      *Button  `json:"button,omitempty"`
+}
+
+//TextParagraph is a paragraph of formatted text
+type TextParagraph struct {
+	Text string `json:"text"`
+}
+
+//ButtonList lays buttons out in a row
+type ButtonList struct {
+	Buttons []Button `json:"buttons"`
+}
+
+//Button is a text button
+type Button struct {
+	Text    string  `json:"text"`
+	OnClick OnClick `json:"onClick"`
+}
+
+//OnClick opens a link when a widget is clicked
+type OnClick struct {
+	OpenLink struct {
+		URL string `json:"url"`
+	} `json:"openLink"`
+}
+
+//Icon is an image shown beside a widget
+type Icon struct {
+	IconURL   string `json:"iconUrl,omitempty"`
+	AltText   string `json:"altText,omitempty"`
+	ImageType string `json:"imageType,omitempty"`
+}
+
+//OpenLink returns the OnClick opening url
+func OpenLink(url string) OnClick {
+	var o OnClick
+	o.OpenLink.URL = url
+	return o
+}
+
+//Post posts msg through the incoming webhook of a space at url
+func Post(ctx context.Context, client *http.Client, url string, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("google chat webhook answered %s", resp.Status)
+	}
+	return nil
+}
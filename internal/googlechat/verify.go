@@ -0,0 +1,172 @@
+package googlechat
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//Issuer signs the bearer tokens of the requests Google Chat sends to apps
+	Issuer = "chat@system.gserviceaccount.com"
+	//CertsURL holds the certificates of Issuer by key id
+	CertsURL = "https://www.googleapis.com/service_accounts/v1/metadata/x509/" + Issuer
+	//certsTTL is how long fetched certificates are trusted before they're fetched again
+	certsTTL = time.Hour
+	//certsRefetch is how soon after a fetch tokens of unknown key ids may fetch again
+	certsRefetch = time.Minute
+)
+
+//Verifier checks the bearer tokens Google Chat puts on the requests it sends an app,
+//https://developers.google.com/chat/api/guides/message-formats#verify_bearer_tokens
+type Verifier struct {
+	//Audience is the project number of the app, every request fails when empty
+	Audience string
+	Client   *http.Client
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	expires time.Time
+	fetched time.Time
+}
+
+//Verify checks the Authorization header of r carries a token Issuer signed for Audience
+//that hasn't expired
+func (v *Verifier) Verify(r *http.Request) error {
+	if len(v.Audience) == 0 {
+		return errors.New("no audience to verify the token for")
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("missing bearer token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return err
+	}
+	if header.Alg != "RS256" {
+		return fmt.Errorf("unexpected token algorithm %s", header.Alg)
+	}
+	key, err := v.key(r.Context(), header.Kid)
+	if err != nil {
+		return err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return err
+	}
+	var claims struct {
+		Iss string `json:"iss"`
+		Aud string `json:"aud"`
+		Exp int64  `json:"exp"`
+	}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return err
+	}
+	switch {
+	case claims.Iss != Issuer:
+		return fmt.Errorf("token issued by %s", claims.Iss)
+	case claims.Aud != v.Audience:
+		return fmt.Errorf("token for audience %s", claims.Aud)
+	case time.Now().Unix() > claims.Exp:
+		return errors.New("token expired")
+	}
+	return nil
+}
+
+//key returns the public key of Issuer with id kid, fetching the certificates again when
+//they're stale or don't have it. Fetches are at least certsRefetch apart so tokens with
+//made up key ids don't fetch on every request.
+func (v *Verifier) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := time.Now()
+	key, ok := v.keys[kid]
+	if ok && now.Before(v.expires) {
+		return key, nil
+	}
+	if now.Sub(v.fetched) < certsRefetch {
+		if ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown token key %s", kid)
+	}
+	v.fetched = now
+	keys, err := fetchKeys(ctx, v.Client)
+	if err != nil {
+		return nil, err
+	}
+	v.keys, v.expires = keys, now.Add(certsTTL)
+	key, ok = keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown token key %s", kid)
+	}
+	return key, nil
+}
+
+//fetchKeys returns the public keys of the certificates at CertsURL by key id
+func fetchKeys(ctx context.Context, client *http.Client) (map[string]*rsa.PublicKey, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequest(http.MethodGet, CertsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching google chat certificates: %s", resp.Status)
+	}
+	var certs map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&certs); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]*rsa.PublicKey, len(certs))
+	for kid, certPEM := range certs {
+		block, _ := pem.Decode([]byte(certPEM))
+		if block == nil {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		if key, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+			keys[kid] = key
+		}
+	}
+	return keys, nil
+}
+
+//decodeSegment decodes a base64url JSON segment of a token into v
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
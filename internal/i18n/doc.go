@@ -0,0 +1,3 @@
+//Package i18n holds the message catalogs of what the bot says, keyed by locale, so a
+//workspace can run the bot in its language. Commands are still typed in English.
+package i18n
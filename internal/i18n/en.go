@@ -0,0 +1,352 @@
+package i18n
+
+//The messages of the catalogs
+const (
+	HelpTitle         Key = "help.title"
+	HelpFindEvents    Key = "help.find_events"
+	HelpSubscribe     Key = "help.subscribe"
+	HelpUnsubscribe   Key = "help.unsubscribe"
+	HelpFavorite      Key = "help.favorite"
+	HelpTruck         Key = "help.truck"
+	HelpFavorites     Key = "help.favorites"
+	HelpPoll          Key = "help.poll"
+	HelpStats         Key = "help.stats"
+	HelpLeaderboard   Key = "help.leaderboard"
+	HelpPrefsDiets    Key = "help.prefs_diets"
+	HelpPrefsCompact  Key = "help.prefs_compact"
+	HelpPrefsAccess   Key = "help.prefs_accessible"
+	HelpPrefsLocale   Key = "help.prefs_locale"
+	UnknownCommand    Key = "unknown_command"
+	KnownOrders       Key = "known_orders"
+	ErrNoLocations    Key = "err.no_locations"
+	ErrLocation       Key = "err.location"
+	ErrEvents         Key = "err.events"
+	ErrUpdateEvents   Key = "err.update_events"
+	ErrPostEvents     Key = "err.post_events"
+	ErrPostSomeEvents Key = "err.post_some_events"
+	Today             Key = "day.today"
+	Tomorrow          Key = "day.tomorrow"
+	NoTrucks          Key = "no_trucks"
+	NoTrucksAhead     Key = "no_trucks.none_ahead"
+	NextTrucks        Key = "no_trucks.next"
+
+	TrucksOn      Key = "events.trucks_on"
+	Matching      Key = "events.matching"
+	ImGoing       Key = "events.im_going"
+	Going         Key = "events.going"
+	Directions    Key = "events.directions"
+	NewHere       Key = "events.new_here"
+	TruckDetails  Key = "events.truck_details"
+	FullMenu      Key = "events.full_menu"
+	Reviews       Key = "events.reviews"
+	EventDate     Key = "events.date"
+	TruckCount    Key = "events.truck_count"
+	SummaryOn     Key = "summary.on"
+	SummaryTrucks Key = "summary.trucks"
+	SummaryTruck  Key = "summary.truck"
+	SummaryNone   Key = "summary.none"
+	Address       Key = "accessible.address"
+	NewHereText   Key = "accessible.new_here"
+	RatedOutOf    Key = "accessible.rated"
+	FoodLine      Key = "accessible.food"
+	DietsLine     Key = "accessible.diets"
+	CreditCards   Key = "accessible.credit_cards"
+	TruckPhotoAlt Key = "accessible.photo_alt"
+
+	SubscribeAdmin    Key = "subscribe.admin_only"
+	SubscribeWhich    Key = "subscribe.which"
+	SubscribeBadTime  Key = "subscribe.bad_time"
+	SubscribeFailed   Key = "subscribe.failed"
+	Subscribed        Key = "subscribe.done"
+	NoSubscription    Key = "unsubscribe.none"
+	UnsubscribeFailed Key = "unsubscribe.failed"
+	Unsubscribed      Key = "unsubscribe.done"
+
+	FavoritesLoadFailed Key = "favorites.load_failed"
+	FavoritesNone       Key = "favorites.none"
+	FavoritesList       Key = "favorites.list"
+	TruckNotFound       Key = "truck.not_found"
+	FavoritesSaveFailed Key = "favorites.save_failed"
+	FavoriteRemoved     Key = "favorites.removed"
+	FavoriteAdded       Key = "favorites.added"
+
+	PrefsList          Key = "prefs.list"
+	PrefsNone          Key = "prefs.none"
+	PrefsSaveFailed    Key = "prefs.save_failed"
+	PrefsCompactOn     Key = "prefs.compact_on"
+	PrefsCompactOff    Key = "prefs.compact_off"
+	PrefsAccessibleOn  Key = "prefs.accessible_on"
+	PrefsClearFailed   Key = "prefs.clear_failed"
+	PrefsCleared       Key = "prefs.cleared"
+	PrefsUnknownDiet   Key = "prefs.unknown_diet"
+	PrefsSaved         Key = "prefs.saved"
+	PrefsUsage         Key = "prefs.usage"
+	PrefsUnknownLocale Key = "prefs.unknown_locale"
+	PrefsLocaleSaved   Key = "prefs.locale_saved"
+
+	HelpSMS          Key = "help.sms"
+	SMSDisabled      Key = "sms.disabled"
+	SMSUsage         Key = "sms.usage"
+	SMSStatus        Key = "sms.status"
+	SMSOff           Key = "sms.off"
+	SMSCuisinesSaved Key = "sms.cuisines_saved"
+	SMSBadNumber     Key = "sms.bad_number"
+	SMSWhere         Key = "sms.where"
+	SMSSaveFailed    Key = "sms.save_failed"
+	SMSSendFailed    Key = "sms.send_failed"
+	SMSSaved         Key = "sms.saved"
+	SMSCode          Key = "sms.code"
+	SMSCodeSent      Key = "sms.code_sent"
+	SMSCodePending   Key = "sms.code_pending"
+	SMSBadCode       Key = "sms.bad_code"
+	SMSNoAttempts    Key = "sms.no_attempts"
+	SMSAlert         Key = "sms.alert"
+
+	BriefingTitle  Key = "briefing.title"
+	BriefingTrucks Key = "briefing.trucks"
+	BriefingTruck  Key = "briefing.truck"
+	BriefingNone   Key = "briefing.none"
+
+	PollHeader       Key = "poll.header"
+	PollClosedHeader Key = "poll.closed_header"
+	PollVotes        Key = "poll.votes"
+	PollVote         Key = "poll.vote"
+	PollNoWinner     Key = "poll.no_winner"
+	PollWinner       Key = "poll.winner"
+	PollText         Key = "poll.text"
+	PollClosedText   Key = "poll.closed_text"
+	PollClosedWinner Key = "poll.closed_winner"
+	PollNoTrucks     Key = "poll.no_trucks"
+	PollNoVotes      Key = "poll.no_votes"
+	PollGoing        Key = "poll.going"
+
+	ArchiveReadFailed     Key = "archive.read_failed"
+	LeaderboardUsage      Key = "leaderboard.usage"
+	LeaderboardDays       Key = "leaderboard.days"
+	LeaderboardVotes      Key = "leaderboard.votes"
+	LeaderboardNoBookings Key = "leaderboard.no_bookings"
+	LeaderboardNoVotes    Key = "leaderboard.no_votes"
+	LeaderboardTitle      Key = "leaderboard.title"
+	LeaderboardMostBooked Key = "leaderboard.most_booked"
+	LeaderboardMostVoted  Key = "leaderboard.most_voted"
+	LeaderboardText       Key = "leaderboard.text"
+	LeaderboardLeads      Key = "leaderboard.leads"
+	StatsNone             Key = "stats.none"
+	StatsTruck            Key = "stats.truck"
+	StatsPerLocation      Key = "stats.per_location"
+	StatsTitle            Key = "stats.title"
+	StatsMostFrequent     Key = "stats.most_frequent"
+	StatsAverage          Key = "stats.average"
+	StatsBusiest          Key = "stats.busiest"
+	StatsText             Key = "stats.text"
+
+	JobsAdminOnly Key = "jobs.admin_only"
+	JobsFailed    Key = "jobs.failed"
+	JobsDone      Key = "jobs.done"
+	JobsNone      Key = "jobs.none"
+	JobLine       Key = "jobs.line"
+	JobPaused     Key = "jobs.paused"
+	PostAdminOnly Key = "admin.post_only"
+
+	WeatherAt     Key = "weather.at"
+	WeatherNoon   Key = "weather.noon"
+	WeatherSnow   Key = "weather.snow"
+	WeatherRain   Key = "weather.rain"
+	WeatherWarm   Key = "weather.warm"
+	WeatherClear  Key = "weather.clear"
+	WeatherCloudy Key = "weather.cloudy"
+
+	MapLabel          Key = "map.label"
+	MapAlt            Key = "map.alt"
+	WalkDistance      Key = "distance.walk"
+	Distance          Key = "distance.away"
+	ChangesHeader     Key = "changes.header"
+	ChangeAdded       Key = "changes.added"
+	ChangeCancelled   Key = "changes.cancelled"
+	ChangeMoved       Key = "changes.moved"
+	RepostAbove       Key = "repost.above"
+	RepostEarlier     Key = "repost.earlier"
+	RepostUnchanged   Key = "repost.unchanged"
+	RepostChanged     Key = "repost.changed"
+	Reminder          Key = "reminder"
+	HolidayNote       Key = "holiday.note"
+	OfficeHolidayNote Key = "holiday.office_note"
+	FavoriteAlert     Key = "favorites.alert"
+	PanicApology      Key = "panic.apology"
+)
+
+var en = map[Key]string{
+	HelpTitle:         "You can ask me",
+	HelpFindEvents:    "%s for <today/tomorrow> [by <rating/name/category>] [at <location ids or group>] - to see events booked",
+	HelpSubscribe:     "%s <location ids or group> at <time> - to get events posted here every weekday",
+	HelpUnsubscribe:   "%s - to stop the daily post in this channel",
+	HelpFavorite:      "%s <truck> - to get a DM when a truck you like is booked nearby",
+	HelpTruck:         "%s <truck> - to see its photos and details",
+	HelpFavorites:     "%s - to list your favorite trucks",
+	HelpPoll:          "%s - to vote on where to get lunch today",
+	HelpStats:         "%s - to see this month's most frequent trucks and busiest days",
+	HelpLeaderboard:   "%s [week/month/year/<n> days] - to see the most booked and most voted trucks",
+	HelpPrefsDiets:    "%s set <vegetarian,vegan,gluten_free,paleo> - to only see trucks that fit your diet",
+	HelpPrefsCompact:  "%s compact on/off - to see a compact list without images",
+	HelpPrefsAccess:   "%s accessible on/off - to get posts that read well with a screen reader",
+	HelpPrefsLocale:   "%s locale <%s> - to get answers in your language",
+	UnknownCommand:    "Sorry I cannot help you with this, please try help to see things you can ask me",
+	KnownOrders:       "I can list trucks by %s",
+	ErrNoLocations:    "locations not set",
+	ErrLocation:       "Sorry I'm having trouble getting location details",
+	ErrEvents:         "Sorry I'm having trouble getting events",
+	ErrUpdateEvents:   "Sorry I couldn't update the events",
+	ErrPostEvents:     "Sorry I couldn't post the events",
+	ErrPostSomeEvents: "Sorry I couldn't post all the events",
+	Today:             "today",
+	Tomorrow:          "tomorrow",
+	NoTrucks:          "No trucks booked at *%s* %s (%s)",
+	NoTrucksAhead:     " — no trucks booked in the next %v days",
+	NextTrucks:        " — next trucks: %s",
+
+	TrucksOn:      "*%v truck(s)* on %s from %s–%s ",
+	Matching:      "matching your preferences (%v booked)",
+	ImGoing:       "I'm going :walking:",
+	Going:         "Going: %s",
+	Directions:    "Directions :world_map:",
+	NewHere:       ":new: *New here!*",
+	TruckDetails:  "Photos & details",
+	FullMenu:      "Full menu (%d)",
+	Reviews:       "%v reviews",
+	EventDate:     "%[1]s, %[2]s %[3]d",
+	TruckCount:    "%v truck(s)",
+	SummaryOn:     "on %s",
+	SummaryTrucks: "%d trucks%s at %s",
+	SummaryTruck:  "%d truck%s at %s",
+	SummaryNone:   "No trucks%s at %s",
+	Address:       "Address: %s",
+	NewHereText:   "New here",
+	RatedOutOf:    "Rated %.1f out of 5 from %v reviews",
+	FoodLine:      "Food: %s",
+	DietsLine:     "Diets: %s",
+	CreditCards:   "Takes credit cards",
+	TruckPhotoAlt: "Photo of the %s food truck",
+
+	SubscribeAdmin:    "Sorry, only admins can subscribe or unsubscribe channels",
+	SubscribeWhich:    "Please tell me which locations, e.g. subscribe 69,123 at 8:30am",
+	SubscribeBadTime:  "Sorry I don't understand the time %s, try something like 8:30am",
+	SubscribeFailed:   "Sorry I couldn't save the subscription",
+	Subscribed:        "Subscribed <#%s> to trucks at locations %s every weekday at %s (%s)",
+	NoSubscription:    "This channel has no subscription",
+	UnsubscribeFailed: "Sorry I couldn't remove the subscription",
+	Unsubscribed:      "Unsubscribed <#%s>, no more daily posts here",
+
+	FavoritesLoadFailed: "Sorry I couldn't load your favorites",
+	FavoritesNone:       "You have no favorite trucks yet, try favorite <truck>",
+	FavoritesList:       "Your favorite trucks: %s",
+	TruckNotFound:       "Sorry I couldn't find the truck %s, try its id from seattlefoodtruck.com",
+	FavoritesSaveFailed: "Sorry I couldn't save your favorites",
+	FavoriteRemoved:     "Removed *%s* from your favorites",
+	FavoriteAdded:       "Added *%s* to your favorites, I'll DM you when it's booked nearby",
+
+	PrefsList:          "Your preferences: %s",
+	PrefsNone:          "You have no preferences, try prefs set %s or prefs compact on",
+	PrefsSaveFailed:    "Sorry I couldn't save your preferences",
+	PrefsCompactOn:     "Saved, when you find events I'll show you a compact list without images",
+	PrefsCompactOff:    "Saved, you'll see the full posts again",
+	PrefsAccessibleOn:  "Saved, when you find events I'll describe every truck in words for screen readers",
+	PrefsClearFailed:   "Sorry I couldn't clear your preferences",
+	PrefsCleared:       "Cleared your preferences, you'll see every truck again",
+	PrefsUnknownDiet:   "Sorry I don't know the preference %s, try %s",
+	PrefsSaved:         "Saved, when you find events I'll only show you trucks that are %s",
+	PrefsUsage:         "Try prefs set %s, prefs compact on/off, prefs accessible on/off, prefs locale <%s> or prefs clear",
+	PrefsUnknownLocale: "Sorry I don't speak %s yet, try %s",
+	PrefsLocaleSaved:   "Saved, I'll answer you in English",
+
+	HelpSMS:          "%s <phone number> at <location id> - to get a text when a favorite truck or cuisine is at your office",
+	SMSDisabled:      "Sorry, SMS alerts aren't set up here",
+	SMSUsage:         "Try sms <phone number> at <location id> to get texts, then sms cuisines <thai, bbq> for the food you like",
+	SMSStatus:        "I text %s about your favorites at location %s, cuisines: %s. Try sms off to stop",
+	SMSOff:           "Done, no more texts",
+	SMSCuisinesSaved: "Saved, I'll also text you about %s trucks",
+	SMSBadNumber:     "Sorry %s doesn't look like a phone number, try +12065550100",
+	SMSWhere:         "Please tell me your office location, e.g. sms +12065550100 at 69",
+	SMSSaveFailed:    "Sorry I couldn't save your SMS alerts",
+	SMSSendFailed:    "Sorry I couldn't text %s, please check the number",
+	SMSSaved:         "Saved, I'll text %s when a favorite truck is at %s",
+	SMSCode:          "Seafoodtruck code %s: tell the bot sms verify %s in Slack to get a text when your favorite trucks are at %s. Reply STOP to opt out.",
+	SMSCodeSent:      "I texted a code to %s, tell me sms verify <code> to start getting texts",
+	SMSCodePending:   "I texted a code a few minutes ago, tell me sms verify <code> or try again in 10 minutes",
+	SMSBadCode:       "Sorry that's not the code I texted, try sms verify <code> again",
+	SMSNoAttempts:    "Sorry that's not the code I texted either, try sms <phone number> again in 10 minutes for a new one",
+	SMSAlert:         "%s (%s) is at %s today %s-%s",
+
+	//the briefing is read aloud: arguments are the truck count, location, start, end and
+	//the names of the trucks
+	BriefingTitle:  "Food trucks today",
+	BriefingTrucks: "%[1]d trucks at %[2]s today from %[3]s to %[4]s: %[5]s.",
+	BriefingTruck:  "%[5]s is at %[2]s today from %[3]s to %[4]s.",
+	BriefingNone:   "No food trucks are booked near the office today.",
+
+	PollHeader:       ":ballot_box_with_ballot: *Where should we get lunch?* Voting closes at %s",
+	PollClosedHeader: ":ballot_box_with_ballot: *Lunch poll* is closed",
+	PollVotes:        "%v vote(s)",
+	PollVote:         "Vote",
+	PollNoWinner:     "No votes, no winner",
+	PollWinner:       ":trophy: *%s* wins with %v vote(s)",
+	PollText:         "Where should we get lunch? Vote on %v truck(s) until %s",
+	PollClosedText:   "Lunch poll is closed, no winner",
+	PollClosedWinner: "Lunch poll is closed, %s wins with %v vote(s)",
+	PollNoTrucks:     "No trucks today, nothing to vote on",
+	PollNoVotes:      "The lunch poll closed without votes",
+	PollGoing:        ":trophy: The team is going to *<%s|%s>* with %v vote(s)",
+
+	ArchiveReadFailed:     "Sorry I couldn't read the archive",
+	LeaderboardUsage:      "Try leaderboard week, month, year or 14 days",
+	LeaderboardDays:       "day(s)",
+	LeaderboardVotes:      "vote(s)",
+	LeaderboardNoBookings: "No bookings archived yet",
+	LeaderboardNoVotes:    "No votes or RSVPs yet, try poll",
+	LeaderboardTitle:      ":trophy: *Truck leaderboard* for the last %v days",
+	LeaderboardMostBooked: "*Most booked*",
+	LeaderboardMostVoted:  "*Most voted*",
+	LeaderboardText:       "Truck leaderboard for the last %v days",
+	LeaderboardLeads:      ", %s leads",
+	StatsNone:             "No trucks archived for %s yet",
+	StatsTruck:            "%v. *<%s|%s>* %v day(s)",
+	StatsPerLocation:      "*%s* %.1f trucks/day",
+	StatsTitle:            ":bar_chart: *Truck stats for %s*",
+	StatsMostFrequent:     "*Most frequent trucks*",
+	StatsAverage:          "*Average per location*",
+	StatsBusiest:          "Busiest weekday: *%s* · %v location day(s) archived",
+	StatsText:             "Truck stats for %s, busiest on %ss",
+
+	JobsAdminOnly: "Sorry, only admins can manage jobs",
+	JobsFailed:    "Sorry, %v",
+	JobsDone:      "Done.\n%s",
+	JobsNone:      "No jobs are scheduled",
+	JobLine:       "`%v` %s <#%s> `%s` next %s, locations %s",
+	JobPaused:     " _(paused)_",
+	PostAdminOnly: "Sorry, only admins can post the schedule on demand",
+
+	WeatherAt:     "%s",
+	WeatherNoon:   "noon",
+	WeatherSnow:   ":snowflake: %d%% snow at %s, %.0f°F — maybe eat inside",
+	WeatherRain:   ":umbrella_with_rain_drops: %d%% rain at %s, %.0f°F — maybe eat inside",
+	WeatherWarm:   ":sunny: %s at %s, %.0f°F — a good day to eat outside",
+	WeatherClear:  ":sunny: %s at %s, %.0f°F",
+	WeatherCloudy: ":cloud: %s at %s, %.0f°F",
+
+	MapLabel:          "Map",
+	MapAlt:            "Map of %s",
+	WalkDistance:      ":walking: %d min walk (%.1f mi)",
+	Distance:          "%.1f mi away",
+	ChangesHeader:     ":mega: Schedule update at *<%s|%s>*",
+	ChangeAdded:       ":heavy_plus_sign: *%s* added, %s",
+	ChangeCancelled:   ":x: *%s* cancelled",
+	ChangeMoved:       ":repeat: *%s* now %s",
+	RepostAbove:       "the message above",
+	RepostEarlier:     "<%s|the earlier post>",
+	RepostUnchanged:   "Trucks at *%s* unchanged since %s, see %s",
+	RepostChanged:     "Trucks at *%s* changed, I've updated %s",
+	Reminder:          ":truck: %v truck(s) arriving at %s in %v min",
+	HolidayNote:       ":tada: Happy %s! No truck schedule today.",
+	OfficeHolidayNote: ":tada: Happy holiday! No truck schedule today.",
+	FavoriteAlert:     ":star: Your favorite *<%s|%s>* is at *<%s|%s>* today from %s–%s",
+	PanicApology:      "Oops, something went wrong on my side. Please try again in a bit.",
+}
@@ -0,0 +1,175 @@
+package i18n
+
+var es = map[Key]string{
+	HelpTitle:         "Puedes pedirme",
+	HelpFindEvents:    "%s for <today/tomorrow> [by <rating/name/category>] [at <ids de ubicaciones o grupo>] - para ver los camiones reservados",
+	HelpSubscribe:     "%s <ids de ubicaciones o grupo> at <hora> - para publicar los camiones aquí cada día laborable",
+	HelpUnsubscribe:   "%s - para dejar de publicar en este canal",
+	HelpFavorite:      "%s <camión> - para recibir un DM cuando un camión que te gusta esté cerca",
+	HelpTruck:         "%s <camión> - para ver sus fotos y detalles",
+	HelpFavorites:     "%s - para ver tus camiones favoritos",
+	HelpPoll:          "%s - para votar dónde comer hoy",
+	HelpStats:         "%s - para ver los camiones más frecuentes y los días con más camiones del mes",
+	HelpLeaderboard:   "%s [week/month/year/<n> days] - para ver los camiones más reservados y votados",
+	HelpPrefsDiets:    "%s set <vegetarian,vegan,gluten_free,paleo> - para ver solo los camiones que se ajustan a tu dieta",
+	HelpPrefsCompact:  "%s compact on/off - para ver una lista compacta sin imágenes",
+	HelpPrefsAccess:   "%s accessible on/off - para recibir publicaciones fáciles de leer con un lector de pantalla",
+	HelpPrefsLocale:   "%s locale <%s> - para recibir respuestas en tu idioma",
+	UnknownCommand:    "Lo siento, no puedo ayudarte con eso, prueba help para ver lo que puedes pedirme",
+	KnownOrders:       "Puedo ordenar los camiones por %s",
+	ErrNoLocations:    "no hay ubicaciones configuradas",
+	ErrLocation:       "Lo siento, no puedo obtener los detalles de la ubicación",
+	ErrEvents:         "Lo siento, no puedo obtener los eventos",
+	ErrUpdateEvents:   "Lo siento, no pude actualizar los eventos",
+	ErrPostEvents:     "Lo siento, no pude publicar los eventos",
+	ErrPostSomeEvents: "Lo siento, no pude publicar todos los eventos",
+	Today:             "hoy",
+	Tomorrow:          "mañana",
+	NoTrucks:          "No hay camiones reservados en *%s* %s (%s)",
+	NoTrucksAhead:     " — no hay camiones reservados en los próximos %v días",
+	NextTrucks:        " — próximos camiones: %s",
+
+	TrucksOn:      "*%v camión(es)* el %s de %s a %s ",
+	Matching:      "según tus preferencias (%v reservados)",
+	ImGoing:       "Voy :walking:",
+	Going:         "Van: %s",
+	Directions:    "Cómo llegar :world_map:",
+	NewHere:       ":new: *¡Nuevo aquí!*",
+	TruckDetails:  "Fotos y detalles",
+	FullMenu:      "Menú completo (%d)",
+	Reviews:       "%v reseñas",
+	EventDate:     "%[1]s %[3]d/%[4]d",
+	TruckCount:    "%v camión(es)",
+	SummaryOn:     "el %s",
+	SummaryTrucks: "%d camiones%s en %s",
+	SummaryTruck:  "%d camión%s en %s",
+	SummaryNone:   "No hay camiones%s en %s",
+	Address:       "Dirección: %s",
+	NewHereText:   "Nuevo aquí",
+	RatedOutOf:    "Valorado con %.1f de 5 en %v reseñas",
+	FoodLine:      "Comida: %s",
+	DietsLine:     "Dietas: %s",
+	CreditCards:   "Acepta tarjetas de crédito",
+	TruckPhotoAlt: "Foto del camión de comida %s",
+
+	SubscribeAdmin:    "Lo siento, solo los administradores pueden suscribir o desuscribir canales",
+	SubscribeWhich:    "Dime qué ubicaciones, p. ej. subscribe 69,123 at 8:30am",
+	SubscribeBadTime:  "Lo siento, no entiendo la hora %s, prueba algo como 8:30am",
+	SubscribeFailed:   "Lo siento, no pude guardar la suscripción",
+	Subscribed:        "<#%s> suscrito a los camiones de las ubicaciones %s cada día laborable a las %s (%s)",
+	NoSubscription:    "Este canal no tiene suscripción",
+	UnsubscribeFailed: "Lo siento, no pude eliminar la suscripción",
+	Unsubscribed:      "<#%s> dado de baja, no habrá más publicaciones diarias aquí",
+
+	FavoritesLoadFailed: "Lo siento, no pude cargar tus favoritos",
+	FavoritesNone:       "Aún no tienes camiones favoritos, prueba favorite <camión>",
+	FavoritesList:       "Tus camiones favoritos: %s",
+	TruckNotFound:       "Lo siento, no encontré el camión %s, prueba su id de seattlefoodtruck.com",
+	FavoritesSaveFailed: "Lo siento, no pude guardar tus favoritos",
+	FavoriteRemoved:     "*%s* eliminado de tus favoritos",
+	FavoriteAdded:       "*%s* añadido a tus favoritos, te enviaré un DM cuando esté reservado cerca",
+
+	PrefsList:          "Tus preferencias: %s",
+	PrefsNone:          "No tienes preferencias, prueba prefs set %s o prefs compact on",
+	PrefsSaveFailed:    "Lo siento, no pude guardar tus preferencias",
+	PrefsCompactOn:     "Guardado, cuando busques eventos te mostraré una lista compacta sin imágenes",
+	PrefsCompactOff:    "Guardado, volverás a ver las publicaciones completas",
+	PrefsAccessibleOn:  "Guardado, cuando busques eventos describiré cada camión con palabras para lectores de pantalla",
+	PrefsClearFailed:   "Lo siento, no pude borrar tus preferencias",
+	PrefsCleared:       "Preferencias borradas, volverás a ver todos los camiones",
+	PrefsUnknownDiet:   "Lo siento, no conozco la preferencia %s, prueba %s",
+	PrefsSaved:         "Guardado, cuando busques eventos solo te mostraré camiones %s",
+	PrefsUsage:         "Prueba prefs set %s, prefs compact on/off, prefs accessible on/off, prefs locale <%s> o prefs clear",
+	PrefsUnknownLocale: "Lo siento, aún no hablo %s, prueba %s",
+	PrefsLocaleSaved:   "Guardado, te responderé en español",
+
+	HelpSMS:          "%s <teléfono> at <id de ubicación> - para recibir un SMS cuando un camión o cocina favorita esté en tu oficina",
+	SMSDisabled:      "Lo siento, las alertas por SMS no están configuradas aquí",
+	SMSUsage:         "Prueba sms <teléfono> at <id de ubicación> para recibir SMS, y luego sms cuisines <thai, bbq> con la comida que te gusta",
+	SMSStatus:        "Te envío SMS a %s sobre tus favoritos en la ubicación %s, cocinas: %s. Prueba sms off para dejar de recibirlos",
+	SMSOff:           "Listo, no más SMS",
+	SMSCuisinesSaved: "Guardado, también te avisaré de los camiones de %s",
+	SMSBadNumber:     "Lo siento, %s no parece un número de teléfono, prueba +12065550100",
+	SMSWhere:         "Dime la ubicación de tu oficina, por ejemplo sms +12065550100 at 69",
+	SMSSaveFailed:    "Lo siento, no pude guardar tus alertas por SMS",
+	SMSSendFailed:    "Lo siento, no pude enviar un SMS a %s, revisa el número",
+	SMSSaved:         "Guardado, te enviaré un SMS a %s cuando un camión favorito esté en %s",
+	SMSCode:          "Código de Seafoodtruck %s: dile al bot sms verify %s en Slack para recibir un SMS cuando tus camiones favoritos estén en %s. Responde STOP para darte de baja.",
+	SMSCodeSent:      "Te envié un código a %s, dime sms verify <código> para empezar a recibir SMS",
+	SMSCodePending:   "Te envié un código hace unos minutos, dime sms verify <código> o vuelve a intentarlo en 10 minutos",
+	SMSBadCode:       "Lo siento, ese no es el código que te envié, prueba sms verify <código> de nuevo",
+	SMSNoAttempts:    "Lo siento, ese tampoco es el código que te envié, prueba sms <número de teléfono> de nuevo en 10 minutos para recibir otro",
+	SMSAlert:         "%s (%s) está hoy en %s de %s a %s",
+
+	BriefingTitle:  "Camiones de comida hoy",
+	BriefingTrucks: "%[1]d camiones en %[2]s hoy de %[3]s a %[4]s: %[5]s.",
+	BriefingTruck:  "%[5]s está en %[2]s hoy de %[3]s a %[4]s.",
+	BriefingNone:   "Hoy no hay camiones de comida cerca de la oficina.",
+
+	PollHeader:       ":ballot_box_with_ballot: *¿Dónde comemos hoy?* La votación cierra a las %s",
+	PollClosedHeader: ":ballot_box_with_ballot: La *votación del almuerzo* está cerrada",
+	PollVotes:        "%v voto(s)",
+	PollVote:         "Votar",
+	PollNoWinner:     "Sin votos, no hay ganador",
+	PollWinner:       ":trophy: *%s* gana con %v voto(s)",
+	PollText:         "¿Dónde comemos hoy? Vota entre %v camión(es) hasta las %s",
+	PollClosedText:   "La votación del almuerzo está cerrada, no hay ganador",
+	PollClosedWinner: "La votación del almuerzo está cerrada, %s gana con %v voto(s)",
+	PollNoTrucks:     "Hoy no hay camiones, no hay nada que votar",
+	PollNoVotes:      "La votación del almuerzo cerró sin votos",
+	PollGoing:        ":trophy: El equipo va a *<%s|%s>* con %v voto(s)",
+
+	ArchiveReadFailed:     "Lo siento, no pude leer el archivo",
+	LeaderboardUsage:      "Prueba leaderboard week, month, year o 14 days",
+	LeaderboardDays:       "día(s)",
+	LeaderboardVotes:      "voto(s)",
+	LeaderboardNoBookings: "Aún no hay reservas archivadas",
+	LeaderboardNoVotes:    "Aún no hay votos ni asistencias, prueba poll",
+	LeaderboardTitle:      ":trophy: *Clasificación de camiones* de los últimos %v días",
+	LeaderboardMostBooked: "*Más reservados*",
+	LeaderboardMostVoted:  "*Más votados*",
+	LeaderboardText:       "Clasificación de camiones de los últimos %v días",
+	LeaderboardLeads:      ", %s va primero",
+	StatsNone:             "Aún no hay camiones archivados en %s",
+	StatsTruck:            "%v. *<%s|%s>* %v día(s)",
+	StatsPerLocation:      "*%s* %.1f camiones/día",
+	StatsTitle:            ":bar_chart: *Estadísticas de camiones de %s*",
+	StatsMostFrequent:     "*Camiones más frecuentes*",
+	StatsAverage:          "*Promedio por ubicación*",
+	StatsBusiest:          "Día con más camiones: *%s* · %v día(s) de ubicación archivados",
+	StatsText:             "Estadísticas de camiones de %s, el día con más camiones es %s",
+
+	JobsAdminOnly: "Lo siento, solo los administradores pueden gestionar los trabajos",
+	JobsFailed:    "Lo siento, %v",
+	JobsDone:      "Listo.\n%s",
+	JobsNone:      "No hay trabajos programados",
+	JobLine:       "`%v` %s <#%s> `%s` próximo %s, ubicaciones %s",
+	JobPaused:     " _(en pausa)_",
+	PostAdminOnly: "Lo siento, solo los administradores pueden publicar el horario cuando quieran",
+
+	WeatherAt:     "las %s",
+	WeatherNoon:   "el mediodía",
+	WeatherSnow:   ":snowflake: %d%% de nieve hacia %s, %.0f°F — mejor comer adentro",
+	WeatherRain:   ":umbrella_with_rain_drops: %d%% de lluvia hacia %s, %.0f°F — mejor comer adentro",
+	WeatherWarm:   ":sunny: %s hacia %s, %.0f°F — buen día para comer afuera",
+	WeatherClear:  ":sunny: %s hacia %s, %.0f°F",
+	WeatherCloudy: ":cloud: %s hacia %s, %.0f°F",
+
+	MapLabel:          "Mapa",
+	MapAlt:            "Mapa de %s",
+	WalkDistance:      ":walking: %d min a pie (%.1f mi)",
+	Distance:          "a %.1f mi",
+	ChangesHeader:     ":mega: Cambio de horario en *<%s|%s>*",
+	ChangeAdded:       ":heavy_plus_sign: *%s* añadido, %s",
+	ChangeCancelled:   ":x: *%s* cancelado",
+	ChangeMoved:       ":repeat: *%s* ahora %s",
+	RepostAbove:       "el mensaje de arriba",
+	RepostEarlier:     "<%s|la publicación anterior>",
+	RepostUnchanged:   "Los camiones en *%s* no han cambiado desde las %s, mira %s",
+	RepostChanged:     "Los camiones en *%s* cambiaron, he actualizado %s",
+	Reminder:          ":truck: %v camión(es) llegan a %s en %v min",
+	HolidayNote:       ":tada: ¡Feliz %s! Hoy no hay horario de camiones.",
+	OfficeHolidayNote: ":tada: ¡Feliz día festivo! Hoy no hay horario de camiones.",
+	FavoriteAlert:     ":star: Tu favorito *<%s|%s>* está en *<%s|%s>* hoy de %s a %s",
+	PanicApology:      "Uy, algo salió mal de mi lado. Inténtalo de nuevo en un rato.",
+}
@@ -0,0 +1,53 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//DefaultLocale is the locale of messages missing from another catalog
+const DefaultLocale = "en"
+
+//Key identifies a message of the catalogs
+type Key string
+
+//catalogs are the messages of each locale, their arguments formatted like fmt's
+var catalogs = map[string]map[Key]string{
+	"en": en,
+	"es": es,
+}
+
+//IsLocale reports whether locale has a catalog, empty standing for DefaultLocale
+func IsLocale(locale string) bool {
+	if len(locale) == 0 {
+		return true
+	}
+	_, ok := catalogs[strings.ToLower(locale)]
+	return ok
+}
+
+//Locales returns the locales with a catalog comma separated
+func Locales() string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return strings.Join(locales, ",")
+}
+
+//T returns the message key in locale formatted with args, in DefaultLocale when locale
+//is unknown or lacks it
+func T(locale string, key Key, args ...interface{}) string {
+	format, ok := catalogs[strings.ToLower(locale)][key]
+	if !ok {
+		if format, ok = en[key]; !ok {
+			format = string(key)
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,55 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"testing"
+)
+
+//declaredKeys returns the Key constants declared in en.go by name
+func declaredKeys(t *testing.T) map[string]Key {
+	f, err := parser.ParseFile(token.NewFileSet(), "en.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := make(map[string]Key)
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				value, err := strconv.Unquote(vs.Values[i].(*ast.BasicLit).Value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				keys[name.Name] = Key(value)
+			}
+		}
+	}
+	return keys
+}
+
+func TestCatalogs(t *testing.T) {
+	for name, key := range declaredKeys(t) {
+		if _, ok := en[key]; !ok {
+			t.Errorf("en lacks %s (%s)", name, key)
+		}
+	}
+	for locale, catalog := range catalogs {
+		for key := range en {
+			if len(catalog[key]) == 0 {
+				t.Errorf("%s lacks %s", locale, key)
+			}
+		}
+		for key := range catalog {
+			if _, ok := en[key]; !ok {
+				t.Errorf("%s has %s, which en lacks", locale, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//ProxyRequest is an API Gateway proxy event, the fields of both REST APIs (payload
+//1.0) and HTTP APIs (payload 2.0)
+type ProxyRequest struct {
+	Version string `json:"version"`
+	//HTTPMethod, Path and MultiValueQueryStringParameters are set by REST APIs
+	HTTPMethod                      string              `json:"httpMethod"`
+	Path                            string              `json:"path"`
+	MultiValueQueryStringParameters map[string][]string `json:"multiValueQueryStringParameters"`
+	MultiValueHeaders               map[string][]string `json:"multiValueHeaders"`
+	//RawPath, RawQueryString and Cookies are set by HTTP APIs
+	RawPath        string   `json:"rawPath"`
+	RawQueryString string   `json:"rawQueryString"`
+	Cookies        []string `json:"cookies"`
+
+	Headers         map[string]string `json:"headers"`
+	Body            string            `json:"body"`
+	IsBase64Encoded bool              `json:"isBase64Encoded"`
+	RequestContext  struct {
+		HTTP struct {
+			Method   string `json:"method"`
+			SourceIP string `json:"sourceIp"`
+		} `json:"http"`
+		Identity struct {
+			SourceIP string `json:"sourceIp"`
+		} `json:"identity"`
+	} `json:"requestContext"`
+}
+
+//ProxyResponse answers a ProxyRequest, binary bodies base64 encoded
+type ProxyResponse struct {
+	StatusCode int `json:"statusCode"`
+	//MultiValueHeaders answer payload 1.0 requests, Headers and Cookies payload 2.0 ones
+	MultiValueHeaders map[string][]string `json:"multiValueHeaders,omitempty"`
+	Headers           map[string]string   `json:"headers,omitempty"`
+	Cookies           []string            `json:"cookies,omitempty"`
+	Body              string              `json:"body"`
+	IsBase64Encoded   bool                `json:"isBase64Encoded"`
+}
+
+//payloadV2 is the version of the HTTP API payload format whose responses take
+//headers and cookies rather than multiValueHeaders
+const payloadV2 = "2.0"
+
+//ParseProxyRequest returns event as a ProxyRequest and whether it is one
+func ParseProxyRequest(event json.RawMessage) (ProxyRequest, bool) {
+	var p ProxyRequest
+	if err := json.Unmarshal(event, &p); err != nil {
+		return p, false
+	}
+	return p, len(p.HTTPMethod) > 0 || len(p.RequestContext.HTTP.Method) > 0
+}
+
+//HTTPRequest returns p as the request a server would have received
+func (p ProxyRequest) HTTPRequest(ctx context.Context) (*http.Request, error) {
+	method, path, ip := p.HTTPMethod, p.Path, p.RequestContext.Identity.SourceIP
+	query := url.Values(p.MultiValueQueryStringParameters).Encode()
+	if len(p.RequestContext.HTTP.Method) > 0 {
+		method, path, query, ip = p.RequestContext.HTTP.Method, p.RawPath, p.RawQueryString, p.RequestContext.HTTP.SourceIP
+	}
+	body := []byte(p.Body)
+	if p.IsBase64Encoded {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(p.Body); err != nil {
+			return nil, err
+		}
+	}
+	u := &url.URL{Path: path, RawQuery: query}
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range p.MultiValueHeaders {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+	for name, v := range p.Headers {
+		if len(req.Header.Get(name)) == 0 {
+			req.Header.Set(name, v)
+		}
+	}
+	if len(p.Cookies) > 0 {
+		req.Header.Set("Cookie", strings.Join(p.Cookies, "; "))
+	}
+	req.Host = req.Header.Get("Host")
+	req.RemoteAddr = net.JoinHostPort(ip, "0")
+	return req.WithContext(ctx), nil
+}
+
+//responseWriter buffers what a handler writes
+type responseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+//Serve answers r, made from p, with h, returning the response in the payload format
+//of p for API Gateway
+func Serve(h http.Handler, r *http.Request, p ProxyRequest) ProxyResponse {
+	w := &responseWriter{header: make(http.Header)}
+	h.ServeHTTP(w, r)
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	resp := ProxyResponse{StatusCode: w.status, MultiValueHeaders: w.header}
+	if p.Version == payloadV2 {
+		resp.MultiValueHeaders, resp.Headers = nil, make(map[string]string, len(w.header))
+		for name, values := range w.header {
+			if name == "Set-Cookie" {
+				resp.Cookies = values
+				continue
+			}
+			resp.Headers[name] = strings.Join(values, ", ")
+		}
+	}
+	if isText(w.header.Get("Content-Type")) {
+		resp.Body = w.body.String()
+	} else {
+		resp.Body, resp.IsBase64Encoded = base64.StdEncoding.EncodeToString(w.body.Bytes()), true
+	}
+	return resp
+}
+
+//isText reports whether bodies of contentType pass through API Gateway as is
+func isText(contentType string) bool {
+	if len(contentType) == 0 {
+		return true
+	}
+	for _, t := range []string{"text/", "json", "xml", "javascript", "x-www-form-urlencoded", "csv", "calendar"} {
+		if strings.Contains(contentType, t) {
+			return true
+		}
+	}
+	return false
+}
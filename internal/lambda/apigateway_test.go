@@ -0,0 +1,124 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestHTTPRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		event      string
+		method     string
+		uri        string
+		body       string
+		remoteAddr string
+		header     http.Header
+	}{
+		{
+			name: "rest api",
+			event: `{"httpMethod": "GET", "path": "/events",
+				"multiValueQueryStringParameters": {"id": ["123"], "day": ["today"]},
+				"multiValueHeaders": {"Accept": ["application/json"], "Host": ["bot.example.com"]},
+				"requestContext": {"identity": {"sourceIp": "203.0.113.7"}}}`,
+			method:     http.MethodGet,
+			uri:        "/events?day=today&id=123",
+			remoteAddr: "203.0.113.7:0",
+			header:     http.Header{"Accept": {"application/json"}, "Host": {"bot.example.com"}},
+		},
+		{
+			name: "http api",
+			event: `{"version": "2.0", "rawPath": "/interactions", "rawQueryString": "a=1",
+				"headers": {"content-type": "application/x-www-form-urlencoded"},
+				"cookies": ["a=1", "b=2"], "body": "payload=%7B%7D",
+				"requestContext": {"http": {"method": "POST", "sourceIp": "198.51.100.1"}}}`,
+			method:     http.MethodPost,
+			uri:        "/interactions?a=1",
+			body:       "payload=%7B%7D",
+			remoteAddr: "198.51.100.1:0",
+			header:     http.Header{"Content-Type": {"application/x-www-form-urlencoded"}, "Cookie": {"a=1; b=2"}},
+		},
+		{
+			name: "base64 body from an ipv6 client",
+			event: `{"version": "2.0", "rawPath": "/", "body": "aGVsbG8=", "isBase64Encoded": true,
+				"requestContext": {"http": {"method": "POST", "sourceIp": "2001:db8::1"}}}`,
+			method:     http.MethodPost,
+			uri:        "/",
+			body:       "hello",
+			remoteAddr: "[2001:db8::1]:0",
+			header:     http.Header{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := ParseProxyRequest(json.RawMessage(tt.event))
+			if !ok {
+				t.Fatalf("ParseProxyRequest(%s) isn't a proxy request", tt.event)
+			}
+			r, err := p.HTTPRequest(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.Method != tt.method || r.URL.RequestURI() != tt.uri || r.RemoteAddr != tt.remoteAddr {
+				t.Errorf("got %s %s from %s, want %s %s from %s", r.Method, r.URL.RequestURI(), r.RemoteAddr, tt.method, tt.uri, tt.remoteAddr)
+			}
+			if !reflect.DeepEqual(r.Header, tt.header) {
+				t.Errorf("got header %v, want %v", r.Header, tt.header)
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			if string(body) != tt.body {
+				t.Errorf("got body %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestParseProxyRequestJob(t *testing.T) {
+	if _, ok := ParseProxyRequest(json.RawMessage(`{"job": "posts"}`)); ok {
+		t.Error("an EventBridge input parsed as a proxy request")
+	}
+}
+
+func TestServe(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Add("Set-Cookie", "a=1")
+		w.Header().Add("Set-Cookie", "b=2")
+		w.Header().Add("Vary", "Accept")
+		w.Header().Add("Vary", "Origin")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	})
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+
+	tests := []struct {
+		version string
+		want    ProxyResponse
+	}{
+		{"", ProxyResponse{
+			StatusCode: http.StatusCreated,
+			MultiValueHeaders: map[string][]string{
+				"Content-Type": {"application/json"},
+				"Set-Cookie":   {"a=1", "b=2"},
+				"Vary":         {"Accept", "Origin"},
+			},
+			Body: `{}`,
+		}},
+		{"2.0", ProxyResponse{
+			StatusCode: http.StatusCreated,
+			Headers:    map[string]string{"Content-Type": "application/json", "Vary": "Accept, Origin"},
+			Cookies:    []string{"a=1", "b=2"},
+			Body:       `{}`,
+		}},
+	}
+	for _, tt := range tests {
+		got := Serve(h, r, ProxyRequest{Version: tt.version})
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Serve, payload %q = %+v, want %+v", tt.version, got, tt.want)
+		}
+	}
+}
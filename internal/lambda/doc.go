@@ -0,0 +1,4 @@
+//Package lambda runs the bot on AWS Lambda with a custom runtime: it takes invocations
+//from the Lambda runtime API, https://docs.aws.amazon.com/lambda/latest/dg/runtimes-api.html,
+//and adapts API Gateway proxy events, REST and HTTP API alike, to net/http handlers.
+package lambda
@@ -0,0 +1,93 @@
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	//RuntimeAPIEnv holds the host and port of the runtime API inside Lambda
+	RuntimeAPIEnv = "AWS_LAMBDA_RUNTIME_API"
+	runtimePath   = "/2018-06-01/runtime/invocation/"
+)
+
+//Handler answers an invocation with a result marshalled to JSON
+type Handler func(ctx context.Context, event json.RawMessage) (interface{}, error)
+
+//invocationError is reported to the runtime API when a Handler fails
+type invocationError struct {
+	Message string `json:"errorMessage"`
+	Type    string `json:"errorType"`
+}
+
+//Start answers the invocations of the function with handler until the runtime API
+//can't be reached, which only happens outside Lambda or when it shuts down
+func Start(handler Handler) error {
+	api := os.Getenv(RuntimeAPIEnv)
+	if len(api) == 0 {
+		return errors.New("lambda: " + RuntimeAPIEnv + " isn't set, not running in Lambda")
+	}
+	base := "http://" + api + runtimePath
+	//next blocks until an invocation comes, without timing out
+	client := &http.Client{}
+	for {
+		resp, err := client.Get(base + "next")
+		if err != nil {
+			return err
+		}
+		event, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		id := resp.Header.Get("Lambda-Runtime-Aws-Request-Id")
+		ctx, cancel := context.Background(), func() {}
+		if ms, err := strconv.ParseInt(resp.Header.Get("Lambda-Runtime-Deadline-Ms"), 10, 64); err == nil {
+			ctx, cancel = context.WithDeadline(ctx, time.Unix(0, ms*int64(time.Millisecond)))
+		}
+		result, err := invoke(ctx, handler, event)
+		cancel()
+		if err != nil {
+			err = post(client, base+id+"/error", invocationError{Message: err.Error(), Type: "Error"})
+		} else {
+			err = post(client, base+id+"/response", result)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+//invoke calls handler, turning a panic into the error of the invocation
+func invoke(ctx context.Context, handler Handler, event json.RawMessage) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, event)
+}
+
+func post(client *http.Client, url string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("lambda: runtime API answered %s", resp.Status)
+	}
+	return nil
+}
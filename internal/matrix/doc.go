@@ -0,0 +1,4 @@
+//Package matrix is a small client of the Matrix client-server API,
+//https://spec.matrix.org/latest/client-server-api/, posting as a bot user with an
+//access token: joining rooms and sending Markdown messages with an HTML rendering.
+package matrix
@@ -0,0 +1,102 @@
+package matrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+//HTMLFormat is the format of the formatted body of messages
+const HTMLFormat = "org.matrix.custom.html"
+
+//Message is an m.room.message event. Body is read by clients that don't render
+//FormattedBody, the bot writes it in Markdown.
+type Message struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format,omitempty"`
+	FormattedBody string `json:"formatted_body,omitempty"`
+}
+
+//Notice returns a message bots send, which clients don't notify about as loudly and
+//other bots ignore
+func Notice(markdown, html string) Message {
+	return Message{MsgType: "m.notice", Body: markdown, Format: HTMLFormat, FormattedBody: html}
+}
+
+//apiError is the body of a failed request
+type apiError struct {
+	ErrCode string `json:"errcode"`
+	Error   string `json:"error"`
+}
+
+//Client calls a homeserver as the user of its access token
+type Client struct {
+	homeserver string
+	token      string
+	client     *http.Client
+	txn        int64
+}
+
+//New returns a Client of homeserver, e.g. https://matrix.example.org, acting as the
+//user of token
+func New(homeserver, token string, client *http.Client) *Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Client{homeserver: strings.TrimSuffix(homeserver, "/"), token: token, client: client, txn: time.Now().UnixNano()}
+}
+
+//JoinRoom joins room, a room id or #alias:server, and returns its id. Joining a room
+//the user is in already is fine.
+func (c *Client) JoinRoom(ctx context.Context, room string) (string, error) {
+	var joined struct {
+		RoomID string `json:"room_id"`
+	}
+	err := c.call(ctx, http.MethodPost, "/_matrix/client/v3/join/"+url.PathEscape(room), struct{}{}, &joined)
+	return joined.RoomID, err
+}
+
+//Send sends msg into the room roomID
+func (c *Client) Send(ctx context.Context, roomID string, msg Message) error {
+	//transaction ids make retried requests idempotent, they must be unique per token
+	txn := strconv.FormatInt(atomic.AddInt64(&c.txn, 1), 10)
+	return c.call(ctx, http.MethodPut, "/_matrix/client/v3/rooms/"+url.PathEscape(roomID)+"/send/m.room.message/"+txn, msg, nil)
+}
+
+//call sends params as JSON to path, decoding the answer into result unless nil
+func (c *Client) call(ctx context.Context, method, path string, params, result interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(method, c.homeserver+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		var e apiError
+		if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || len(e.ErrCode) == 0 {
+			return fmt.Errorf("matrix: %s", resp.Status)
+		}
+		return fmt.Errorf("matrix: %s: %s", e.ErrCode, e.Error)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
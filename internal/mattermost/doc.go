@@ -0,0 +1,4 @@
+//Package mattermost holds the plumbing between the bot and Mattermost: posting through
+//incoming webhooks and answering slash commands, both of which take Slack style
+//attachments.
+package mattermost
@@ -0,0 +1,92 @@
+package mattermost
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/slack-go/slack"
+)
+
+//Response types of slash command answers
+const (
+	InChannel = "in_channel"
+	Ephemeral = "ephemeral"
+)
+
+//Message is posted through an incoming webhook, https://developers.mattermost.com/integrate/webhooks/incoming/
+type Message struct {
+	//Channel overrides the channel of the webhook, by name such as town-square
+	Channel     string             `json:"channel,omitempty"`
+	Username    string             `json:"username,omitempty"`
+	IconURL     string             `json:"icon_url,omitempty"`
+	Text        string             `json:"text,omitempty"`
+	Attachments []slack.Attachment `json:"attachments,omitempty"`
+}
+
+//Response answers a slash command, https://developers.mattermost.com/integrate/slash-commands/custom/
+type Response struct {
+	ResponseType string             `json:"response_type"`
+	Text         string             `json:"text,omitempty"`
+	Attachments  []slack.Attachment `json:"attachments,omitempty"`
+}
+
+//SlashCommand is what Mattermost sends when a user runs the bot's slash command
+type SlashCommand struct {
+	Token       string
+	ChannelID   string
+	ChannelName string
+	UserID      string
+	Command     string
+	Text        string
+}
+
+//ParseSlashCommand reads the slash command r carries as a form
+func ParseSlashCommand(r *http.Request) (SlashCommand, error) {
+	if err := r.ParseForm(); err != nil {
+		return SlashCommand{}, err
+	}
+	return SlashCommand{
+		Token:       r.PostForm.Get("token"),
+		ChannelID:   r.PostForm.Get("channel_id"),
+		ChannelName: r.PostForm.Get("channel_name"),
+		UserID:      r.PostForm.Get("user_id"),
+		Command:     r.PostForm.Get("command"),
+		Text:        r.PostForm.Get("text"),
+	}, nil
+}
+
+//Verify checks the command carries token, the one Mattermost generated for the slash
+//command. Every command fails when token is empty.
+func (s SlashCommand) Verify(token string) error {
+	if len(token) == 0 || subtle.ConstantTimeCompare([]byte(s.Token), []byte(token)) != 1 {
+		return errors.New("invalid slash command token")
+	}
+	return nil
+}
+
+//Post posts msg through the incoming webhook at url
+func Post(ctx context.Context, client *http.Client, url string, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("mattermost webhook answered %s", resp.Status)
+	}
+	return nil
+}
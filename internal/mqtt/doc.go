@@ -0,0 +1,4 @@
+//Package mqtt publishes messages to an MQTT 3.1.1 broker,
+//http://docs.oasis-open.org/mqtt/mqtt/v3.1.1/mqtt-v3.1.1.html. The bot only publishes
+//now and then, so each Publish connects, sends its messages and disconnects.
+package mqtt
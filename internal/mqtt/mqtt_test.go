@@ -0,0 +1,197 @@
+package mqtt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPacket(t *testing.T) {
+	tests := []struct {
+		size   int
+		header []byte
+	}{
+		//the remaining length boundaries of the MQTT 3.1.1 spec, 2.2.3
+		{0, []byte{0xE0, 0x00}},
+		{127, []byte{0xE0, 0x7F}},
+		{128, []byte{0xE0, 0x80, 0x01}},
+		{16383, []byte{0xE0, 0xFF, 0x7F}},
+		{16384, []byte{0xE0, 0x80, 0x80, 0x01}},
+		{2097151, []byte{0xE0, 0xFF, 0xFF, 0x7F}},
+		{2097152, []byte{0xE0, 0x80, 0x80, 0x80, 0x01}},
+	}
+	for _, tt := range tests {
+		body := bytes.Repeat([]byte{'x'}, tt.size)
+		p := packet(disconnect, body)
+		if !bytes.Equal(p[:len(tt.header)], tt.header) || !bytes.Equal(p[len(tt.header):], body) {
+			t.Errorf("packet of %d bytes starts % X, want % X", tt.size, p[:len(tt.header)], tt.header)
+		}
+		header, got, err := readPacket(bufio.NewReader(bytes.NewReader(p)))
+		if err != nil || header != disconnect || !bytes.Equal(got, body) {
+			t.Errorf("readPacket of %d bytes = %X, %d bytes, %v", tt.size, header, len(got), err)
+		}
+	}
+}
+
+func TestPublishPacket(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  Message
+		id   uint16
+		want []byte
+	}{
+		{"qos 0", Message{Topic: "a/b", Payload: []byte("hi")}, 1,
+			[]byte{0x30, 0x07, 0x00, 0x03, 'a', '/', 'b', 'h', 'i'}},
+		{"qos 0 retained", Message{Topic: "a/b", Payload: []byte("hi"), Retain: true}, 1,
+			[]byte{0x31, 0x07, 0x00, 0x03, 'a', '/', 'b', 'h', 'i'}},
+		{"qos 1", Message{Topic: "a/b", Payload: []byte("hi"), QoS: 1}, 0x0102,
+			[]byte{0x32, 0x09, 0x00, 0x03, 'a', '/', 'b', 0x01, 0x02, 'h', 'i'}},
+		{"qos 1 retained, no payload", Message{Topic: "t", QoS: 1, Retain: true}, 10,
+			[]byte{0x33, 0x05, 0x00, 0x01, 't', 0x00, 0x0A}},
+	}
+	for _, tt := range tests {
+		if got := publishPacket(tt.msg, tt.id); !bytes.Equal(got, tt.want) {
+			t.Errorf("%s: publishPacket = % X, want % X", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReadPacketMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"no remaining length", []byte{0x20}},
+		{"remaining length over 4 bytes", []byte{0x20, 0x80, 0x80, 0x80, 0x80, 0x01}},
+		{"short body", []byte{0x20, 0x02, 0x00}},
+	}
+	for _, tt := range tests {
+		if _, _, err := readPacket(bufio.NewReader(bytes.NewReader(tt.data))); err == nil {
+			t.Errorf("%s: readPacket(% X) read a packet", tt.name, tt.data)
+		}
+	}
+}
+
+//broker accepts one connection, checks it reads the packets of want in order and
+//answers each with the reply at the same index, when there is one
+func broker(t *testing.T, want, replies [][]byte) (string, <-chan error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			done <- err
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		for i, w := range want {
+			got := make([]byte, len(w))
+			if _, err := io.ReadFull(conn, got); err != nil {
+				done <- err
+				return
+			}
+			if !bytes.Equal(got, w) {
+				done <- fmt.Errorf("packet %d is % X, want % X", i, got, w)
+				return
+			}
+			if i < len(replies) && replies[i] != nil {
+				conn.Write(replies[i])
+			}
+		}
+		done <- nil
+	}()
+	return l.Addr().String(), done
+}
+
+//connectPacket is what a client with id bot, user u and password p sends
+var connectPacket = []byte{0x10, 0x15, 0x00, 0x04, 'M', 'Q', 'T', 'T', 0x04, 0xC2, 0x00, 0x3C,
+	0x00, 0x03, 'b', 'o', 't', 0x00, 0x01, 'u', 0x00, 0x01, 'p'}
+
+func TestPublish(t *testing.T) {
+	msgs := []Message{
+		{Topic: "seafoodtruck/schedule/C1", Payload: []byte(`{}`), QoS: 1, Retain: true},
+		{Topic: "seafoodtruck/changes/69", Payload: []byte(`[]`)},
+	}
+	addr, done := broker(t,
+		[][]byte{connectPacket, publishPacket(msgs[0], 1), publishPacket(msgs[1], 2), {0xE0, 0x00}},
+		[][]byte{{0x20, 0x02, 0x00, 0x00}, {0x40, 0x02, 0x00, 0x01}})
+	c, err := New("mqtt://u:p@"+addr, "bot", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Publish(context.Background(), msgs...); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPublishRefused(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply []byte
+		want  string
+	}{
+		{"bad credentials", []byte{0x20, 0x02, 0x00, 0x04}, "bad user name or password"},
+		{"not a connack", []byte{0x40, 0x02, 0x00, 0x01}, "expected a connack"},
+	}
+	for _, tt := range tests {
+		addr, done := broker(t, [][]byte{connectPacket}, [][]byte{tt.reply})
+		c, _ := New("mqtt://u:p@"+addr, "bot", time.Second)
+		err := c.Publish(context.Background(), Message{Topic: "t"})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Publish = %v, want %q", tt.name, err, tt.want)
+		}
+		<-done
+	}
+}
+
+func TestPublishWrongPuback(t *testing.T) {
+	msg := Message{Topic: "t", QoS: 1}
+	addr, done := broker(t, [][]byte{connectPacket, publishPacket(msg, 1)},
+		[][]byte{{0x20, 0x02, 0x00, 0x00}, {0x40, 0x02, 0x00, 0x02}})
+	c, _ := New("mqtt://u:p@"+addr, "bot", time.Second)
+	if err := c.Publish(context.Background(), msg); err == nil {
+		t.Error("Publish took the puback of another packet")
+	}
+	<-done
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		url            string
+		addr           string
+		tls            bool
+		user, password string
+		ok             bool
+	}{
+		{"mqtt://broker", "broker:1883", false, "", "", true},
+		{"mqtts://u:p@broker", "broker:8883", true, "u", "p", true},
+		{"tcp://broker:1884", "broker:1884", false, "", "", true},
+		{"mqtt://[::1]", "[::1]:1883", false, "", "", true},
+		{"http://broker", "", false, "", "", false},
+	}
+	for _, tt := range tests {
+		c, err := New(tt.url, "bot", time.Second)
+		if (err == nil) != tt.ok {
+			t.Errorf("New(%s) error %v, want ok %v", tt.url, err, tt.ok)
+			continue
+		}
+		if tt.ok && (c.addr != tt.addr || c.tls != tt.tls || c.username != tt.user || c.password != tt.password) {
+			t.Errorf("New(%s) = %+v", tt.url, c)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/slack-go/slack"
+)
+
+//StyleAccessible renders events for screen readers: every image has descriptive alt
+//text, nothing is told by emoji alone and blocks read top to bottom without accessories
+const StyleAccessible = "accessible"
+
+//accessibleEvents is Events in StyleAccessible. Each truck is a section spelling out its
+//rating, food and diets, followed by its photo as an image block.
+func accessibleEvents(loc seattlefoodtruck.Location, events []seattlefoodtruck.Event, truck TruckLookup, tz *time.Location, opts Options) slack.Message {
+	ht := fmt.Sprintf("*<%s|%s>*", fmt.Sprintf(LocationScheduleURL, loc.ID), loc.Name)
+	if len(opts.Distance) > 0 {
+		ht += " · " + opts.Distance
+	}
+	if mapsURL := MapsURL(loc); len(mapsURL) > 0 && len(loc.Address) > 0 {
+		ht += "\n" + i18n.T(opts.Locale, i18n.Address, fmt.Sprintf("<%s|%s>", mapsURL, mrkdwnEscaper.Replace(loc.Address)))
+	}
+	if len(opts.Weather) > 0 {
+		ht += "\n" + opts.Weather
+	}
+	msg := slack.NewBlockMessage(slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", ht, false, false), nil, nil))
+	if directions := DirectionsBlock(loc, opts.Locale); directions != nil {
+		msg = slack.AddBlockMessage(msg, directions)
+	}
+	for _, e := range events {
+		st, _ := time.Parse(time.RFC3339, e.StartTime)
+		et, _ := time.Parse(time.RFC3339, e.EndTime)
+		st, et = st.In(tz), et.In(tz)
+
+		var blocks []slack.Block
+		trucks := 0
+		for _, b := range sortBookings(e.Bookings, truck, opts.Order) {
+			t, err := truck(b.Truck.ID)
+			if len(opts.Diets) > 0 && (err != nil || !MatchesDiets(t, opts.Diets)) {
+				continue
+			}
+			lines := []string{fmt.Sprintf("*<%s|%s>*", fmt.Sprintf(TruckURL, b.Truck.ID), b.Truck.Name)}
+			if opts.New[b.Truck.ID] {
+				lines = append(lines, i18n.T(opts.Locale, i18n.NewHereText))
+			}
+			if err == nil {
+				lines = append(lines, fmt.Sprintf("<%s|%s>", mrkdwnEscaper.Replace(ReviewsURL(t)), i18n.T(opts.Locale, i18n.RatedOutOf, t.Rating, t.RatingCount)))
+			}
+			if len(b.Truck.FoodCategories) > 0 {
+				lines = append(lines, i18n.T(opts.Locale, i18n.FoodLine, strings.Join(b.Truck.FoodCategories, ", ")))
+			}
+			if err == nil {
+				if diets := dietNames(t); len(diets) > 0 {
+					lines = append(lines, i18n.T(opts.Locale, i18n.DietsLine, strings.Join(diets, ", ")))
+				}
+				if t.AcceptsCreditCards {
+					lines = append(lines, i18n.T(opts.Locale, i18n.CreditCards))
+				}
+			}
+			blocks = append(blocks, textSections(lines)...)
+			trucks++
+			if len(b.Truck.FeaturedPhoto) > 0 {
+				alt := i18n.T(opts.Locale, i18n.TruckPhotoAlt, b.Truck.Name)
+				if len(b.Truck.FoodCategories) > 0 {
+					alt += " (" + strings.Join(b.Truck.FoodCategories, ", ") + ")"
+				}
+				blocks = append(blocks, slack.NewImageBlock(fmt.Sprintf(PhotoURL, b.Truck.FeaturedPhoto), alt, "photo:"+b.Truck.ID, nil))
+			}
+			if menu := MenuPreviewBlock(t, opts.MenuItems, opts.Locale); err == nil && menu != nil {
+				blocks = append(blocks, menu)
+			}
+			if links := SocialLinks(t); opts.Social && err == nil && len(links) > 0 {
+				blocks = append(blocks, slack.NewContextBlock("social:"+b.Truck.ID, slack.NewTextBlockObject("mrkdwn", links, false, false)))
+			}
+			if opts.RSVP {
+				blocks = append(blocks, RSVPBlocks(b.Truck.ID, opts.Going[b.Truck.ID], opts.Locale)...)
+			}
+		}
+		date := i18n.T(opts.Locale, i18n.EventDate, st.Weekday().String()[0:3], st.Month().String(), st.Day(), int(st.Month()))
+		sh := i18n.T(opts.Locale, i18n.TrucksOn, trucks, date, st.Format(time.Kitchen), et.Format(time.Kitchen))
+		if len(opts.Diets) > 0 {
+			sh += i18n.T(opts.Locale, i18n.Matching, len(e.Bookings))
+		}
+		msg = slack.AddBlockMessage(msg, slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", sh, false, false), nil, nil))
+		for _, block := range blocks {
+			msg = slack.AddBlockMessage(msg, block)
+		}
+	}
+	if opts.More {
+		msg = slack.AddBlockMessage(msg, slack.NewDividerBlock())
+	}
+	msg.Text = Summary(loc, events, opts.Day, tz, opts.Locale)
+	return msg
+}
+
+//dietNames returns the diets t caters for in words, e.g. vegan, gluten free. Vegan
+//trucks aren't listed vegetarian too.
+func dietNames(t seattlefoodtruck.Truck) []string {
+	var names []string
+	for _, name := range []string{"vegan", "vegetarian", "gluten_free", "paleo"} {
+		if name == "vegetarian" && t.Vegan {
+			continue
+		}
+		if diets[name].matches(t) {
+			names = append(names, strings.Replace(name, "_", " ", -1))
+		}
+	}
+	return names
+}
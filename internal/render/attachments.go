@@ -0,0 +1,83 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/slack-go/slack"
+)
+
+//attachmentColor is the bar color of truck attachments
+const attachmentColor = "#36a64f"
+
+//Attachments renders the events at loc as Markdown text heading a legacy attachment per
+//truck, for chats taking Slack's attachments but not its blocks, e.g. Mattermost.
+//Options.RSVP, Going and Style don't apply.
+func Attachments(loc seattlefoodtruck.Location, events []seattlefoodtruck.Event, truck TruckLookup, tz *time.Location, opts Options) (string, []slack.Attachment) {
+	text := fmt.Sprintf("#### [%s](%s)", loc.Name, fmt.Sprintf(LocationScheduleURL, loc.ID))
+	if len(opts.Distance) > 0 {
+		text += " · " + opts.Distance
+	}
+	if mapsURL := MapsURL(loc); len(mapsURL) > 0 {
+		label := i18n.T(opts.Locale, i18n.MapLabel)
+		if len(loc.Address) > 0 {
+			label = loc.Address
+		}
+		text += fmt.Sprintf("\n:round_pushpin: [%s](%s)", label, mapsURL)
+	}
+	if len(opts.Weather) > 0 {
+		text += "\n" + opts.Weather
+	}
+
+	var attachments []slack.Attachment
+	for _, e := range events {
+		st, _ := time.Parse(time.RFC3339, e.StartTime)
+		et, _ := time.Parse(time.RFC3339, e.EndTime)
+		st, et = st.In(tz), et.In(tz)
+
+		var trucks []slack.Attachment
+		for _, b := range sortBookings(e.Bookings, truck, opts.Order) {
+			t, err := truck(b.Truck.ID)
+			if len(opts.Diets) > 0 && (err != nil || !MatchesDiets(t, opts.Diets)) {
+				continue
+			}
+			var lines []string
+			if err == nil {
+				rating := fmt.Sprintf("[%s (%.1f) %s](%s)", Stars(t.Rating), t.Rating, i18n.T(opts.Locale, i18n.Reviews, t.RatingCount), ReviewsURL(t))
+				if badges := TruckBadges(t); len(badges) > 0 {
+					rating += "  " + badges
+				}
+				lines = append(lines, rating)
+			}
+			if opts.New[b.Truck.ID] {
+				lines = append(lines, i18n.T(opts.Locale, i18n.NewHere))
+			}
+			for _, fc := range b.Truck.FoodCategories {
+				lines = append(lines, Emoji(fc)+" "+fc)
+			}
+			a := slack.Attachment{
+				Color:     attachmentColor,
+				Fallback:  b.Truck.Name,
+				Title:     b.Truck.Name,
+				TitleLink: fmt.Sprintf(TruckURL, b.Truck.ID),
+				Text:      strings.Join(lines, "\n"),
+			}
+			if len(b.Truck.FeaturedPhoto) > 0 {
+				a.ThumbURL = fmt.Sprintf(PhotoURL, b.Truck.FeaturedPhoto)
+			}
+			trucks = append(trucks, a)
+		}
+		date := i18n.T(opts.Locale, i18n.EventDate, st.Weekday().String()[0:3], st.Month().String(), st.Day(), int(st.Month()))
+		header := strings.TrimSpace(strings.Replace(i18n.T(opts.Locale, i18n.TrucksOn, len(trucks), date, st.Format(time.Kitchen), et.Format(time.Kitchen)), "*", "**", -1))
+		if len(trucks) == 0 {
+			text += "\n" + header
+			continue
+		}
+		trucks[0].Pretext = header
+		attachments = append(attachments, trucks...)
+	}
+	return text, attachments
+}
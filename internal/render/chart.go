@@ -0,0 +1,68 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+const (
+	//QuickChartURL is the QuickChart chart image service, https://quickchart.io/documentation
+	QuickChartURL = "https://quickchart.io/chart"
+
+	chartWidth  = "500"
+	chartHeight = "200"
+)
+
+//chartDataset is a series of a chart
+type chartDataset struct {
+	Label string `json:"label"`
+	Data  []int  `json:"data"`
+}
+
+//chartConfig is the Chart.js configuration of a bar chart QuickChart renders
+type chartConfig struct {
+	Type string `json:"type"`
+	Data struct {
+		Labels   []string       `json:"labels"`
+		Datasets []chartDataset `json:"datasets"`
+	} `json:"data"`
+	Options struct {
+		Legend struct {
+			Display bool `json:"display"`
+		} `json:"legend"`
+	} `json:"options"`
+}
+
+//TruckCountChartURL returns the address of a bar chart image of counts[i] trucks
+//booked on days[i], rendered by the QuickChart compatible service at base
+func TruckCountChartURL(base string, days []time.Time, counts []int) string {
+	var c chartConfig
+	c.Type = "bar"
+	for _, d := range days {
+		c.Data.Labels = append(c.Data.Labels, d.Format("Mon Jan 2"))
+	}
+	c.Data.Datasets = []chartDataset{{Label: "Trucks", Data: counts}}
+	config, _ := json.Marshal(c)
+	q := url.Values{
+		"c":   {string(config)},
+		"w":   {chartWidth},
+		"h":   {chartHeight},
+		"bkg": {"white"},
+	}
+	return base + "?" + q.Encode()
+}
+
+//TruckCountChart returns the image block of the TruckCountChartURL of days, its alt
+//text listing the counts for screen readers
+func TruckCountChart(base string, days []time.Time, counts []int) slack.Block {
+	alt := make([]string, 0, len(days))
+	for i, d := range days {
+		alt = append(alt, fmt.Sprintf("%s %d", d.Format("Mon"), counts[i]))
+	}
+	return slack.NewImageBlock(TruckCountChartURL(base, days, counts), "Trucks per day: "+strings.Join(alt, ", "), "chart:week", nil)
+}
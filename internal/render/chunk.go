@@ -0,0 +1,163 @@
+package render
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+const (
+	//MaxBlocks is the most blocks Slack accepts in a message
+	MaxBlocks = 50
+	//MaxBlockBytes bounds the JSON of the blocks of a message, below what Slack
+	//truncates or rejects
+	MaxBlockBytes = 40000
+	//MaxSectionText is the most characters Slack takes in the text of a section
+	MaxSectionText = 3000
+)
+
+//attachedBlocks are the prefixes of the block ids of blocks belonging to the block
+//before them, such as a truck's RSVP button, so a message is never split between them
+var attachedBlocks = []string{"weather:", "directions:", "rsvp:", "going:", "menu:", "social:"}
+
+//blockID returns the block id of b, empty when it has none or is of an unknown type
+func blockID(b slack.Block) string {
+	switch b := b.(type) {
+	case *slack.SectionBlock:
+		return b.BlockID
+	case *slack.ActionBlock:
+		return b.BlockID
+	case *slack.ContextBlock:
+		return b.BlockID
+	case *slack.ImageBlock:
+		return b.BlockID
+	}
+	return ""
+}
+
+//attached reports whether b belongs to the block before it
+func attached(b slack.Block) bool {
+	if _, ok := b.(*slack.DividerBlock); ok {
+		return true
+	}
+	id := blockID(b)
+	for _, prefix := range attachedBlocks {
+		if strings.HasPrefix(id, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+//groupBlocks returns how many blocks g takes up, counting the Going line an RSVP button
+//gets once someone clicks it so RSVPs don't move where a message splits
+func groupBlocks(g []slack.Block) int {
+	n := len(g)
+	rsvp, going := false, false
+	for _, b := range g {
+		id := blockID(b)
+		rsvp = rsvp || strings.HasPrefix(id, "rsvp:")
+		going = going || strings.HasPrefix(id, "going:")
+	}
+	if rsvp && !going {
+		n++
+	}
+	return n
+}
+
+func blockBytes(b slack.Block) int {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+//Split splits msg into messages within MaxBlocks and MaxBlockBytes, keeping each block
+//with those attached to it. The split only depends on the blocks so rendering the same
+//events again, RSVPs included, splits them the same way. Every part keeps the text of
+//msg. A message within the limits is returned as is.
+func Split(msg slack.Message) []slack.Message {
+	var groups [][]slack.Block
+	total, size := 0, 0
+	for i, b := range msg.Blocks.BlockSet {
+		size += blockBytes(b)
+		if i > 0 && attached(b) {
+			groups[len(groups)-1] = append(groups[len(groups)-1], b)
+			continue
+		}
+		groups = append(groups, []slack.Block{b})
+	}
+	for _, g := range groups {
+		total += groupBlocks(g)
+	}
+	if total <= MaxBlocks && size <= MaxBlockBytes {
+		return []slack.Message{msg}
+	}
+
+	var parts []slack.Message
+	var part []slack.Block
+	count := 0
+	size = 0
+	flush := func() {
+		if len(part) > 0 {
+			m := slack.NewBlockMessage(part...)
+			m.Text = msg.Text
+			parts = append(parts, m)
+		}
+		part, count, size = nil, 0, 0
+	}
+	for _, g := range groups {
+		//a group over the limits on its own is cut wherever it must
+		if len(g) > MaxBlocks {
+			flush()
+			for len(g) > MaxBlocks {
+				part = g[:MaxBlocks]
+				flush()
+				g = g[MaxBlocks:]
+			}
+		}
+		gsize := 0
+		for _, b := range g {
+			gsize += blockBytes(b)
+		}
+		if count+groupBlocks(g) > MaxBlocks || (len(part) > 0 && size+gsize > MaxBlockBytes) {
+			flush()
+		}
+		part = append(part, g...)
+		count += groupBlocks(g)
+		size += gsize
+	}
+	flush()
+	return parts
+}
+
+//textSections returns mrkdwn sections holding lines in order, a new one started whenever
+//the next line would take a section past MaxSectionText. A line longer than that by
+//itself is cut short.
+func textSections(lines []string) []slack.Block {
+	var blocks []slack.Block
+	var text []rune
+	flush := func() {
+		if len(text) > 0 {
+			blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", string(text), false, false), nil, nil))
+			text = nil
+		}
+	}
+	for _, line := range lines {
+		r := []rune(line)
+		if len(r) > MaxSectionText {
+			r = append(r[:MaxSectionText-1], '…')
+		}
+		if len(text) > 0 && len(text)+1+len(r) > MaxSectionText {
+			flush()
+		}
+		if len(text) > 0 {
+			text = append(text, '\n')
+		}
+		text = append(text, r...)
+	}
+	flush()
+	return blocks
+}
@@ -0,0 +1,104 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func section(id, text string) slack.Block {
+	return slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil, slack.SectionBlockOptionBlockID(id))
+}
+
+func rsvp(id string) slack.Block {
+	return slack.NewActionBlock("rsvp:"+id, slack.NewButtonBlockElement("rsvp", id, slack.NewTextBlockObject(slack.PlainTextType, "I'm going", false, false)))
+}
+
+//trucks returns the blocks of n trucks, each a section followed by an RSVP button
+func trucks(n int) []slack.Block {
+	var blocks []slack.Block
+	for i := 0; i < n; i++ {
+		blocks = append(blocks, section(fmt.Sprintf("truck:%d", i), fmt.Sprintf("Truck %d", i)), rsvp(fmt.Sprint(i)))
+	}
+	return blocks
+}
+
+func TestSplit(t *testing.T) {
+	long := strings.Repeat("x", 2900)
+	var big []slack.Block
+	for i := 0; i < 20; i++ {
+		big = append(big, section(fmt.Sprintf("truck:%d", i), long))
+	}
+
+	tests := []struct {
+		name   string
+		blocks []slack.Block
+		//want is the number of blocks of each part
+		want []int
+	}{
+		{"empty", nil, []int{0}},
+		{"within the limits", trucks(10), []int{20}},
+		//every RSVP button counts the Going line it gets once clicked
+		{"going lines count", trucks(17), []int{32, 2}},
+		{"exactly the limit", trucks(16), []int{32}},
+		{"too many blocks", append([]slack.Block{section("header", "Trucks")}, trucks(30)...), []int{33, 28}},
+		{"too many bytes", big, []int{13, 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := slack.NewBlockMessage(tt.blocks...)
+			msg.Text = "fallback"
+			parts := Split(msg)
+			var got []int
+			for _, p := range parts {
+				got = append(got, len(p.Blocks.BlockSet))
+				if p.Text != "fallback" {
+					t.Errorf("part text = %q, want the text of the message", p.Text)
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("Split gave parts of %v blocks, want %v", got, tt.want)
+			}
+			//no truck is separated from its button
+			for _, p := range parts {
+				if blocks := p.Blocks.BlockSet; len(blocks) > 0 && attached(blocks[0]) {
+					t.Errorf("part starts with %s, attached to the block before it", blockID(blocks[0]))
+				}
+			}
+		})
+	}
+}
+
+func TestSplitOversizedGroup(t *testing.T) {
+	blocks := []slack.Block{section("header", "Trucks")}
+	for i := 0; i < 60; i++ {
+		blocks = append(blocks, slack.NewDividerBlock())
+	}
+	var got []int
+	for _, p := range Split(slack.NewBlockMessage(blocks...)) {
+		got = append(got, len(p.Blocks.BlockSet))
+	}
+	if fmt.Sprint(got) != fmt.Sprint([]int{50, 11}) {
+		t.Errorf("Split gave parts of %v blocks, want [50 11]", got)
+	}
+}
+
+func TestSplitStable(t *testing.T) {
+	blocks := trucks(20)
+	before := Split(slack.NewBlockMessage(blocks...))
+	//someone clicks the first button, adding its Going line
+	going := slack.NewContextBlock("going:0", slack.NewTextBlockObject(slack.MarkdownType, "Going: <@U1>", false, false))
+	clicked := append(append(append([]slack.Block{}, blocks[:2]...), going), blocks[2:]...)
+	after := Split(slack.NewBlockMessage(clicked...))
+	if len(before) != len(after) {
+		t.Fatalf("Split gave %d parts before the RSVP and %d after", len(before), len(after))
+	}
+	for i := range before {
+		b, a := before[i].Blocks.BlockSet, after[i].Blocks.BlockSet
+		if blockID(b[len(b)-1]) != blockID(a[len(a)-1]) {
+			t.Errorf("part %d ends at %s before the RSVP and at %s after", i, blockID(b[len(b)-1]), blockID(a[len(a)-1]))
+		}
+	}
+}
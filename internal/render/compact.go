@@ -0,0 +1,70 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/slack-go/slack"
+)
+
+//StyleCompact renders events as a text list without images or buttons, for poor
+//connections, screen readers and channels finding the full posts noisy
+const StyleCompact = "compact"
+
+//compactEvents is Events in StyleCompact: sections per event listing one truck a line,
+//as many as the list takes. RSVP, menus and social links are left out.
+func compactEvents(loc seattlefoodtruck.Location, events []seattlefoodtruck.Event, truck TruckLookup, tz *time.Location, opts Options) slack.Message {
+	ht := fmt.Sprintf("*<%s|%s>*", fmt.Sprintf(LocationScheduleURL, loc.ID), loc.Name)
+	if len(opts.Distance) > 0 {
+		ht += " · " + opts.Distance
+	}
+	if mapsURL := MapsURL(loc); len(mapsURL) > 0 {
+		ht += fmt.Sprintf(" · <%s|%s>", mapsURL, i18n.T(opts.Locale, i18n.MapLabel))
+	}
+	if len(opts.Weather) > 0 {
+		ht += "\n" + opts.Weather
+	}
+	msg := slack.NewBlockMessage(slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", ht, false, false), nil, nil))
+	for _, e := range events {
+		st, _ := time.Parse(time.RFC3339, e.StartTime)
+		et, _ := time.Parse(time.RFC3339, e.EndTime)
+		st, et = st.In(tz), et.In(tz)
+
+		var lines []string
+		for _, b := range sortBookings(e.Bookings, truck, opts.Order) {
+			line := fmt.Sprintf("• <%s|%s>", fmt.Sprintf(TruckURL, b.Truck.ID), b.Truck.Name)
+			t, err := truck(b.Truck.ID)
+			if len(opts.Diets) > 0 && (err != nil || !MatchesDiets(t, opts.Diets)) {
+				continue
+			}
+			if err == nil {
+				line += fmt.Sprintf(" <%s|%.1f%s (%v)>", mrkdwnEscaper.Replace(ReviewsURL(t)), t.Rating, BlackStar, t.RatingCount)
+			}
+			if len(b.Truck.FoodCategories) > 0 {
+				line += " · " + strings.Join(b.Truck.FoodCategories, ", ")
+			}
+			if err == nil {
+				if badges := TruckBadges(t); len(badges) > 0 {
+					line += " · " + badges
+				}
+			}
+			if opts.New[b.Truck.ID] {
+				line += " · " + i18n.T(opts.Locale, i18n.NewHere)
+			}
+			lines = append(lines, line)
+		}
+		heading := fmt.Sprintf("*%s %s–%s* · %s", st.Format("Mon Jan 2"), st.Format(time.Kitchen), et.Format(time.Kitchen), i18n.T(opts.Locale, i18n.TruckCount, len(lines)))
+		//a long list takes several sections
+		for _, block := range textSections(append([]string{heading}, lines...)) {
+			msg = slack.AddBlockMessage(msg, block)
+		}
+	}
+	if opts.More {
+		msg = slack.AddBlockMessage(msg, slack.NewDividerBlock())
+	}
+	msg.Text = Summary(loc, events, opts.Day, tz, opts.Locale)
+	return msg
+}
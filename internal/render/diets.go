@@ -0,0 +1,69 @@
+package render
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+)
+
+//diet is a dietary preference a truck can be listed under
+type diet struct {
+	badge   string
+	matches func(seattlefoodtruck.Truck) bool
+}
+
+var diets = map[string]diet{
+	"vegetarian":  {":green_salad: veggie", func(t seattlefoodtruck.Truck) bool { return t.Vegetarian || t.Vegan }},
+	"vegan":       {":seedling: vegan", func(t seattlefoodtruck.Truck) bool { return t.Vegan }},
+	"gluten_free": {"GF", func(t seattlefoodtruck.Truck) bool { return t.GlutenFree }},
+	"paleo":       {"paleo", func(t seattlefoodtruck.Truck) bool { return t.Paleo }},
+}
+
+//creditCardBadge marks trucks accepting credit cards
+const creditCardBadge = ":credit_card:"
+
+//IsDiet reports whether name is a known dietary preference
+func IsDiet(name string) bool {
+	_, ok := diets[name]
+	return ok
+}
+
+//KnownDiets returns the known dietary preferences comma separated
+func KnownDiets() string {
+	names := make([]string, 0, len(diets))
+	for name := range diets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+//MatchesDiets reports whether t meets every preference of prefs
+func MatchesDiets(t seattlefoodtruck.Truck, prefs []string) bool {
+	for _, p := range prefs {
+		if d, ok := diets[p]; ok && !d.matches(t) {
+			return false
+		}
+	}
+	return true
+}
+
+//TruckBadges returns the compact badges of the diets t caters for and whether it takes
+//credit cards, e.g. ":seedling: vegan GF :credit_card:". Vegan trucks aren't badged
+//vegetarian too.
+func TruckBadges(t seattlefoodtruck.Truck) string {
+	var badges []string
+	for _, name := range []string{"vegan", "vegetarian", "gluten_free", "paleo"} {
+		if name == "vegetarian" && t.Vegan {
+			continue
+		}
+		if d := diets[name]; d.matches(t) {
+			badges = append(badges, d.badge)
+		}
+	}
+	if t.AcceptsCreditCards {
+		badges = append(badges, creditCardBadge)
+	}
+	return strings.Join(badges, " ")
+}
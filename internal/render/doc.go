@@ -0,0 +1,4 @@
+//Package render builds the Slack messages the bot posts from seattlefoodtruck
+//locations, events and trucks. It holds no state of its own besides the configured
+//emoji so the messages can be built and checked without a Slack connection.
+package render
@@ -0,0 +1,82 @@
+package render
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	//defaultEmoji maps the food categories of seattlefoodtruck to emoji
+	defaultEmoji = map[string]string{
+		"BBQ":             ":cut_of_meat:",
+		"Beverage":        ":cup_with_straw:",
+		"Burgers":         ":hamburger:",
+		"Indian":          ":flag-in:",
+		"Vegetarian":      ":green_salad:",
+		"Vegan":           ":seedling:",
+		"Native American": ":earth_americas:",
+		"Asian":           ":earth_asia:",
+		"Hawaiian":        ":pineapple:",
+		"Seafood":         ":crab:",
+		"Sandwiches":      ":sandwich:",
+		"Italian":         ":spaghetti:",
+		"Pizza":           ":pizza:",
+		"Mexican":         ":taco:",
+		"Tacos":           ":taco:",
+		"Burritos":        ":burrito:",
+		"Wraps":           ":burrito:",
+		"Sushi":           ":sushi:",
+		"Japanese":        ":japan:",
+		"Latin American":  ":earth_americas:",
+		"Breakfast":       ":fried_egg:",
+		"American":        ":flag-us:",
+		"Southern":        ":face_with_cowboy_hat:",
+		"Caribbean":       ":palm_tree:",
+		"Central Asian":   ":earth_asia:",
+		"Coffee":          ":coffee:",
+		"Dessert":         ":ice_cream:",
+		"Ethiopian":       ":flag-et:",
+		"European":        ":earth_africa:",
+		"French":          ":flag-fr:",
+		"Global":          ":globe_with_meridians:",
+		"Halal":           "حلال",
+		"Hot Dogs":        ":hotdog:",
+		"Mediterranean":   ":stuffed_flatbread:",
+		"Middle Eastern":  ":stuffed_flatbread:",
+	}
+
+	emojiMu sync.RWMutex
+	//configuredEmoji add to or replace defaultEmoji, keyed in lower case since config
+	//keys are case insensitive. Guarded by emojiMu.
+	configuredEmoji map[string]string
+)
+
+//SetEmoji replaces the configured emoji of food categories
+func SetEmoji(emoji map[string]string) {
+	lowered := make(map[string]string)
+	for category, e := range emoji {
+		lowered[strings.ToLower(category)] = e
+	}
+	emojiMu.Lock()
+	defer emojiMu.Unlock()
+	configuredEmoji = lowered
+}
+
+//Emoji returns the emoji of a food category, configured ones first
+func Emoji(category string) string {
+	emojiMu.RLock()
+	emoji, ok := configuredEmoji[strings.ToLower(category)]
+	emojiMu.RUnlock()
+	if ok {
+		return emoji
+	}
+	return defaultEmoji[category]
+}
+
+//ConfiguredEmoji returns the configured emoji keyed by lower case category. The map
+//is replaced rather than modified by SetEmoji so it is safe to keep.
+func ConfiguredEmoji() map[string]string {
+	emojiMu.RLock()
+	defer emojiMu.RUnlock()
+	return configuredEmoji
+}
@@ -0,0 +1,79 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/googlechat"
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+)
+
+//GoogleChat renders the events at loc as a Google Chat cards v2 card, a section per
+//event listing its trucks. Options.RSVP, Going and Style don't apply.
+func GoogleChat(loc seattlefoodtruck.Location, events []seattlefoodtruck.Event, truck TruckLookup, tz *time.Location, opts Options) googlechat.CardWithID {
+	header := &googlechat.CardHeader{Title: loc.Name, Subtitle: loc.Address}
+	if len(opts.Distance) > 0 {
+		header.Subtitle = strings.TrimPrefix(header.Subtitle+" · "+opts.Distance, " · ")
+	}
+	if mapURL := StaticMapURL(loc); len(mapURL) > 0 {
+		header.ImageURL, header.ImageAlt = mapURL, i18n.T(opts.Locale, i18n.MapAlt, loc.Name)
+	}
+	card := googlechat.CardWithID{CardID: "location-" + loc.ID, Card: googlechat.Card{Header: header}}
+
+	links := googlechat.ButtonList{Buttons: []googlechat.Button{{Text: "Schedule", OnClick: googlechat.OpenLink(fmt.Sprintf(LocationScheduleURL, loc.ID))}}}
+	if directions := DirectionsURL(loc); len(directions) > 0 {
+		links.Buttons = append(links.Buttons, googlechat.Button{Text: strings.TrimSuffix(i18n.T(opts.Locale, i18n.Directions), " :world_map:"), OnClick: googlechat.OpenLink(directions)})
+	}
+	top := googlechat.Section{Widgets: []googlechat.Widget{{ButtonList: &links}}}
+	if len(opts.Weather) > 0 {
+		top.Widgets = append([]googlechat.Widget{{TextParagraph: &googlechat.TextParagraph{Text: html.EscapeString(opts.Weather)}}}, top.Widgets...)
+	}
+	card.Card.Sections = append(card.Card.Sections, top)
+
+	for _, e := range events {
+		st, _ := time.Parse(time.RFC3339, e.StartTime)
+		et, _ := time.Parse(time.RFC3339, e.EndTime)
+		st, et = st.In(tz), et.In(tz)
+
+		var widgets []googlechat.Widget
+		for _, b := range sortBookings(e.Bookings, truck, opts.Order) {
+			t, err := truck(b.Truck.ID)
+			if len(opts.Diets) > 0 && (err != nil || !MatchesDiets(t, opts.Diets)) {
+				continue
+			}
+			text := "<b>" + html.EscapeString(b.Truck.Name) + "</b>"
+			if opts.New[b.Truck.ID] {
+				text += " " + html.EscapeString(i18n.T(opts.Locale, i18n.NewHereText))
+			}
+			onClick := googlechat.OpenLink(fmt.Sprintf(TruckURL, b.Truck.ID))
+			dt := &googlechat.DecoratedText{
+				TopLabel: strings.Join(b.Truck.FoodCategories, ", "),
+				Text:     text,
+				WrapText: true,
+				OnClick:  &onClick,
+			}
+			if err == nil {
+				dt.BottomLabel = fmt.Sprintf("%s (%.1f) %s", Stars(t.Rating), t.Rating, i18n.T(opts.Locale, i18n.Reviews, t.RatingCount))
+				if badges := textBadges(t); len(badges) > 0 {
+					dt.BottomLabel += " · " + badges
+				}
+				dt.Button = &googlechat.Button{Text: "Reviews", OnClick: googlechat.OpenLink(ReviewsURL(t))}
+			}
+			if len(b.Truck.FeaturedPhoto) > 0 {
+				dt.StartIcon = &googlechat.Icon{IconURL: fmt.Sprintf(PhotoURL, b.Truck.FeaturedPhoto), AltText: b.Truck.Name, ImageType: "CIRCLE"}
+			}
+			widgets = append(widgets, googlechat.Widget{DecoratedText: dt})
+		}
+		date := i18n.T(opts.Locale, i18n.EventDate, st.Weekday().String()[0:3], st.Month().String(), st.Day(), int(st.Month()))
+		sh := strings.TrimSpace(strings.Replace(i18n.T(opts.Locale, i18n.TrucksOn, len(widgets), date, st.Format(time.Kitchen), et.Format(time.Kitchen)), "*", "", -1))
+		if len(widgets) == 0 {
+			widgets = []googlechat.Widget{{TextParagraph: &googlechat.TextParagraph{Text: html.EscapeString(sh)}}}
+			sh = ""
+		}
+		card.Card.Sections = append(card.Card.Sections, googlechat.Section{Header: html.EscapeString(sh), Widgets: widgets})
+	}
+	return card
+}
@@ -0,0 +1,70 @@
+package render
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/slack-go/slack"
+)
+
+const (
+	//MapsSearchURL and MapsDirectionsURL are Google Maps URLs, https://developers.google.com/maps/documentation/urls
+	MapsSearchURL     = "https://www.google.com/maps/search/?api=1&"
+	MapsDirectionsURL = "https://www.google.com/maps/dir/?api=1&"
+	//DirectionsActionID is the action id of the Directions button. The button opens
+	//its URL, Slack still posts the click to the interactivity request URL.
+	DirectionsActionID = "directions"
+)
+
+//mapsPlace returns where loc is for a maps URL, its coordinates or else its address,
+//and false when upstream has neither
+func mapsPlace(loc seattlefoodtruck.Location) (string, bool) {
+	if loc.Latitude != 0 || loc.Longitude != 0 {
+		return fmt.Sprintf("%f,%f", loc.Latitude, loc.Longitude), true
+	}
+	if len(loc.Address) > 0 {
+		return loc.Address, true
+	}
+	return "", false
+}
+
+//MapsURL returns the Google Maps link showing loc, empty when its place is unknown
+func MapsURL(loc seattlefoodtruck.Location) string {
+	place, ok := mapsPlace(loc)
+	if !ok {
+		return ""
+	}
+	q := url.Values{"query": {place}}
+	if len(loc.GooglePlaceID) > 0 {
+		q.Set("query_place_id", loc.GooglePlaceID)
+	}
+	return MapsSearchURL + q.Encode()
+}
+
+//DirectionsURL returns the Google Maps link navigating to loc from where the user is,
+//empty when its place is unknown
+func DirectionsURL(loc seattlefoodtruck.Location) string {
+	place, ok := mapsPlace(loc)
+	if !ok {
+		return ""
+	}
+	q := url.Values{"destination": {place}}
+	if len(loc.GooglePlaceID) > 0 {
+		q.Set("destination_place_id", loc.GooglePlaceID)
+	}
+	return MapsDirectionsURL + q.Encode()
+}
+
+//DirectionsBlock returns the actions block with the Directions button of loc labelled
+//in locale, nil when its place is unknown
+func DirectionsBlock(loc seattlefoodtruck.Location, locale string) slack.Block {
+	directions := DirectionsURL(loc)
+	if len(directions) == 0 {
+		return nil
+	}
+	button := slack.NewButtonBlockElement(DirectionsActionID, loc.ID, slack.NewTextBlockObject("plain_text", i18n.T(locale, i18n.Directions), true, false))
+	button.URL = directions
+	return slack.NewActionBlock("directions:"+loc.ID, button)
+}
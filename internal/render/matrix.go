@@ -0,0 +1,76 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+)
+
+//markdownEscaper escapes what Markdown would otherwise format in names
+var markdownEscaper = strings.NewReplacer("\\", "\\\\", "*", "\\*", "_", "\\_", "[", "\\[", "]", "\\]", "`", "\\`")
+
+//Matrix renders the events at loc as Markdown, which Matrix clients fall back to, and
+//as the HTML they show, a line per truck. Options.RSVP, Going and Style don't apply.
+func Matrix(loc seattlefoodtruck.Location, events []seattlefoodtruck.Event, truck TruckLookup, tz *time.Location, opts Options) (string, string) {
+	var md, h []string
+	lsURL := fmt.Sprintf(LocationScheduleURL, loc.ID)
+	md = append(md, fmt.Sprintf("**[%s](%s)**", markdownEscaper.Replace(loc.Name), lsURL))
+	h = append(h, fmt.Sprintf(`<strong><a href="%s">%s</a></strong>`, html.EscapeString(lsURL), html.EscapeString(loc.Name)))
+	if len(opts.Distance) > 0 {
+		md[0] += " · " + opts.Distance
+		h[0] += " · " + html.EscapeString(opts.Distance)
+	}
+	if mapsURL := MapsURL(loc); len(mapsURL) > 0 {
+		label := i18n.T(opts.Locale, i18n.MapLabel)
+		if len(loc.Address) > 0 {
+			label = loc.Address
+		}
+		md = append(md, fmt.Sprintf("📍 [%s](%s)", markdownEscaper.Replace(label), mapsURL))
+		h = append(h, fmt.Sprintf(`📍 <a href="%s">%s</a>`, html.EscapeString(mapsURL), html.EscapeString(label)))
+	}
+	for _, e := range events {
+		st, _ := time.Parse(time.RFC3339, e.StartTime)
+		et, _ := time.Parse(time.RFC3339, e.EndTime)
+		st, et = st.In(tz), et.In(tz)
+
+		var mdTrucks, hTrucks []string
+		for _, b := range sortBookings(e.Bookings, truck, opts.Order) {
+			t, err := truck(b.Truck.ID)
+			if len(opts.Diets) > 0 && (err != nil || !MatchesDiets(t, opts.Diets)) {
+				continue
+			}
+			tURL := fmt.Sprintf(TruckURL, b.Truck.ID)
+			mdLine := fmt.Sprintf("- [%s](%s)", markdownEscaper.Replace(b.Truck.Name), tURL)
+			hLine := fmt.Sprintf(`<li><a href="%s">%s</a>`, html.EscapeString(tURL), html.EscapeString(b.Truck.Name))
+			if err == nil {
+				rating := fmt.Sprintf("%.1f%s (%v)", t.Rating, BlackStar, t.RatingCount)
+				mdLine += fmt.Sprintf(" [%s](%s)", rating, ReviewsURL(t))
+				hLine += fmt.Sprintf(` <a href="%s">%s</a>`, html.EscapeString(ReviewsURL(t)), rating)
+			}
+			if len(b.Truck.FoodCategories) > 0 {
+				categories := strings.Join(b.Truck.FoodCategories, ", ")
+				mdLine += " · " + markdownEscaper.Replace(categories)
+				hLine += " · " + html.EscapeString(categories)
+			}
+			if opts.New[b.Truck.ID] {
+				mdLine += " 🆕"
+				hLine += " 🆕"
+			}
+			mdTrucks = append(mdTrucks, mdLine)
+			hTrucks = append(hTrucks, hLine+"</li>")
+		}
+		date := i18n.T(opts.Locale, i18n.EventDate, st.Weekday().String()[0:3], st.Month().String(), st.Day(), int(st.Month()))
+		header := strings.TrimSpace(strings.Replace(i18n.T(opts.Locale, i18n.TrucksOn, len(mdTrucks), date, st.Format(time.Kitchen), et.Format(time.Kitchen)), "*", "", -1))
+		md = append(md, "", "**"+header+"**")
+		md = append(md, mdTrucks...)
+		h = append(h, "<strong>"+html.EscapeString(header)+"</strong>")
+		if len(hTrucks) > 0 {
+			h = append(h, "<ul>"+strings.Join(hTrucks, "")+"</ul>")
+		}
+	}
+	return strings.Join(md, "\n"), strings.Join(h, "<br>")
+}
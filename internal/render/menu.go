@@ -0,0 +1,73 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/slack-go/slack"
+)
+
+const (
+	//FullMenuActionID is the action id of the Full menu button under a menu preview
+	FullMenuActionID = "full_menu"
+	//menuItemsPerSection keeps each section of a full menu well under Slack's 3000
+	//characters of text
+	menuItemsPerSection = 15
+)
+
+//menuLine returns an item of a menu, e.g. "Chicken over rice · $11.00"
+func menuLine(item seattlefoodtruck.MenuItem) string {
+	line := "• " + mrkdwnEscaper.Replace(strings.TrimSpace(item.Name))
+	if item.Price > 0 {
+		line += fmt.Sprintf(" · $%.2f", item.Price)
+	}
+	return line
+}
+
+//MenuPreviewBlock returns the first n items of the menu of t with a Full menu button
+//labelled in locale when it has more, nil when n isn't positive or the menu is empty
+func MenuPreviewBlock(t seattlefoodtruck.Truck, n int, locale string) slack.Block {
+	if n <= 0 || len(t.MenuItems) == 0 {
+		return nil
+	}
+	items := t.MenuItems
+	if len(items) > n {
+		items = items[:n]
+	}
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, menuLine(item))
+	}
+	var accessory *slack.Accessory
+	if len(t.MenuItems) > n {
+		label := i18n.T(locale, i18n.FullMenu, len(t.MenuItems))
+		accessory = slack.NewAccessory(slack.NewButtonBlockElement(FullMenuActionID, t.ID, slack.NewTextBlockObject("plain_text", label, false, false)))
+	}
+	section := slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", strings.Join(lines, "\n"), false, false), nil, accessory)
+	section.BlockID = "menu:" + t.ID
+	return section
+}
+
+//Menu builds the message listing the whole menu of t
+func Menu(t seattlefoodtruck.Truck) slack.Message {
+	header := fmt.Sprintf(":memo: *Menu of <%s|%s>*", fmt.Sprintf(TruckURL, t.ID), t.Name)
+	msg := slack.NewBlockMessage(slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", header, false, false), nil, nil))
+	msg.Text = "Menu of " + t.Name
+	if len(t.MenuItems) == 0 {
+		return slack.AddBlockMessage(msg, slack.NewContextBlock("", slack.NewTextBlockObject("mrkdwn", "No menu on seattlefoodtruck.com yet", false, false)))
+	}
+	for start := 0; start < len(t.MenuItems); start += menuItemsPerSection {
+		end := start + menuItemsPerSection
+		if end > len(t.MenuItems) {
+			end = len(t.MenuItems)
+		}
+		lines := make([]string, 0, end-start)
+		for _, item := range t.MenuItems[start:end] {
+			lines = append(lines, menuLine(item))
+		}
+		msg = slack.AddBlockMessage(msg, slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", strings.Join(lines, "\n"), false, false), nil, nil))
+	}
+	return msg
+}
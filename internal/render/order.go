@@ -0,0 +1,90 @@
+package render
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+)
+
+//The orders trucks within an event can be listed in, upstream's when empty
+const (
+	OrderRating   = "rating"
+	OrderName     = "name"
+	OrderCategory = "category"
+)
+
+//orderAliases are the other words accepted for orders
+var orderAliases = map[string]string{
+	"alphabetical": OrderName,
+	"alphabet":     OrderName,
+	"ratings":      OrderRating,
+	"stars":        OrderRating,
+	"categories":   OrderCategory,
+	"cuisine":      OrderCategory,
+}
+
+//ParseOrder returns the order name refers to and false when it isn't one
+func ParseOrder(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "", OrderRating, OrderName, OrderCategory:
+		return name, true
+	}
+	order, ok := orderAliases[name]
+	return order, ok
+}
+
+//KnownOrders returns the orders comma separated
+func KnownOrders() string {
+	return strings.Join([]string{OrderRating, OrderName, OrderCategory}, ",")
+}
+
+//sortBookings returns bookings in order: best rated first, by truck name, or by first
+//food category then name. Trucks without details sort last by rating.
+func sortBookings(bookings []seattlefoodtruck.Booking, truck TruckLookup, order string) []seattlefoodtruck.Booking {
+	if len(order) == 0 || len(bookings) < 2 {
+		return bookings
+	}
+	sorted := make([]seattlefoodtruck.Booking, len(bookings))
+	copy(sorted, bookings)
+
+	name := func(b seattlefoodtruck.Booking) string { return strings.ToLower(b.Truck.Name) }
+	var less func(a, b seattlefoodtruck.Booking) bool
+	switch order {
+	case OrderRating:
+		ratings := make(map[string]float64, len(sorted))
+		for _, b := range sorted {
+			ratings[b.Truck.ID] = -1
+			if t, err := truck(b.Truck.ID); err == nil {
+				ratings[b.Truck.ID] = t.Rating
+			}
+		}
+		less = func(a, b seattlefoodtruck.Booking) bool {
+			if ratings[a.Truck.ID] != ratings[b.Truck.ID] {
+				return ratings[a.Truck.ID] > ratings[b.Truck.ID]
+			}
+			return name(a) < name(b)
+		}
+	case OrderName:
+		less = func(a, b seattlefoodtruck.Booking) bool { return name(a) < name(b) }
+	case OrderCategory:
+		category := func(b seattlefoodtruck.Booking) string {
+			if len(b.Truck.FoodCategories) == 0 {
+				//uncategorized trucks last
+				return "\uffff"
+			}
+			return strings.ToLower(b.Truck.FoodCategories[0])
+		}
+		less = func(a, b seattlefoodtruck.Booking) bool {
+			if category(a) != category(b) {
+				return category(a) < category(b)
+			}
+			return name(a) < name(b)
+		}
+	default:
+		return bookings
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+	return sorted
+}
@@ -0,0 +1,235 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/slack-go/slack"
+)
+
+const (
+	//PhotoURL is the address of an uploaded truck photo
+	PhotoURL = "https://s3-us-west-2.amazonaws.com/seattlefoodtruck-uploads-prod/%s"
+	//LocationScheduleURL is the schedule page of a location
+	LocationScheduleURL = "https://www.seattlefoodtruck.com/schedule/%s"
+	//TruckURL is the page of a truck
+	TruckURL = "https://www.seattlefoodtruck.com/food-trucks/%s"
+	//TruckReviewsURL is the reviews tab of the page of a truck
+	TruckReviewsURL = TruckURL + "#reviews"
+	//BlackStar and WhiteStar draw a rating
+	BlackStar = "★"
+	WhiteStar = "☆"
+	//RSVPActionID is the action id of the I'm going button
+	RSVPActionID = "rsvp_going"
+)
+
+//mrkdwnEscaper escapes the characters Slack reserves for links and mentions in mrkdwn
+var mrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+//TruckLookup returns the details of a truck, typically from the cached client
+type TruckLookup func(id string) (seattlefoodtruck.Truck, error)
+
+//Options tune how events are rendered
+type Options struct {
+	//More adds a trailing divider when further locations follow
+	More bool
+	//Diets only lists trucks meeting all of them
+	Diets []string
+	//RSVP adds an I'm going button under each truck
+	RSVP bool
+	//Going lists the users who said they're going, keyed by truck id
+	Going map[string][]string
+	//Weather is the forecast line shown under the location header, none when empty
+	Weather string
+	//Distance is how far the location is, e.g. 12 min walk, shown after its name
+	Distance string
+	//Order lists the trucks of each event by OrderRating, OrderName or OrderCategory,
+	//in upstream's order when empty
+	Order string
+	//New holds the ids of trucks badged new here
+	New map[string]bool
+	//Social adds the website and social profiles under each truck
+	Social bool
+	//MenuItems previews this many items of each truck's menu, none when zero
+	MenuItems int
+	//Day names the day of the events in the notification text, e.g. today, the date
+	//of the first event is given when empty
+	Day string
+	//Style is StyleCompact for a text list, StyleAccessible for screen readers, empty for
+	//the full blocks
+	Style string
+	//Locale is the language of the message, i18n.DefaultLocale when empty
+	Locale string
+}
+
+//Stars returns rating rounded to whole stars out of five
+func Stars(rating float64) string {
+	var sb strings.Builder
+	r := round(rating)
+	for i := 1; i <= 5; i++ {
+		if i <= r {
+			sb.WriteString(BlackStar)
+		} else {
+			sb.WriteString(WhiteStar)
+		}
+	}
+	return sb.String()
+}
+
+//ReviewsURL returns where the reviews of t are read, its Yelp page or else its reviews on
+//seattlefoodtruck.com
+func ReviewsURL(t seattlefoodtruck.Truck) string {
+	if u := socialURL(t.Yelp, YelpBizURL); len(u) > 0 {
+		return u
+	}
+	return fmt.Sprintf(TruckReviewsURL, t.ID)
+}
+
+//ratingLink returns the stars, rating and review count of t in locale linked to its
+//reviews
+func ratingLink(t seattlefoodtruck.Truck, locale string) string {
+	return fmt.Sprintf("<%s|%s (%.1f) %s>", mrkdwnEscaper.Replace(ReviewsURL(t)), Stars(t.Rating),
+		t.Rating, i18n.T(locale, i18n.Reviews, t.RatingCount))
+}
+
+func round(num float64) int {
+	return int(num + math.Copysign(0.5, num))
+}
+
+//Events builds the block message listing the trucks booked for events at loc, with
+//times shown in tz and its Summary as the notification text
+func Events(loc seattlefoodtruck.Location, events []seattlefoodtruck.Event, truck TruckLookup, tz *time.Location, opts Options) slack.Message {
+	switch opts.Style {
+	case StyleCompact:
+		return compactEvents(loc, events, truck, tz, opts)
+	case StyleAccessible:
+		return accessibleEvents(loc, events, truck, tz, opts)
+	}
+	lsURL := fmt.Sprintf(LocationScheduleURL, loc.ID)
+	ht := fmt.Sprintf("*<%s|%s>*", lsURL, loc.Name)
+	if len(opts.Distance) > 0 {
+		ht += " · " + opts.Distance
+	}
+	if mapsURL := MapsURL(loc); len(mapsURL) > 0 {
+		label := i18n.T(opts.Locale, i18n.MapLabel)
+		if len(loc.Address) > 0 {
+			label = mrkdwnEscaper.Replace(loc.Address)
+		}
+		ht += fmt.Sprintf("\n:round_pushpin: <%s|%s>", mapsURL, label)
+	}
+
+	htb := slack.NewTextBlockObject("mrkdwn", ht, false, false)
+	hsb := slack.NewSectionBlock(htb, nil, staticMapAccessory(loc, opts.Locale))
+	div := slack.NewDividerBlock()
+	msg := slack.NewBlockMessage(hsb)
+	if len(opts.Weather) > 0 {
+		msg = slack.AddBlockMessage(msg, slack.NewContextBlock("weather:"+loc.ID, slack.NewTextBlockObject("mrkdwn", opts.Weather, false, false)))
+	}
+	if directions := DirectionsBlock(loc, opts.Locale); directions != nil {
+		msg = slack.AddBlockMessage(msg, directions)
+	}
+	msg = slack.AddBlockMessage(msg, div)
+	for _, e := range events {
+		st, _ := time.Parse(time.RFC3339, e.StartTime)
+		et, _ := time.Parse(time.RFC3339, e.EndTime)
+		st, et = st.In(tz), et.In(tz)
+		_, m, d := st.Date()
+		wd := st.Weekday()
+
+		var sections []slack.Block
+		trucks := 0
+		//loop through each booking and
+		for _, b := range sortBookings(e.Bookings, truck, opts.Order) {
+			var sb strings.Builder
+
+			tURL := fmt.Sprintf(TruckURL, b.Truck.ID)
+			sb.WriteString(fmt.Sprintf("*<%s|%s>* ", tURL, b.Truck.Name))
+			if opts.New[b.Truck.ID] {
+				sb.WriteString(i18n.T(opts.Locale, i18n.NewHere) + " ")
+			}
+
+			//get truck details
+			t, err := truck(b.Truck.ID)
+			if err == nil {
+				sb.WriteString(ratingLink(t, opts.Locale))
+				if badges := TruckBadges(t); len(badges) > 0 {
+					sb.WriteString("  " + badges)
+				}
+			}
+			if len(opts.Diets) > 0 && (err != nil || !MatchesDiets(t, opts.Diets)) {
+				continue
+			}
+			sb.WriteString("\n")
+			for _, fc := range b.Truck.FoodCategories {
+				sb.WriteString(fmt.Sprintf("%s %s\n", Emoji(fc), fc))
+			}
+			bhtb := slack.NewTextBlockObject("mrkdwn", sb.String(), false, false)
+			//create accessory element
+			imgURL := fmt.Sprintf(PhotoURL, b.Truck.FeaturedPhoto)
+			ibe := slack.NewImageBlockElement(imgURL, b.Truck.Name)
+			ab := slack.NewAccessory(ibe)
+			//create section block
+			sections = append(sections, slack.NewSectionBlock(bhtb, nil, ab))
+			trucks++
+			if menu := MenuPreviewBlock(t, opts.MenuItems, opts.Locale); err == nil && menu != nil {
+				sections = append(sections, menu)
+			}
+			if links := SocialLinks(t); opts.Social && err == nil && len(links) > 0 {
+				sections = append(sections, slack.NewContextBlock("social:"+b.Truck.ID, slack.NewTextBlockObject("mrkdwn", links, false, false)))
+			}
+			if opts.RSVP {
+				sections = append(sections, RSVPBlocks(b.Truck.ID, opts.Going[b.Truck.ID], opts.Locale)...)
+			}
+		}
+
+		date := i18n.T(opts.Locale, i18n.EventDate, wd.String()[0:3], m.String(), d, int(m))
+		sh := i18n.T(opts.Locale, i18n.TrucksOn, trucks, date, st.Format(time.Kitchen), et.Format(time.Kitchen))
+		if len(opts.Diets) > 0 {
+			sh += i18n.T(opts.Locale, i18n.Matching, len(e.Bookings))
+		}
+		shtb := slack.NewTextBlockObject("mrkdwn", sh, false, false)
+		shsb := slack.NewSectionBlock(shtb, nil, nil)
+
+		//add to message
+		msg = slack.AddBlockMessage(msg, shsb)
+		for _, section := range sections {
+			msg = slack.AddBlockMessage(msg, section)
+		}
+	}
+	if opts.More {
+		msg = slack.AddBlockMessage(msg, div)
+	}
+	msg.Text = Summary(loc, events, opts.Day, tz, opts.Locale)
+	return msg
+}
+
+//RSVPTrucks returns the ids of the trucks msg has I'm going buttons for
+func RSVPTrucks(msg slack.Message) []string {
+	var ids []string
+	for _, b := range msg.Blocks.BlockSet {
+		if id := blockID(b); strings.HasPrefix(id, "rsvp:") {
+			ids = append(ids, strings.TrimPrefix(id, "rsvp:"))
+		}
+	}
+	return ids
+}
+
+//RSVPBlocks returns the I'm going button and details menu of a truck followed by who
+//is going, labelled in locale
+func RSVPBlocks(truckID string, going []string, locale string) []slack.Block {
+	button := slack.NewButtonBlockElement(RSVPActionID, truckID, slack.NewTextBlockObject("plain_text", i18n.T(locale, i18n.ImGoing), true, false))
+	blocks := []slack.Block{slack.NewActionBlock("rsvp:"+truckID, button, truckDetailsMenu(truckID, locale))}
+	if len(going) > 0 {
+		mentions := make([]string, 0, len(going))
+		for _, u := range going {
+			mentions = append(mentions, "<@"+u+">")
+		}
+		text := i18n.T(locale, i18n.Going, strings.Join(mentions, ", "))
+		blocks = append(blocks, slack.NewContextBlock("going:"+truckID, slack.NewTextBlockObject("mrkdwn", text, false, false)))
+	}
+	return blocks
+}
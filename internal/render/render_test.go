@@ -0,0 +1,61 @@
+package render
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/slack-go/slack"
+)
+
+func TestRSVPTrucks(t *testing.T) {
+	var blocks []slack.Block
+	blocks = append(blocks, section("header", "Trucks"))
+	blocks = append(blocks, RSVPBlocks("marination", []string{"U1"}, "en")...)
+	blocks = append(blocks, section("truck:skillet", "Skillet"))
+	blocks = append(blocks, RSVPBlocks("skillet", nil, "en")...)
+	if got := RSVPTrucks(slack.NewBlockMessage(blocks...)); !reflect.DeepEqual(got, []string{"marination", "skillet"}) {
+		t.Errorf("RSVPTrucks = %q, want marination and skillet", got)
+	}
+	if got := RSVPTrucks(slack.NewBlockMessage(section("header", "Trucks"))); got != nil {
+		t.Errorf("RSVPTrucks of a message without buttons = %q", got)
+	}
+}
+
+func TestEventsSectionsWithinLimit(t *testing.T) {
+	loc := seattlefoodtruck.Location{ID: "69", Name: "Westlake Park", Address: "401 Pine St", Latitude: 47.61, Longitude: -122.34}
+	e := seattlefoodtruck.Event{StartTime: "2020-06-01T11:00:00-07:00", EndTime: "2020-06-01T14:00:00-07:00"}
+	for i := 0; i < 40; i++ {
+		e.Bookings = append(e.Bookings, seattlefoodtruck.Booking{Truck: seattlefoodtruck.BookingTruck{ID: fmt.Sprintf("truck-with-a-long-name-%d", i), Name: fmt.Sprintf("Truck With A Long Name %d", i),
+			FoodCategories: []string{"Pacific Northwest", "Asian Fusion", "Sandwiches", "Vegetarian Options"}}})
+	}
+	lookup := func(id string) (seattlefoodtruck.Truck, error) {
+		return seattlefoodtruck.Truck{ID: id, Rating: 4.5, RatingCount: 120, Vegetarian: true, AcceptsCreditCards: true}, nil
+	}
+	for _, style := range []string{StyleCompact, StyleAccessible} {
+		t.Run(style, func(t *testing.T) {
+			msg := Events(loc, []seattlefoodtruck.Event{e}, lookup, time.UTC, Options{Style: style, RSVP: true, Locale: i18n.DefaultLocale})
+			var text strings.Builder
+			for _, b := range msg.Blocks.BlockSet {
+				s, ok := b.(*slack.SectionBlock)
+				if !ok || s.Text == nil {
+					continue
+				}
+				if n := utf8.RuneCountInString(s.Text.Text); n > MaxSectionText {
+					t.Errorf("section of %v characters, more than %v", n, MaxSectionText)
+				}
+				text.WriteString(s.Text.Text)
+			}
+			for _, b := range e.Bookings {
+				if !strings.Contains(text.String(), b.Truck.Name+">") {
+					t.Errorf("%s isn't listed", b.Truck.Name)
+				}
+			}
+		})
+	}
+}
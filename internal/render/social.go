@@ -0,0 +1,56 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+)
+
+//YelpBizURL is prefixed to the Yelp handles of trucks
+const YelpBizURL = "https://www.yelp.com/biz/"
+
+//social is a profile upstream can hold for a truck, either as a link or a handle
+type social struct {
+	label string
+	//base is prefixed to handles, empty when only links are expected
+	base  string
+	value func(seattlefoodtruck.Truck) string
+}
+
+var socials = []social{
+	{"Website", "", func(t seattlefoodtruck.Truck) string { return t.Website }},
+	{"Twitter", "https://twitter.com/", func(t seattlefoodtruck.Truck) string { return t.Twitter }},
+	{"Instagram", "https://www.instagram.com/", func(t seattlefoodtruck.Truck) string { return t.Instagram }},
+	{"Facebook", "https://www.facebook.com/", func(t seattlefoodtruck.Truck) string { return t.Facebook }},
+	{"Yelp", YelpBizURL, func(t seattlefoodtruck.Truck) string { return t.Yelp }},
+}
+
+//socialURL turns what upstream holds for a profile into a link, empty when it is blank
+//or a bare handle of a site without a base
+func socialURL(value, base string) string {
+	value = strings.TrimSpace(value)
+	switch {
+	case len(value) == 0:
+		return ""
+	case strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://"):
+		return value
+	case strings.Contains(value, "."):
+		return "https://" + strings.TrimPrefix(value, "//")
+	case len(base) > 0:
+		return base + strings.TrimPrefix(value, "@")
+	}
+	return ""
+}
+
+//SocialLinks returns the mrkdwn links to the website and profiles of t, e.g.
+//"<https://twitter.com/truck|Twitter> · <https://www.yelp.com/biz/truck|Yelp>", empty
+//when it has none
+func SocialLinks(t seattlefoodtruck.Truck) string {
+	var links []string
+	for _, s := range socials {
+		if u := socialURL(s.value(t), s.base); len(u) > 0 {
+			links = append(links, "<"+mrkdwnEscaper.Replace(u)+"|"+s.label+">")
+		}
+	}
+	return strings.Join(links, " · ")
+}
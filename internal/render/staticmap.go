@@ -0,0 +1,100 @@
+package render
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/slack-go/slack"
+)
+
+const (
+	//GoogleStaticMaps and OSMStaticMaps are the static map providers,
+	//https://developers.google.com/maps/documentation/maps-static and
+	//https://staticmap.openstreetmap.de
+	GoogleStaticMaps = "google"
+	OSMStaticMaps    = "osm"
+
+	googleStaticMapURL = "https://maps.googleapis.com/maps/api/staticmap?"
+	osmStaticMapURL    = "https://staticmap.openstreetmap.de/staticmap.php?"
+	staticMapZoom      = "15"
+	staticMapSize      = "150x150"
+)
+
+var (
+	staticMapMu sync.RWMutex
+	//staticMapProvider and staticMapKey are the configured provider, none when empty,
+	//and its api key. Guarded by staticMapMu.
+	staticMapProvider string
+	staticMapKey      string
+)
+
+//IsStaticMapProvider reports whether provider names a static map provider, or is
+//empty for none
+func IsStaticMapProvider(provider string) bool {
+	switch provider {
+	case "", GoogleStaticMaps, OSMStaticMaps:
+		return true
+	}
+	return false
+}
+
+//SetStaticMap sets the provider of the map images on location headers and its api key,
+//an empty provider disables them
+func SetStaticMap(provider, key string) {
+	staticMapMu.Lock()
+	defer staticMapMu.Unlock()
+	staticMapProvider = provider
+	staticMapKey = key
+}
+
+//StaticMapURL returns the address of a small map image with a marker at loc, empty
+//when no provider is configured or the place of loc is unknown
+func StaticMapURL(loc seattlefoodtruck.Location) string {
+	staticMapMu.RLock()
+	provider, key := staticMapProvider, staticMapKey
+	staticMapMu.RUnlock()
+
+	switch provider {
+	case GoogleStaticMaps:
+		place, ok := mapsPlace(loc)
+		if !ok {
+			return ""
+		}
+		q := url.Values{
+			"center":  {place},
+			"zoom":    {staticMapZoom},
+			"size":    {staticMapSize},
+			"scale":   {"2"},
+			"markers": {"color:red|" + place},
+			"key":     {key},
+		}
+		return googleStaticMapURL + q.Encode()
+	case OSMStaticMaps:
+		//the OSM service only takes coordinates
+		if loc.Latitude == 0 && loc.Longitude == 0 {
+			return ""
+		}
+		center := fmt.Sprintf("%f,%f", loc.Latitude, loc.Longitude)
+		q := url.Values{
+			"center":  {center},
+			"zoom":    {staticMapZoom},
+			"size":    {staticMapSize},
+			"markers": {center + ",red-pushpin"},
+		}
+		return osmStaticMapURL + q.Encode()
+	}
+	return ""
+}
+
+//staticMapAccessory returns the map image accessory of the header of loc with alt text
+//in locale, nil when there is no map
+func staticMapAccessory(loc seattlefoodtruck.Location, locale string) *slack.Accessory {
+	mapURL := StaticMapURL(loc)
+	if len(mapURL) == 0 {
+		return nil
+	}
+	return slack.NewAccessory(slack.NewImageBlockElement(mapURL, i18n.T(locale, i18n.MapAlt, loc.Name)))
+}
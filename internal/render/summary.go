@@ -0,0 +1,49 @@
+package render
+
+import (
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+)
+
+//summaryTrucks is how many truck names a summary lists before eliding the rest
+const summaryTrucks = 3
+
+//Summary returns the plain text notifications show for the events at loc, e.g.
+//"7 trucks today at Westlake: Marination, Sam Choy's, Where Ya At Matt, …". day names
+//the day, such as today, and else the date of the first event is given. It's worded in
+//locale.
+func Summary(loc seattlefoodtruck.Location, events []seattlefoodtruck.Event, day string, tz *time.Location, locale string) string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, e := range events {
+		for _, b := range e.Bookings {
+			if !seen[b.Truck.ID] {
+				seen[b.Truck.ID] = true
+				names = append(names, b.Truck.Name)
+			}
+		}
+	}
+	if len(day) == 0 && len(events) > 0 {
+		if st, err := time.Parse(time.RFC3339, events[0].StartTime); err == nil {
+			day = i18n.T(locale, i18n.SummaryOn, st.In(tz).Format("Mon Jan 2"))
+		}
+	}
+	if len(day) > 0 {
+		day = " " + day
+	}
+	if len(names) == 0 {
+		return i18n.T(locale, i18n.SummaryNone, day, loc.Name)
+	}
+	key := i18n.SummaryTrucks
+	if len(names) == 1 {
+		key = i18n.SummaryTruck
+	}
+	head := i18n.T(locale, key, len(names), day, loc.Name)
+	if len(names) > summaryTrucks {
+		names = append(names[:summaryTrucks], "…")
+	}
+	return head + ": " + strings.Join(names, ", ")
+}
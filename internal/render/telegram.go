@@ -0,0 +1,93 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/internal/telegram"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+)
+
+//telegramCaption is how many characters Telegram shows under a photo
+const telegramCaption = 1024
+
+//Telegram renders the events at loc as a Telegram MarkdownV2 message, a line per truck.
+//Options.RSVP, Going and Style don't apply.
+func Telegram(loc seattlefoodtruck.Location, events []seattlefoodtruck.Event, truck TruckLookup, tz *time.Location, opts Options) string {
+	lines := []string{"*" + telegram.Link(loc.Name, fmt.Sprintf(LocationScheduleURL, loc.ID)) + "*"}
+	if len(opts.Distance) > 0 {
+		lines[0] += telegram.Escape(" · " + opts.Distance)
+	}
+	if mapsURL := MapsURL(loc); len(mapsURL) > 0 {
+		label := i18n.T(opts.Locale, i18n.MapLabel)
+		if len(loc.Address) > 0 {
+			label = loc.Address
+		}
+		lines = append(lines, "📍 "+telegram.Link(label, mapsURL))
+	}
+	for _, e := range events {
+		st, _ := time.Parse(time.RFC3339, e.StartTime)
+		et, _ := time.Parse(time.RFC3339, e.EndTime)
+		st, et = st.In(tz), et.In(tz)
+
+		var trucks []string
+		for _, b := range sortBookings(e.Bookings, truck, opts.Order) {
+			t, err := truck(b.Truck.ID)
+			if len(opts.Diets) > 0 && (err != nil || !MatchesDiets(t, opts.Diets)) {
+				continue
+			}
+			line := "• " + telegram.Link(b.Truck.Name, fmt.Sprintf(TruckURL, b.Truck.ID))
+			if err == nil {
+				line += " " + telegram.Link(fmt.Sprintf("%.1f%s (%v)", t.Rating, BlackStar, t.RatingCount), ReviewsURL(t))
+			}
+			if len(b.Truck.FoodCategories) > 0 {
+				line += telegram.Escape(" · " + strings.Join(b.Truck.FoodCategories, ", "))
+			}
+			if opts.New[b.Truck.ID] {
+				line += " 🆕"
+			}
+			trucks = append(trucks, line)
+		}
+		date := i18n.T(opts.Locale, i18n.EventDate, st.Weekday().String()[0:3], st.Month().String(), st.Day(), int(st.Month()))
+		header := strings.Replace(i18n.T(opts.Locale, i18n.TrucksOn, len(trucks), date, st.Format(time.Kitchen), et.Format(time.Kitchen)), "*", "", -1)
+		lines = append(lines, "", telegram.Bold(strings.TrimSpace(header)))
+		lines = append(lines, trucks...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+//TelegramTruck returns the featured photo of t, empty when it has none, and its details
+//as a MarkdownV2 caption
+func TelegramTruck(t seattlefoodtruck.Truck) (string, string) {
+	lines := []string{
+		"*" + telegram.Link(t.Name, fmt.Sprintf(TruckURL, t.ID)) + "*",
+		telegram.Link(fmt.Sprintf("%s (%.1f) %v reviews", Stars(t.Rating), t.Rating, t.RatingCount), ReviewsURL(t)),
+	}
+	if len(t.FoodCategories) > 0 {
+		categories := make([]string, 0, len(t.FoodCategories))
+		for _, fc := range t.FoodCategories {
+			categories = append(categories, fc.Name)
+		}
+		lines = append(lines, telegram.Escape(strings.Join(categories, ", ")))
+	}
+	if badges := textBadges(t); len(badges) > 0 {
+		lines = append(lines, telegram.Escape(badges))
+	}
+	caption := strings.Join(lines, "\n")
+	if description := strings.TrimSpace(t.Description); len(description) > 0 {
+		//escaping grows the text, cut the description short before it
+		if room := (telegramCaption - len([]rune(caption)) - 2) / 2; room > 0 {
+			if r := []rune(description); len(r) > room {
+				description = string(r[:room]) + "…"
+			}
+			caption += "\n\n" + telegram.Escape(description)
+		}
+	}
+	var photo string
+	if len(t.FeaturedPhoto) > 0 {
+		photo = fmt.Sprintf(PhotoURL, t.FeaturedPhoto)
+	}
+	return photo, caption
+}
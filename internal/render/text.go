@@ -0,0 +1,70 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+)
+
+//Text writes the trucks booked for events at loc as plain text for a terminal, with
+//times shown in tz. Only Options.Diets and Options.Order apply, there is nothing to
+//RSVP to.
+func Text(w io.Writer, loc seattlefoodtruck.Location, events []seattlefoodtruck.Event, truck TruckLookup, tz *time.Location, opts Options) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\n%s\n", loc.Name, fmt.Sprintf(LocationScheduleURL, loc.ID))
+	if mapsURL := MapsURL(loc); len(mapsURL) > 0 {
+		fmt.Fprintf(tw, "%s\n", mapsURL)
+	}
+	if len(events) == 0 {
+		fmt.Fprintln(tw, "  No trucks booked")
+	}
+	for _, e := range events {
+		st, _ := time.Parse(time.RFC3339, e.StartTime)
+		et, _ := time.Parse(time.RFC3339, e.EndTime)
+		st, et = st.In(tz), et.In(tz)
+
+		var rows []string
+		for _, b := range sortBookings(e.Bookings, truck, opts.Order) {
+			rating := "-\t\t"
+			t, err := truck(b.Truck.ID)
+			if err == nil {
+				rating = fmt.Sprintf("%s %.1f\t%v reviews\t%s", Stars(t.Rating), t.Rating, t.RatingCount, textBadges(t))
+			}
+			if len(opts.Diets) > 0 && (err != nil || !MatchesDiets(t, opts.Diets)) {
+				continue
+			}
+			rows = append(rows, fmt.Sprintf("    %s\t%s\t%s", b.Truck.Name, rating, strings.Join(b.Truck.FoodCategories, ", ")))
+		}
+		fmt.Fprintf(tw, "\n  %s from %s to %s, %v truck(s)\n", st.Format("Mon Jan 2"), st.Format(time.Kitchen), et.Format(time.Kitchen), len(rows))
+		for _, row := range rows {
+			fmt.Fprintln(tw, row)
+		}
+	}
+	fmt.Fprintln(tw)
+	return tw.Flush()
+}
+
+//textBadges spells out the badges of t, the emoji of TruckBadges don't render in a
+//terminal
+func textBadges(t seattlefoodtruck.Truck) string {
+	var badges []string
+	for _, b := range []struct {
+		name string
+		has  bool
+	}{
+		{"vegan", t.Vegan},
+		{"vegetarian", t.Vegetarian && !t.Vegan},
+		{"GF", t.GlutenFree},
+		{"paleo", t.Paleo},
+		{"cards", t.AcceptsCreditCards},
+	} {
+		if b.has {
+			badges = append(badges, b.name)
+		}
+	}
+	return strings.Join(badges, " ")
+}
@@ -0,0 +1,82 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/slack-go/slack"
+)
+
+const (
+	//TruckDetailsActionID is the action id of the overflow menu opening a truck's details
+	TruckDetailsActionID = "truck_details"
+	//GalleryPhotos is how many photos the details of a truck show at most
+	GalleryPhotos = 6
+)
+
+//TruckDetail builds the card of t: its rating, badges, description, food categories and
+//social links followed by a gallery of its first GalleryPhotos photos
+func TruckDetail(t seattlefoodtruck.Truck) slack.Message {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("*<%s|%s>*\n%s", fmt.Sprintf(TruckURL, t.ID), t.Name, ratingLink(t, i18n.DefaultLocale)))
+	if badges := TruckBadges(t); len(badges) > 0 {
+		sb.WriteString("  " + badges)
+	}
+	if len(t.Description) > 0 {
+		sb.WriteString("\n" + mrkdwnEscaper.Replace(t.Description))
+	}
+	var accessory *slack.Accessory
+	if len(t.FeaturedPhoto) > 0 {
+		accessory = slack.NewAccessory(slack.NewImageBlockElement(fmt.Sprintf(PhotoURL, t.FeaturedPhoto), t.Name))
+	}
+	msg := slack.NewBlockMessage(slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", sb.String(), false, false), nil, accessory))
+
+	if len(t.FoodCategories) > 0 {
+		var categories []string
+		for _, fc := range t.FoodCategories {
+			categories = append(categories, fmt.Sprintf("%s %s", Emoji(fc.Name), fc.Name))
+		}
+		msg = slack.AddBlockMessage(msg, slack.NewContextBlock("categories:"+t.ID, slack.NewTextBlockObject("mrkdwn", strings.Join(categories, "  "), false, false)))
+	}
+	if links := SocialLinks(t); len(links) > 0 {
+		msg = slack.AddBlockMessage(msg, slack.NewContextBlock("social:"+t.ID, slack.NewTextBlockObject("mrkdwn", links, false, false)))
+	}
+	for _, block := range GalleryBlocks(t) {
+		msg = slack.AddBlockMessage(msg, block)
+	}
+	msg.Text = fmt.Sprintf("%s %s (%.1f)", t.Name, Stars(t.Rating), t.Rating)
+	return msg
+}
+
+//GalleryBlocks returns an image block for each of the first GalleryPhotos photos of t
+//in upstream's order, none when it has no photos
+func GalleryBlocks(t seattlefoodtruck.Truck) []slack.Block {
+	photos := make([]seattlefoodtruck.Photo, 0, len(t.Photos))
+	for _, p := range t.Photos {
+		if len(p.File) > 0 {
+			photos = append(photos, p)
+		}
+	}
+	sort.SliceStable(photos, func(i, j int) bool {
+		return photos[i].Position < photos[j].Position
+	})
+	if len(photos) > GalleryPhotos {
+		photos = photos[:GalleryPhotos]
+	}
+	blocks := make([]slack.Block, 0, len(photos))
+	for i, p := range photos {
+		alt := fmt.Sprintf("%s photo %d", t.Name, i+1)
+		blocks = append(blocks, slack.NewImageBlock(fmt.Sprintf(PhotoURL, p.File), alt, fmt.Sprintf("photo:%s:%d", t.ID, p.ID), nil))
+	}
+	return blocks
+}
+
+//truckDetailsMenu returns the overflow menu of a truck in posts, its one option opening
+//the truck's details
+func truckDetailsMenu(truckID, locale string) *slack.OverflowBlockElement {
+	option := slack.NewOptionBlockObject(truckID, slack.NewTextBlockObject("plain_text", i18n.T(locale, i18n.TruckDetails), false, false), nil)
+	return slack.NewOverflowBlockElement(TruckDetailsActionID, option)
+}
@@ -0,0 +1,4 @@
+//Package schedule describes when and where a channel gets events posted, along with
+//the calendar arithmetic the bot needs to decide what to post and when to stay quiet,
+//and runs the posts on a cron.
+package schedule
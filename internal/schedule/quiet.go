@@ -0,0 +1,77 @@
+package schedule
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+)
+
+var weekdays = map[string]time.Weekday{
+	"SUN": time.Sunday,
+	"MON": time.Monday,
+	"TUE": time.Tuesday,
+	"WED": time.Wednesday,
+	"THU": time.Thursday,
+	"FRI": time.Friday,
+	"SAT": time.Saturday,
+}
+
+//QuietWindow is a daily window of minutes since midnight during which nothing is posted,
+//a window ending before it starts wraps past midnight
+type QuietWindow struct {
+	from, to int
+}
+
+//ParseQuietHours parses a window such as 19:00-7am, empty disables quiet hours
+func ParseQuietHours(s string) (*QuietWindow, error) {
+	if len(strings.TrimSpace(s)) == 0 {
+		return nil, nil
+	}
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return nil, errors.New("Quiet hours must look like 19:00-07:00, got " + s)
+	}
+	from, err := commands.ParseTime(parts[0], time.UTC)
+	if err != nil {
+		return nil, err
+	}
+	to, err := commands.ParseTime(parts[1], time.UTC)
+	if err != nil {
+		return nil, err
+	}
+	return &QuietWindow{from.Hour()*60 + from.Minute(), to.Hour()*60 + to.Minute()}, nil
+}
+
+//Until returns how long t stays inside the window, zero when t is outside of it
+func (w *QuietWindow) Until(t time.Time) time.Duration {
+	if w == nil || w.from == w.to {
+		return 0
+	}
+	m := t.Hour()*60 + t.Minute()
+	end := w.to
+	switch {
+	case w.from < w.to && m >= w.from && m < w.to:
+	case w.from > w.to && m >= w.from:
+		end += 24 * 60
+	case w.from > w.to && m < w.to:
+	default:
+		return 0
+	}
+	return time.Duration(end-m)*time.Minute - time.Duration(t.Second())*time.Second
+}
+
+//IsQuietDay reports whether t falls on one of days, given as MON, TUE, ...
+func IsQuietDay(days []string, t time.Time) bool {
+	for _, d := range days {
+		d = strings.ToUpper(strings.TrimSpace(d))
+		if len(d) > 3 {
+			d = d[:3]
+		}
+		if wd, ok := weekdays[d]; ok && wd == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
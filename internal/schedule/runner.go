@@ -0,0 +1,131 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/scheduler"
+	"go.uber.org/zap"
+)
+
+//Job is a post a Runner makes into Channel on the cron spec Spec, empty disabling it
+type Job struct {
+	Kind    string
+	Channel string
+	Spec    string
+	Run     func() error
+}
+
+//Runner runs the jobs of the schedules on a cron, rebuilt from Jobs whenever the
+//schedules change. Its methods are safe to call concurrently.
+type Runner struct {
+	Location *time.Location
+	Clock    scheduler.Clock
+	Logger   *zap.SugaredLogger
+
+	//Jobs returns the jobs of the current schedules, no jobs leave the cron stopped
+	Jobs func() []Job
+
+	//Wrap turns the run of a job into what the cron calls, such as retrying it
+	Wrap func(Job) func()
+
+	//Added is told the id cron gave each job it added
+	Added func(cron scheduler.Scheduler, id scheduler.EntryID, job Job)
+
+	mu      sync.Mutex
+	cron    scheduler.Scheduler
+	stopped bool
+}
+
+//Start starts the cron with the jobs of the current schedules, unless the runner stopped
+func (r *Runner) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.stopped {
+		r.start()
+	}
+}
+
+//Restart calls update, when not nil, and replaces the running cron with one built from
+//the jobs after it, so no job runs against a half updated set of schedules. It does
+//nothing once the runner stopped.
+func (r *Runner) Restart(update func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopped {
+		return
+	}
+	if update != nil {
+		update()
+	}
+	if r.cron != nil {
+		r.cron.Stop()
+		r.cron = nil
+	}
+	r.start()
+}
+
+//Stop stops the cron for good, the returned context is done once the running jobs finished
+func (r *Runner) Stop() context.Context {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stopped = true
+	if r.cron == nil {
+		idle, cancel := context.WithCancel(context.Background())
+		cancel()
+		return idle
+	}
+	return r.cron.Stop()
+}
+
+//Cron returns the running cron, nil when none runs
+func (r *Runner) Cron() scheduler.Scheduler {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.cron
+}
+
+func (r *Runner) start() {
+	jobs := r.Jobs()
+	if len(jobs) == 0 {
+		return
+	}
+	r.cron = scheduler.New(r.Location, r.Clock)
+	for _, job := range jobs {
+		if len(job.Spec) == 0 {
+			continue
+		}
+		id, err := r.cron.Add(job.Kind+" "+job.Channel, job.Spec, r.Wrap(job))
+		if err != nil {
+			r.Logger.Errorw(fmt.Sprintf("Error scheduling %s for channel %s", job.Kind, job.Channel), zap.Error(err))
+			continue
+		}
+		if r.Added != nil {
+			r.Added(r.cron, id, job)
+		}
+		r.Logger.Infof("Scheduled %s for channel %s at %s", job.Kind, job.Channel, job.Spec)
+	}
+	r.Logger.Infof("Starting cron job in %s", r.Location)
+	r.cron.Start()
+}
+
+//PostedToday keeps the post keys of a job's runs on the day they ran, forgotten once a
+//run falls on another day
+type PostedToday struct {
+	day    string
+	posted map[string]bool
+}
+
+//Keys returns the keys posted by the runs on the day of now
+func (p *PostedToday) Keys(now time.Time) map[string]bool {
+	if day := now.Format("2006-01-02"); p.day != day || p.posted == nil {
+		p.day, p.posted = day, make(map[string]bool)
+	}
+	return p.posted
+}
@@ -0,0 +1,54 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/scheduler"
+	"go.uber.org/zap"
+)
+
+func TestRunner(t *testing.T) {
+	channels := []string{"C1"}
+	added := make(map[string]scheduler.EntryID)
+	r := &Runner{
+		Location: time.UTC,
+		Clock:    scheduler.SystemClock,
+		Logger:   zap.NewNop().Sugar(),
+		Jobs: func() []Job {
+			var jobs []Job
+			for _, ch := range channels {
+				jobs = append(jobs, Job{Kind: "posts", Channel: ch, Spec: DefaultSpec}, Job{Kind: "evening post", Channel: ch})
+			}
+			return jobs
+		},
+		Wrap: func(j Job) func() { return func() { j.Run() } },
+		Added: func(_ scheduler.Scheduler, id scheduler.EntryID, j Job) {
+			added[j.Kind+" "+j.Channel] = id
+		},
+	}
+	if r.Cron() != nil {
+		t.Fatal("a cron runs before starting")
+	}
+	r.Start()
+	if entries := r.Cron().Entries(); len(entries) != 1 || len(added) != 1 {
+		t.Fatalf("entries = %+v, added %v, want the job with a spec only", entries, added)
+	}
+
+	first := r.Cron()
+	r.Restart(func() { channels = append(channels, "C2") })
+	if r.Cron() == first || len(r.Cron().Entries()) != 2 {
+		t.Errorf("restarting kept %v entries of the same cron, want a new cron with 2", len(r.Cron().Entries()))
+	}
+
+	select {
+	case <-r.Stop().Done():
+	case <-time.After(time.Second):
+		t.Fatal("stopping an idle cron didn't finish")
+	}
+	updated := false
+	r.Restart(func() { updated = true })
+	if updated {
+		t.Error("a stopped runner still restarted")
+	}
+}
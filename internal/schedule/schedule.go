@@ -0,0 +1,120 @@
+package schedule
+
+import (
+	"strings"
+	"time"
+)
+
+//Day values of a Schedule
+const (
+	Today    = "today"
+	Tomorrow = "tomorrow"
+)
+
+//DefaultSpec posts every weekday at 8am
+const DefaultSpec = "0 8 * * MON-FRI"
+
+//Schedule describes a recurring post of events into a channel
+type Schedule struct {
+	Channel   string   `json:"channel"`
+	Locations []string `json:"locations"`
+	Spec      string   `json:"spec"`
+	Day       string   `json:"day"`
+
+	//WeeklySpec schedules a week-ahead preview, empty uses WEEKLY_PREVIEW_SPEC
+	WeeklySpec string `json:"weekly_spec,omitempty"`
+
+	//EveningSpec schedules a post of tomorrow's events, empty uses EVENING_SPEC
+	EveningSpec string `json:"evening_spec,omitempty"`
+
+	//ReminderMinutes posts a threaded reminder this many minutes before each event,
+	//zero uses REMINDER_MINUTES
+	ReminderMinutes int `json:"reminder_minutes,omitempty"`
+
+	//NotifyEmpty posts a note with the next booked day instead of skipping days without events
+	NotifyEmpty *bool `json:"notify_empty,omitempty"`
+
+	//QuietHours such as 19:00-07:00 defers or drops posts, empty uses QUIET_HOURS
+	QuietHours string `json:"quiet_hours,omitempty"`
+
+	//QuietDays such as FRI drops posts on those days, empty uses QUIET_DAYS
+	QuietDays []string `json:"quiet_days,omitempty"`
+
+	//Office such as 47.6205,-122.3493 is where the channel's people walk from, location
+	//headers show how far each location is. Empty uses OFFICE.
+	Office string `json:"office,omitempty"`
+
+	//Order lists trucks by rating, name or category, empty uses TRUCK_ORDER
+	Order string `json:"order,omitempty"`
+
+	//Compact posts a text list without images or buttons
+	Compact bool `json:"compact,omitempty"`
+
+	//Accessible posts for screen readers, see render.StyleAccessible. It wins over
+	//Compact.
+	Accessible bool `json:"accessible,omitempty"`
+
+	//Locale such as es is the language of the posts, empty uses LOCALE
+	Locale string `json:"locale,omitempty"`
+}
+
+//WithDefaults returns sch for channel ch, posting today's events on DefaultSpec
+//unless set otherwise
+func WithDefaults(ch string, sch Schedule) Schedule {
+	sch.Channel = ch
+	if len(sch.Spec) == 0 {
+		sch.Spec = DefaultSpec
+	}
+	if len(sch.Day) == 0 {
+		sch.Day = Today
+	}
+	return sch
+}
+
+//DayOf returns the date day, Today or Tomorrow, falls on when asked at now
+func DayOf(day string, now time.Time) time.Time {
+	if day == Tomorrow {
+		return now.AddDate(0, 0, 1)
+	}
+	return now
+}
+
+//ExpandLocations replaces group names in ids with the locations of the group in
+//groups, keyed in lower case, dropping duplicates while keeping the order
+func ExpandLocations(ids []string, groups map[string][]string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		expanded := []string{id}
+		if group, ok := groups[strings.ToLower(id)]; ok {
+			expanded = group
+		}
+		for _, e := range expanded {
+			if !seen[e] {
+				seen[e] = true
+				result = append(result, e)
+			}
+		}
+	}
+	return result
+}
+
+//WeekAhead returns the weekdays to preview, the current week until Thursday
+//and the following week from Friday on
+func WeekAhead(now time.Time) []time.Time {
+	var days []time.Time
+
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch start.Weekday() {
+	case time.Friday:
+		start = start.AddDate(0, 0, 3)
+	case time.Saturday:
+		start = start.AddDate(0, 0, 2)
+	case time.Sunday:
+		start = start.AddDate(0, 0, 1)
+	}
+	for d := start; d.Weekday() != time.Saturday; d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+	}
+	return days
+}
@@ -0,0 +1,76 @@
+package schedule
+
+import (
+	"sort"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+)
+
+//Slot is a truck booked at a location for a time window
+type Slot struct {
+	TruckID    string
+	Truck      string
+	Start, End time.Time
+}
+
+//SlotKey keys the slot of a truck by its start too, a truck may be booked twice a day
+func SlotKey(truckID string, start time.Time) string {
+	return truckID + "@" + start.UTC().Format(time.RFC3339)
+}
+
+//Snapshot holds the slots of one location on one day, keyed by SlotKey
+type Snapshot struct {
+	Day   string
+	Slots map[string]Slot
+}
+
+//TakeSnapshot returns the slots booked by events on day
+func TakeSnapshot(day string, events []seattlefoodtruck.Event) Snapshot {
+	snap := Snapshot{Day: day, Slots: make(map[string]Slot)}
+	for _, e := range events {
+		st, _ := time.Parse(time.RFC3339, e.StartTime)
+		et, _ := time.Parse(time.RFC3339, e.EndTime)
+		for _, b := range e.Bookings {
+			snap.Slots[SlotKey(b.Truck.ID, st)] = Slot{TruckID: b.Truck.ID, Truck: b.Truck.Name, Start: st, End: et}
+		}
+	}
+	return snap
+}
+
+//Sorted returns the slots of s by start, then truck
+func (s Snapshot) Sorted() []Slot {
+	slots := make([]Slot, 0, len(s.Slots))
+	for _, slot := range s.Slots {
+		slots = append(slots, slot)
+	}
+	SortSlots(slots)
+	return slots
+}
+
+//SortSlots orders slots by start, then truck
+func SortSlots(slots []Slot) {
+	sort.Slice(slots, func(i, j int) bool {
+		if !slots[i].Start.Equal(slots[j].Start) {
+			return slots[i].Start.Before(slots[j].Start)
+		}
+		if slots[i].Truck != slots[j].Truck {
+			return slots[i].Truck < slots[j].Truck
+		}
+		return slots[i].TruckID < slots[j].TruckID
+	})
+}
+
+//SameSlots reports whether a and b book the same trucks on the same day at the same times
+func SameSlots(a, b Snapshot) bool {
+	if a.Day != b.Day || len(a.Slots) != len(b.Slots) {
+		return false
+	}
+	for key, s := range a.Slots {
+		o, ok := b.Slots[key]
+		if !ok || !o.Start.Equal(s.Start) || !o.End.Equal(s.End) {
+			return false
+		}
+	}
+	return true
+}
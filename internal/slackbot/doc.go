@@ -0,0 +1,4 @@
+//Package slackbot holds the bot's side of talking to Slack: reading and verifying the
+//payloads Slack sends, dispatching the commands the bot is mentioned with and posting
+//schedules. The bot's own state, such as channel settings and RSVPs, is injected.
+package slackbot
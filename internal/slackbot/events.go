@@ -0,0 +1,93 @@
+package slackbot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/appsbyram/seafoodtruck-slack/version"
+	"github.com/slack-go/slack/slackevents"
+	"go.uber.org/zap"
+)
+
+//RetryNumHeader counts the times Slack redelivered an event
+const RetryNumHeader = "X-Slack-Retry-Num"
+
+//EventsHandler answers the Events API requests Slack posts, and the bot's version on GET
+type EventsHandler struct {
+	//MaxPayload is the most bytes of a request read, more are answered with 413
+	MaxPayload int64
+	//Verify checks Slack signed the request with body
+	Verify func(r *http.Request, body []byte) error
+	//FirstDelivery reports whether the event with id wasn't handled before
+	FirstDelivery func(eventID string) bool
+	//Logger returns the logger of the request of ctx
+	Logger func(ctx context.Context) *zap.SugaredLogger
+	//EventType records the type of the event the request of ctx delivered
+	EventType func(ctx context.Context, eventType string)
+	//Answer answers a callback event without blocking
+	Answer func(log *zap.SugaredLogger, event slackevents.EventsAPIInnerEvent)
+}
+
+func (h *EventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `{
+			"Version": "%s",
+			"GitCommitID": "%s"
+		}`, version.Version, version.GitCommitID)
+	case http.MethodPost:
+		h.serveEvent(w, r)
+	}
+}
+
+func (h *EventsHandler) serveEvent(w http.ResponseWriter, r *http.Request) {
+	log := h.Logger(r.Context())
+	buf, err := ReadPayload(w, r, h.MaxPayload)
+	if err != nil {
+		log.Errorw("Error reading payload posted in http request", zap.Error(err))
+		PayloadError(w, err)
+		return
+	}
+	defer ReleasePayload(buf)
+	payload := buf.Bytes()
+	if err := h.Verify(r, payload); err != nil {
+		log.Warnw("Rejecting request with invalid signature", zap.Error(err))
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	event, err := slackevents.ParseEvent(json.RawMessage(payload), slackevents.OptionNoVerifyToken())
+	if err != nil {
+		log.Errorw("Error parsing to slack event from payload", zap.Error(err))
+		http.Error(w, "Error parsing event", http.StatusInternalServerError)
+		return
+	}
+	h.EventType(r.Context(), event.Type)
+	switch event.Type {
+	case slackevents.URLVerification:
+		var challenge *slackevents.ChallengeResponse
+		if err := json.Unmarshal(payload, &challenge); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		w.Write([]byte(challenge.Challenge))
+	case slackevents.CallbackEvent:
+		h.EventType(r.Context(), event.InnerEvent.Type)
+		var eventID string
+		if cb, ok := event.Data.(*slackevents.EventsAPICallbackEvent); ok {
+			eventID = cb.EventID
+		}
+		if !h.FirstDelivery(eventID) {
+			log.Infow("Skipping event already handled", "event_id", eventID, "retry", r.Header.Get(RetryNumHeader))
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		h.Answer(log.With("event_id", eventID), event.InnerEvent)
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,68 @@
+package slackbot
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/slack-go/slack"
+)
+
+//payloadBuffers are reused across requests to read Slack payloads
+var payloadBuffers = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+//ReadPayload reads the body of r, at most max bytes of it, into a pooled buffer
+//to be handed back with ReleasePayload once the payload is no longer referenced
+func ReadPayload(w http.ResponseWriter, r *http.Request, max int64) (*bytes.Buffer, error) {
+	defer r.Body.Close()
+
+	buf := payloadBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	if _, err := buf.ReadFrom(http.MaxBytesReader(w, r.Body, max)); err != nil {
+		ReleasePayload(buf)
+		return nil, err
+	}
+	return buf, nil
+}
+
+//ReleasePayload hands a buffer read by ReadPayload back to the pool
+func ReleasePayload(buf *bytes.Buffer) {
+	//don't keep buffers grown by unusually large payloads around
+	if buf.Cap() <= 64*1024 {
+		payloadBuffers.Put(buf)
+	}
+}
+
+//PayloadError answers 413 when the payload exceeded the cap and 400 otherwise
+func PayloadError(w http.ResponseWriter, err error) {
+	var mbe *http.MaxBytesError
+	if errors.As(err, &mbe) {
+		http.Error(w, "Payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	http.Error(w, "Error reading payload from request", http.StatusBadRequest)
+}
+
+//ErrNoSigningSecret is returned by Verify when there is no secret to verify with
+var ErrNoSigningSecret = errors.New("no signing secret to verify the request with")
+
+//Verify checks the signature Slack puts on the requests it sends, failing when secret
+//is empty
+func Verify(header http.Header, secret string, body []byte) error {
+	if len(secret) == 0 {
+		return ErrNoSigningSecret
+	}
+	sv, err := slack.NewSecretsVerifier(header, secret)
+	if err != nil {
+		return err
+	}
+	if _, err := sv.Write(body); err != nil {
+		return err
+	}
+	return sv.Ensure()
+}
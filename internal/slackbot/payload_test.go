@@ -0,0 +1,46 @@
+package slackbot
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReadPayload(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"within the cap", "payload", http.StatusOK},
+		{"over the cap", strings.Repeat("x", 64), http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			buf, err := ReadPayload(w, r, 32)
+			if err != nil {
+				PayloadError(w, err)
+			} else {
+				if buf.String() != tt.body {
+					t.Errorf("payload = %q, want %q", buf.String(), tt.body)
+				}
+				ReleasePayload(buf)
+			}
+			if w.Code != tt.status {
+				t.Errorf("status = %v, want %v", w.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestPayloadError(t *testing.T) {
+	w := httptest.NewRecorder()
+	PayloadError(w, errors.New("unexpected EOF"))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+}
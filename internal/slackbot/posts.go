@@ -0,0 +1,53 @@
+package slackbot
+
+import (
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/schedule"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"go.uber.org/zap"
+)
+
+//PostRecord remembers a schedule post so later requests can reference or update it
+//instead of posting again
+type PostRecord struct {
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+	//Parts are the ts of the messages threaded under TS a post too long for one message
+	//was split into
+	Parts    []string          `json:"parts,omitempty"`
+	PostedAt time.Time         `json:"posted_at"`
+	Snapshot schedule.Snapshot `json:"snapshot"`
+}
+
+//PostKey identifies the post of a location on a day, day formatted as 2006-01-02
+func PostKey(channel, locationID, day string) string {
+	return channel + ":" + locationID + ":" + day
+}
+
+//Posts keeps the latest PostRecord under each post key in the store.Messages bucket
+type Posts struct {
+	Store  store.Store
+	Logger *zap.SugaredLogger
+}
+
+//Last returns the latest post stored under key
+func (p Posts) Last(key string) (PostRecord, bool) {
+	var last PostRecord
+	if err := store.GetJSON(p.Store, store.Messages, key, &last); err != nil {
+		if err != store.ErrNotFound {
+			p.Logger.Warnw("Error loading post "+key, zap.Error(err))
+		}
+		return last, false
+	}
+	return last, true
+}
+
+//Record persists the channel and ts of a post, and of the parts threaded under it,
+//under key
+func (p Posts) Record(key, channel, ts string, parts []string, snap schedule.Snapshot) {
+	last := PostRecord{Channel: channel, TS: ts, Parts: parts, PostedAt: time.Now(), Snapshot: snap}
+	if err := store.PutJSON(p.Store, store.Messages, key, last); err != nil {
+		p.Logger.Warnw("Error saving post "+key, zap.Error(err))
+	}
+}
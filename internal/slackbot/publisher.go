@@ -0,0 +1,319 @@
+package slackbot
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/i18n"
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/appsbyram/seafoodtruck-slack/internal/schedule"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+//LookaheadDays is how far ahead a post without trucks looks for the next ones
+const LookaheadDays = 14
+
+//Client is the part of the Slack API schedules are posted with, *slack.Client implements it
+type Client interface {
+	PostMessage(channelID string, options ...slack.MsgOption) (string, string, error)
+	UpdateMessage(channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error)
+	DeleteMessage(channel, messageTimestamp string) (string, string, error)
+	GetPermalink(params *slack.PermalinkParameters) (string, error)
+}
+
+//PostFunc posts a message into a channel, returning the channel and ts of the post.
+//An empty ts means the message was deferred or dropped.
+type PostFunc func(channel string, options ...slack.MsgOption) (string, string, error)
+
+//Channels tells how each channel wants its schedules posted
+type Channels interface {
+	Locale(channel string) string
+	//Order is the order trucks are listed in, see render.ParseOrder
+	Order(channel string) string
+	//Style is how events are rendered, see render.StyleCompact
+	Style(channel string) string
+	//NotifyEmpty reports whether scheduled posts tell about locations without trucks
+	NotifyEmpty(channel string) bool
+}
+
+//Post is a schedule posted into a channel, each of Parts in the message with the ts at
+//the same index of TS
+type Post struct {
+	Channel  string
+	TS       []string
+	Parts    []slack.Message
+	Location seattlefoodtruck.Location
+	//Day is schedule.Today or schedule.Tomorrow
+	Day      string
+	Snapshot schedule.Snapshot
+	Events   []seattlefoodtruck.Event
+	Options  render.Options
+	//Interactive posts answer a request, the others are scheduled
+	Interactive bool
+	//Updated posts replaced the messages of an earlier post of the same schedule
+	Updated bool
+}
+
+//Hooks keep the bot's features built on schedule posts, such as RSVPs and reminders,
+//in step with what a Publisher fetches and posts
+type Hooks interface {
+	//Fetched is handed the events booked at loc on day, formatted as 2006-01-02
+	Fetched(loc seattlefoodtruck.Location, day string, events []seattlefoodtruck.Event)
+	//Decorate fills in the options the bot knows besides the events, such as the weather
+	Decorate(channel string, loc seattlefoodtruck.Location, snap schedule.Snapshot, events []seattlefoodtruck.Event, opts *render.Options)
+	//Going returns who said they're going to which truck of an earlier post
+	Going(post PostRecord) map[string][]string
+	Posted(post Post)
+	//Deleted is told about a message of an earlier post that was deleted
+	Deleted(channel, ts string)
+}
+
+//PostError carries the message shown to users when posting events fails
+type PostError struct {
+	Reason i18n.Key
+	Err    error
+}
+
+func (e *PostError) Error() string {
+	return i18n.T(i18n.DefaultLocale, e.Reason) + ": " + e.Err.Error()
+}
+
+//Publisher posts the events booked at locations into Slack channels, one post per
+//location. Its dependencies are injected so it holds no state of its own.
+type Publisher struct {
+	//Slack returns the client to call Slack with, it changes as credentials rotate
+	Slack func() Client
+	//Post makes scheduled posts, which may be deferred for quiet hours
+	Post     PostFunc
+	Proxy    seattlefoodtruck.FoodTruckClient
+	Posts    Posts
+	Channels Channels
+	Hooks    Hooks
+	Location *time.Location
+	Logger   *zap.SugaredLogger
+	//Social and MenuItems are passed on to render.Options
+	Social    bool
+	MenuItems int
+}
+
+//Publish posts the events of each location, trucks listed in order or else the
+//channel's. Interactive requests reuse the last post of the day: an unchanged schedule
+//is referenced, a changed one updated. They are always told when a location has no
+//trucks, scheduled posts only when the channel asks for it. Locations whose post keys
+//are in posted are skipped, the keys of those posted are added to it. Failures are
+//returned as *PostError so callers decide how to surface them.
+func (p *Publisher) Publish(channel, day string, forLocations []string, interactive bool, order string, posted map[string]bool) error {
+	post := p.Post
+	if interactive {
+		post = p.Slack().PostMessage
+	}
+	if len(order) == 0 {
+		order = p.Channels.Order(channel)
+	}
+	if len(forLocations) == 0 {
+		return &PostError{i18n.ErrNoLocations, errors.New("no locations configured for channel " + channel)}
+	}
+	on := schedule.DayOf(day, time.Now().In(p.Location)).Format("2006-01-02")
+	locale := p.Channels.Locale(channel)
+	for i, id := range forLocations {
+		loc, err := p.Proxy.GetLocation(id)
+		if err != nil {
+			return &PostError{i18n.ErrLocation, err}
+		}
+		key := PostKey(channel, loc.ID, on)
+		if posted[key] {
+			continue
+		}
+		events, err := p.Proxy.GetEvents(id, day)
+		if err != nil {
+			return &PostError{i18n.ErrEvents, err}
+		}
+		p.Hooks.Fetched(loc, on, events)
+		if len(events) == 0 {
+			if interactive || p.Channels.NotifyEmpty(channel) {
+				p.postNoEvents(post, channel, day, loc)
+			} else {
+				p.Logger.Info("No events, skipping")
+			}
+			markPosted(posted, key)
+			continue
+		}
+
+		snap := schedule.TakeSnapshot(on, events)
+		opts := render.Options{More: i < len(forLocations)-1, Order: order, Social: p.Social, MenuItems: p.MenuItems,
+			Day: dayName(day, locale), Style: p.Channels.Style(channel), Locale: locale}
+		opts.RSVP = opts.Style != render.StyleCompact
+		p.Hooks.Decorate(channel, loc, snap, events, &opts)
+		if last, ok := p.Posts.Last(key); ok && interactive {
+			opts.Going = p.Hooks.Going(last)
+		}
+		parts := render.Split(render.Events(loc, events, p.Proxy.GetTruck, p.Location, opts))
+		published := Post{Channel: channel, Parts: parts, Location: loc, Day: day, Snapshot: snap, Events: events, Options: opts, Interactive: interactive}
+		if interactive {
+			tss, done, err := p.repost(channel, key, loc, snap, parts)
+			if len(tss) > 0 {
+				published.TS, published.Updated = tss, true
+				p.Hooks.Posted(published)
+			}
+			if err != nil {
+				return &PostError{i18n.ErrUpdateEvents, err}
+			}
+			if done {
+				continue
+			}
+		}
+
+		_, ts, err := post(channel, slack.MsgOptionText(parts[0].Text, false), slack.MsgOptionBlocks(parts[0].Blocks.BlockSet...))
+		if err != nil {
+			return &PostError{i18n.ErrPostEvents, err}
+		}
+		tss, err := p.postContinuations(post, channel, ts, parts[1:])
+		if err != nil {
+			return &PostError{i18n.ErrPostSomeEvents, err}
+		}
+		markPosted(posted, key)
+		if len(ts) == 0 {
+			continue
+		}
+		p.Posts.Record(key, channel, ts, tss, snap)
+		published.TS = append([]string{ts}, tss...)
+		p.Hooks.Posted(published)
+	}
+	return nil
+}
+
+//Find answers an interactive request, apologizing in channel when posting fails.
+//An empty order lists trucks in the channel's order.
+func (p *Publisher) Find(channel, day string, forLocations []string, order string) {
+	err := p.Publish(channel, day, forLocations, true, order, nil)
+	if err == nil {
+		return
+	}
+	p.Logger.Errorw("Error posting events", zap.Error(err))
+	var pe *PostError
+	if errors.As(err, &pe) {
+		p.Slack().PostMessage(channel, slack.MsgOptionText(i18n.T(p.Channels.Locale(channel), pe.Reason), false))
+	}
+}
+
+//markPosted adds key to posted unless posted is nil
+func markPosted(posted map[string]bool, key string) {
+	if posted != nil {
+		posted[key] = true
+	}
+}
+
+//postContinuations posts the parts of a schedule that didn't fit its first message,
+//threaded under it at ts or, when it wasn't posted yet, after it with post. It returns
+//the ts of every threaded part.
+func (p *Publisher) postContinuations(post PostFunc, channel, ts string, parts []slack.Message) ([]string, error) {
+	var tss []string
+	for _, part := range parts {
+		if len(ts) == 0 {
+			if _, _, err := post(channel, slack.MsgOptionText(part.Text, false), slack.MsgOptionBlocks(part.Blocks.BlockSet...)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		_, partTS, err := p.Slack().PostMessage(channel, slack.MsgOptionText(part.Text, false), slack.MsgOptionBlocks(part.Blocks.BlockSet...), slack.MsgOptionTS(ts))
+		if err != nil {
+			return nil, err
+		}
+		tss = append(tss, partTS)
+	}
+	return tss, nil
+}
+
+//postNoEvents tells the channel there are no trucks at loc and when the next ones are booked
+func (p *Publisher) postNoEvents(post PostFunc, channel, day string, loc seattlefoodtruck.Location) {
+	on := schedule.DayOf(day, time.Now().In(p.Location))
+	locale := p.Channels.Locale(channel)
+	text := i18n.T(locale, i18n.NoTrucks, loc.Name, dayWord(day, locale), on.Format("Mon Jan 2"))
+
+	_, next, err := p.Proxy.NextEvents(context.TODO(), loc.ID, on, LookaheadDays)
+	switch {
+	case err != nil:
+		p.Logger.Errorw("Error looking ahead for events", zap.Error(err))
+	case next.IsZero():
+		text += i18n.T(locale, i18n.NoTrucksAhead, LookaheadDays)
+	default:
+		text += i18n.T(locale, i18n.NextTrucks, next.Format("Monday, Jan 2"))
+	}
+	if _, _, err := post(channel, slack.MsgOptionText(text, false)); err != nil {
+		p.Logger.Errorw("Error posting message to channel", zap.Error(err))
+	}
+}
+
+//repost handles an interactive request for a schedule already posted today. An unchanged
+//schedule is answered with a pointer to the earlier post, a changed one updates that post
+//in place, each of parts replacing the message of the post at the same index. Parts the
+//post lacks are threaded under it and messages left over deleted. It reports whether
+//the request was handled and, when the post was updated, the ts of each part.
+func (p *Publisher) repost(channel, key string, loc seattlefoodtruck.Location, snap schedule.Snapshot, parts []slack.Message) ([]string, bool, error) {
+	last, ok := p.Posts.Last(key)
+	if !ok {
+		return nil, false, nil
+	}
+
+	api := p.Slack()
+	locale := p.Channels.Locale(channel)
+	link := i18n.T(locale, i18n.RepostAbove)
+	if permalink, err := api.GetPermalink(&slack.PermalinkParameters{Channel: channel, Ts: last.TS}); err == nil {
+		link = i18n.T(locale, i18n.RepostEarlier, permalink)
+	}
+	if schedule.SameSlots(last.Snapshot, snap) {
+		text := i18n.T(locale, i18n.RepostUnchanged, loc.Name, last.PostedAt.In(p.Location).Format(time.Kitchen), link)
+		_, _, err := api.PostMessage(channel, slack.MsgOptionText(text, false))
+		return nil, err == nil, err
+	}
+
+	tss := append([]string{last.TS}, last.Parts...)
+	for i, part := range parts {
+		msg := []slack.MsgOption{slack.MsgOptionText(part.Text, false), slack.MsgOptionBlocks(part.Blocks.BlockSet...)}
+		if i >= len(tss) {
+			_, ts, err := api.PostMessage(channel, append(msg, slack.MsgOptionTS(last.TS))...)
+			if err != nil {
+				return nil, false, err
+			}
+			tss = append(tss, ts)
+			continue
+		}
+		if _, _, _, err := api.UpdateMessage(channel, tss[i], msg...); err != nil {
+			if i == 0 {
+				p.Logger.Warnw("Error updating earlier post, posting again", zap.Error(err))
+				return nil, false, nil
+			}
+			return nil, false, err
+		}
+	}
+	for _, ts := range tss[len(parts):] {
+		if _, _, err := api.DeleteMessage(channel, ts); err != nil {
+			p.Logger.Warnw("Error deleting part of earlier post", "ts", ts, zap.Error(err))
+		}
+		p.Hooks.Deleted(channel, ts)
+	}
+	tss = tss[:len(parts)]
+	p.Posts.Record(key, channel, last.TS, tss[1:], snap)
+	text := i18n.T(locale, i18n.RepostChanged, loc.Name, link)
+	_, _, err := api.PostMessage(channel, slack.MsgOptionText(text, false))
+	return tss, err == nil, err
+}
+
+//dayName returns how posts name day in locale, empty unless it is today or tomorrow
+func dayName(day, locale string) string {
+	if day == schedule.Today || day == schedule.Tomorrow {
+		return dayWord(day, locale)
+	}
+	return ""
+}
+
+//dayWord returns today or tomorrow in locale
+func dayWord(day, locale string) string {
+	if day == schedule.Tomorrow {
+		return i18n.T(locale, i18n.Tomorrow)
+	}
+	return i18n.T(locale, i18n.Today)
+}
@@ -0,0 +1,150 @@
+package slackbot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/internal/render"
+	"github.com/appsbyram/seafoodtruck-slack/internal/schedule"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+	"github.com/appsbyram/seafoodtruck-slack/pkg/store"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+//fakeSlack numbers the messages posted to it and keeps their text
+type fakeSlack struct {
+	posted []string
+}
+
+func (f *fakeSlack) PostMessage(channel string, options ...slack.MsgOption) (string, string, error) {
+	_, values, err := slack.UnsafeApplyMsgOptions("token", channel, "https://slack.test/api/", options...)
+	if err != nil {
+		return "", "", err
+	}
+	f.posted = append(f.posted, values.Get("text"))
+	return channel, fmt.Sprintf("%d.000100", len(f.posted)), nil
+}
+
+func (f *fakeSlack) UpdateMessage(channel, ts string, options ...slack.MsgOption) (string, string, string, error) {
+	return channel, ts, "", nil
+}
+
+func (f *fakeSlack) DeleteMessage(channel, ts string) (string, string, error) {
+	return channel, ts, nil
+}
+
+func (f *fakeSlack) GetPermalink(params *slack.PermalinkParameters) (string, error) {
+	return "https://slack.test/archives/" + params.Channel + "/p" + params.Ts, nil
+}
+
+//fakeProxy books marination at Westlake today, the other calls aren't implemented
+type fakeProxy struct {
+	seattlefoodtruck.FoodTruckClient
+	fetches int
+}
+
+func (f *fakeProxy) GetLocation(id string) (seattlefoodtruck.Location, error) {
+	return seattlefoodtruck.Location{ID: id, Name: "Westlake"}, nil
+}
+
+func (f *fakeProxy) GetEvents(id, day string) ([]seattlefoodtruck.Event, error) {
+	f.fetches++
+	now := time.Now()
+	return []seattlefoodtruck.Event{{
+		StartTime: now.Format(time.RFC3339),
+		EndTime:   now.Add(3 * time.Hour).Format(time.RFC3339),
+		Bookings:  []seattlefoodtruck.Booking{{Truck: seattlefoodtruck.BookingTruck{ID: "marination", Name: "Marination"}}},
+	}}, nil
+}
+
+func (f *fakeProxy) GetTruck(id string) (seattlefoodtruck.Truck, error) {
+	return seattlefoodtruck.Truck{ID: id, Name: "Marination"}, nil
+}
+
+func (f *fakeProxy) NextEvents(ctx context.Context, id string, t time.Time, days int) ([]seattlefoodtruck.Event, time.Time, error) {
+	return nil, time.Time{}, nil
+}
+
+type defaultChannels struct{}
+
+func (defaultChannels) Locale(string) string    { return "" }
+func (defaultChannels) Order(string) string     { return "" }
+func (defaultChannels) Style(string) string     { return "" }
+func (defaultChannels) NotifyEmpty(string) bool { return false }
+
+//recordingHooks keeps the posts it was told about
+type recordingHooks struct {
+	posts []Post
+}
+
+func (h *recordingHooks) Fetched(seattlefoodtruck.Location, string, []seattlefoodtruck.Event) {}
+func (h *recordingHooks) Decorate(string, seattlefoodtruck.Location, schedule.Snapshot, []seattlefoodtruck.Event, *render.Options) {
+}
+func (h *recordingHooks) Going(PostRecord) map[string][]string { return nil }
+func (h *recordingHooks) Posted(post Post)                     { h.posts = append(h.posts, post) }
+func (h *recordingHooks) Deleted(string, string)               {}
+
+func newTestPublisher() (*Publisher, *fakeSlack, *fakeProxy, *recordingHooks) {
+	api, proxy, hooks := &fakeSlack{}, &fakeProxy{}, &recordingHooks{}
+	logger := zap.NewNop().Sugar()
+	return &Publisher{
+		Slack:    func() Client { return api },
+		Post:     api.PostMessage,
+		Proxy:    proxy,
+		Posts:    Posts{Store: store.NewMemoryStore(), Logger: logger},
+		Channels: defaultChannels{},
+		Hooks:    hooks,
+		Location: time.Local,
+		Logger:   logger,
+	}, api, proxy, hooks
+}
+
+func TestPublishSkipsPosted(t *testing.T) {
+	p, api, proxy, hooks := newTestPublisher()
+	posted := make(map[string]bool)
+	if err := p.Publish("C1", schedule.Today, []string{"69"}, false, "", posted); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.posted) != 1 || len(hooks.posts) != 1 || hooks.posts[0].TS[0] != "1.000100" {
+		t.Fatalf("posted %q, told about %+v, want one post", api.posted, hooks.posts)
+	}
+	if _, ok := p.Posts.Last(PostKey("C1", "69", time.Now().Format("2006-01-02"))); !ok {
+		t.Error("the post wasn't recorded")
+	}
+
+	//a retry skips the locations already posted
+	if err := p.Publish("C1", schedule.Today, []string{"69"}, false, "", posted); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.posted) != 1 || proxy.fetches != 1 {
+		t.Errorf("a retry posted %v time(s) after fetching %v time(s), want it skipped", len(api.posted), proxy.fetches)
+	}
+}
+
+func TestPublishInteractiveUnchanged(t *testing.T) {
+	p, api, _, hooks := newTestPublisher()
+	if err := p.Publish("C1", schedule.Today, []string{"69"}, false, "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Publish("C1", schedule.Today, []string{"69"}, true, "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.posted) != 2 || !strings.Contains(api.posted[1], "unchanged") {
+		t.Errorf("posted %q, want a pointer to the unchanged post", api.posted)
+	}
+	if len(hooks.posts) != 1 {
+		t.Errorf("told about %v posts, want only the first", len(hooks.posts))
+	}
+}
+
+func TestFindApologizes(t *testing.T) {
+	p, api, _, _ := newTestPublisher()
+	p.Find("C1", schedule.Today, nil, "")
+	if len(api.posted) != 1 || !strings.Contains(api.posted[0], "location") {
+		t.Errorf("posted %q, want an apology for the missing locations", api.posted)
+	}
+}
@@ -0,0 +1,29 @@
+package slackbot
+
+import (
+	"github.com/appsbyram/seafoodtruck-slack/internal/commands"
+	"github.com/slack-go/slack/slackevents"
+	"go.uber.org/zap"
+)
+
+//Handler answers the command cmd mentioned in event
+type Handler func(event *slackevents.AppMentionEvent, cmd commands.Command)
+
+//Responder answers mentions of the bot with the handler of the command they name
+type Responder struct {
+	//Handlers are keyed by command name, see commands.Parse
+	Handlers map[string]Handler
+	//Unknown answers the commands without a handler
+	Unknown Handler
+}
+
+//Respond parses the command mentioned in event and runs its handler
+func (r *Responder) Respond(log *zap.SugaredLogger, event *slackevents.AppMentionEvent) {
+	cmd := commands.Parse(event.Text)
+	log.Infow("Received mention", "channel", event.Channel, "user", event.User, "command", cmd.Name, "args", cmd.Args)
+	if handle, ok := r.Handlers[cmd.Name]; ok {
+		handle(event, cmd)
+		return
+	}
+	r.Unknown(event, cmd)
+}
@@ -0,0 +1,4 @@
+//Package telegram is a small client of the Telegram Bot API, https://core.telegram.org/bots/api,
+//covering what the bot needs: long polling for commands and sending Markdown and photo
+//messages.
+package telegram
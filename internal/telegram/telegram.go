@@ -0,0 +1,157 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+//APIURL is where the Bot API methods of a bot token are called
+const APIURL = "https://api.telegram.org/bot"
+
+//Update is an incoming update, only messages are asked for
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message"`
+}
+
+//Message is a message sent to the bot
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text"`
+}
+
+//Chat is where a message was sent
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+//response wraps the result of every method
+type response struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	Description string          `json:"description"`
+}
+
+//Client calls the Bot API as the bot of its token
+type Client struct {
+	token  string
+	client *http.Client
+}
+
+//New returns a Client of the bot of token. Long polls hold requests open, client must
+//not time out before them.
+func New(token string, client *http.Client) *Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Client{token: token, client: client}
+}
+
+//call calls method with params as JSON, decoding its result into result unless nil
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, APIURL+c.token+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		//the url holds the token, keep it out of logs
+		return errors.New("telegram " + method + ": " + strings.Replace(err.Error(), c.token, "<token>", -1))
+	}
+	defer resp.Body.Close()
+	var r response
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return err
+	}
+	if !r.OK {
+		return errors.New("telegram " + method + ": " + r.Description)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(r.Result, result)
+}
+
+//GetUpdates long polls for the messages after offset, waiting up to timeout seconds
+func (c *Client) GetUpdates(ctx context.Context, offset int64, timeout int) ([]Update, error) {
+	var updates []Update
+	err := c.call(ctx, "getUpdates", map[string]interface{}{
+		"offset":          offset,
+		"timeout":         timeout,
+		"allowed_updates": []string{"message"},
+	}, &updates)
+	return updates, err
+}
+
+//SendMessage sends text formatted as MarkdownV2 to the chat chatID, a chat id or an
+//@channel username
+func (c *Client) SendMessage(ctx context.Context, chatID, text string) error {
+	return c.call(ctx, "sendMessage", map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"parse_mode":               "MarkdownV2",
+		"disable_web_page_preview": true,
+	}, nil)
+}
+
+//SendPhoto sends the photo at photoURL with caption formatted as MarkdownV2 to chatID
+func (c *Client) SendPhoto(ctx context.Context, chatID, photoURL, caption string) error {
+	return c.call(ctx, "sendPhoto", map[string]interface{}{
+		"chat_id":    chatID,
+		"photo":      photoURL,
+		"caption":    caption,
+		"parse_mode": "MarkdownV2",
+	}, nil)
+}
+
+//escaper escapes the characters MarkdownV2 reserves,
+//https://core.telegram.org/bots/api#markdownv2-style
+var escaper = strings.NewReplacer(
+	"\\", "\\\\", "_", "\\_", "*", "\\*", "[", "\\[", "]", "\\]", "(", "\\(", ")", "\\)",
+	"~", "\\~", "`", "\\`", ">", "\\>", "#", "\\#", "+", "\\+", "-", "\\-", "=", "\\=",
+	"|", "\\|", "{", "\\{", "}", "\\}", ".", "\\.", "!", "\\!",
+)
+
+//urlEscaper escapes what MarkdownV2 reserves inside the url of a link
+var urlEscaper = strings.NewReplacer("\\", "\\\\", ")", "\\)")
+
+//Escape escapes text to show as is in a MarkdownV2 message
+func Escape(text string) string {
+	return escaper.Replace(text)
+}
+
+//Link returns a MarkdownV2 link to url showing text
+func Link(text, url string) string {
+	return "[" + Escape(text) + "](" + urlEscaper.Replace(url) + ")"
+}
+
+//Bold returns text in bold in MarkdownV2
+func Bold(text string) string {
+	return "*" + Escape(text) + "*"
+}
+
+//CommandOf returns the command a message starts with and its arguments, e.g. truck
+//and marination for /truck@seafoodbot marination. The command is empty when the
+//message isn't one.
+func CommandOf(text string) (string, string) {
+	if !strings.HasPrefix(text, "/") {
+		return "", ""
+	}
+	fields := strings.SplitN(strings.TrimSpace(text[1:]), " ", 2)
+	command := strings.ToLower(strings.SplitN(fields[0], "@", 2)[0])
+	if len(fields) == 1 {
+		return command, ""
+	}
+	return command, strings.TrimSpace(fields[1])
+}
@@ -0,0 +1,3 @@
+//Package twilio sends SMS through the Programmable Messaging API of Twilio,
+//https://www.twilio.com/docs/messaging/api/message-resource.
+package twilio
@@ -0,0 +1,80 @@
+package twilio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+//APIURL is where messages of an account are created
+const APIURL = "https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json"
+
+//e164 matches a phone number in E.164 format, e.g. +12065550100
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+//apiError is the body of a failed request
+type apiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+//Client sends messages from a number of an account
+type Client struct {
+	accountSID string
+	authToken  string
+	from       string
+	client     *http.Client
+}
+
+//New returns a Client sending from the number from of the account accountSID
+func New(accountSID, authToken, from string, client *http.Client) *Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Client{accountSID: accountSID, authToken: authToken, from: from, client: client}
+}
+
+//Send texts body to the number to
+func (c *Client) Send(ctx context.Context, to, body string) error {
+	form := url.Values{"To": {to}, "From": {c.from}, "Body": {body}}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf(APIURL, c.accountSID), strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.accountSID, c.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	var e apiError
+	if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || len(e.Message) == 0 {
+		return errors.New("twilio: " + resp.Status)
+	}
+	return fmt.Errorf("twilio: %s (%d)", e.Message, e.Code)
+}
+
+//NormalizeNumber returns number in E.164 format, dropping spaces, dots, dashes and
+//parentheses, and reports whether it is one. Numbers without a country code are taken
+//as North American.
+func NormalizeNumber(number string) (string, bool) {
+	n := strings.NewReplacer(" ", "", ".", "", "-", "", "(", "", ")", "").Replace(number)
+	if !strings.HasPrefix(n, "+") {
+		switch {
+		case len(n) == 10:
+			n = "+1" + n
+		case len(n) == 11 && n[0] == '1':
+			n = "+" + n
+		}
+	}
+	return n, e164.MatchString(n)
+}
@@ -0,0 +1,97 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const distanceMatrixURL = "https://maps.googleapis.com/maps/api/distancematrix/json"
+
+//distanceMatrix finds walking routes with the Google Distance Matrix API,
+//https://developers.google.com/maps/documentation/distance-matrix
+type distanceMatrix struct {
+	key    string
+	client *http.Client
+
+	mu sync.Mutex
+	//walks are the routes found, which don't change, keyed by from and to. Guarded by mu.
+	walks map[[2]Point]Walk
+}
+
+//NewDistanceMatrix returns a Router of the Google Distance Matrix API authenticated
+//with the api key. Routes are cached since offices and pods don't move.
+func NewDistanceMatrix(key string, client *http.Client) Router {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &distanceMatrix{
+		key:    key,
+		client: client,
+		walks:  make(map[[2]Point]Walk),
+	}
+}
+
+func (d *distanceMatrix) Walk(ctx context.Context, from, to Point) (Walk, error) {
+	d.mu.Lock()
+	walk, ok := d.walks[[2]Point{from, to}]
+	d.mu.Unlock()
+	if ok {
+		return walk, nil
+	}
+
+	q := url.Values{
+		"origins":      {from.String()},
+		"destinations": {to.String()},
+		"mode":         {"walking"},
+		"key":          {d.key},
+	}
+	req, err := http.NewRequest(http.MethodGet, distanceMatrixURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return walk, err
+	}
+	resp, err := d.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return walk, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+		Rows         []struct {
+			Elements []struct {
+				Status   string `json:"status"`
+				Distance struct {
+					Value float64 `json:"value"`
+				} `json:"distance"`
+				Duration struct {
+					Value int64 `json:"value"`
+				} `json:"duration"`
+			} `json:"elements"`
+		} `json:"rows"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return walk, err
+	}
+	if body.Status != "OK" {
+		return walk, fmt.Errorf("distance matrix answered %s: %s", body.Status, body.ErrorMessage)
+	}
+	if len(body.Rows) == 0 || len(body.Rows[0].Elements) == 0 {
+		return walk, fmt.Errorf("distance matrix found no route")
+	}
+	e := body.Rows[0].Elements[0]
+	if e.Status != "OK" {
+		return walk, fmt.Errorf("distance matrix found no route: %s", e.Status)
+	}
+	walk = Walk{Meters: e.Distance.Value, Duration: time.Duration(e.Duration.Value) * time.Second}
+
+	d.mu.Lock()
+	d.walks[[2]Point{from, to}] = walk
+	d.mu.Unlock()
+	return walk, nil
+}
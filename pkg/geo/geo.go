@@ -0,0 +1,74 @@
+//Package geo measures how far places are from each other, for telling people how far
+//a pod is from their office
+package geo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	earthRadiusMeters = 6371000
+	metersPerMile     = 1609.344
+)
+
+//Point is a place by latitude and longitude in degrees
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+//ParsePoint parses "latitude,longitude" such as "47.6205,-122.3493"
+func ParsePoint(s string) (Point, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return Point{}, fmt.Errorf("%q is not latitude,longitude", s)
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("latitude of %q: %v", s, err)
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("longitude of %q: %v", s, err)
+	}
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return Point{}, errors.New("coordinates out of range")
+	}
+	return Point{Lat: lat, Lon: lon}, nil
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("%f,%f", p.Lat, p.Lon)
+}
+
+//Distance returns the straight-line distance between a and b in meters
+func Distance(a, b Point) float64 {
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := rad(b.Lat - a.Lat)
+	dLon := rad(b.Lon - a.Lon)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(rad(a.Lat))*math.Cos(rad(b.Lat))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(h))
+}
+
+//Miles converts meters to miles
+func Miles(meters float64) float64 {
+	return meters / metersPerMile
+}
+
+//Walk is the walking route between two places
+type Walk struct {
+	Meters   float64
+	Duration time.Duration
+}
+
+//Router finds walking routes
+type Router interface {
+	Walk(ctx context.Context, from, to Point) (Walk, error)
+}
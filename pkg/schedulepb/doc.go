@@ -0,0 +1,6 @@
+//Package schedulepb holds the messages and gRPC stubs of schedule.proto, generated by
+//protoc-gen-go v1.3.2 with its grpc plugin, matching the golang/protobuf and grpc
+//versions of go.mod. Run `go generate ./pkg/schedulepb` after editing the proto.
+package schedulepb
+
+//go:generate protoc --go_out=plugins=grpc,paths=source_relative:. schedule.proto
@@ -0,0 +1,895 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: schedule.proto
+
+package schedulepb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type ListEventsRequest struct {
+	LocationId string `protobuf:"bytes,1,opt,name=location_id,json=locationId,proto3" json:"location_id,omitempty"`
+	// today, tomorrow or a date formatted 2006-01-02, today when empty.
+	Day                  string   `protobuf:"bytes,2,opt,name=day,proto3" json:"day,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListEventsRequest) Reset()         { *m = ListEventsRequest{} }
+func (m *ListEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListEventsRequest) ProtoMessage()    {}
+func (*ListEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d00842e68e05382a, []int{0}
+}
+
+func (m *ListEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListEventsRequest.Unmarshal(m, b)
+}
+func (m *ListEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListEventsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListEventsRequest.Merge(m, src)
+}
+func (m *ListEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListEventsRequest.Size(m)
+}
+func (m *ListEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListEventsRequest proto.InternalMessageInfo
+
+func (m *ListEventsRequest) GetLocationId() string {
+	if m != nil {
+		return m.LocationId
+	}
+	return ""
+}
+
+func (m *ListEventsRequest) GetDay() string {
+	if m != nil {
+		return m.Day
+	}
+	return ""
+}
+
+type ListEventsResponse struct {
+	Events               []*Event `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListEventsResponse) Reset()         { *m = ListEventsResponse{} }
+func (m *ListEventsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListEventsResponse) ProtoMessage()    {}
+func (*ListEventsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d00842e68e05382a, []int{1}
+}
+
+func (m *ListEventsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListEventsResponse.Unmarshal(m, b)
+}
+func (m *ListEventsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListEventsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListEventsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListEventsResponse.Merge(m, src)
+}
+func (m *ListEventsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListEventsResponse.Size(m)
+}
+func (m *ListEventsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListEventsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListEventsResponse proto.InternalMessageInfo
+
+func (m *ListEventsResponse) GetEvents() []*Event {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type GetTruckRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTruckRequest) Reset()         { *m = GetTruckRequest{} }
+func (m *GetTruckRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTruckRequest) ProtoMessage()    {}
+func (*GetTruckRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d00842e68e05382a, []int{2}
+}
+
+func (m *GetTruckRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTruckRequest.Unmarshal(m, b)
+}
+func (m *GetTruckRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTruckRequest.Marshal(b, m, deterministic)
+}
+func (m *GetTruckRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTruckRequest.Merge(m, src)
+}
+func (m *GetTruckRequest) XXX_Size() int {
+	return xxx_messageInfo_GetTruckRequest.Size(m)
+}
+func (m *GetTruckRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTruckRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTruckRequest proto.InternalMessageInfo
+
+func (m *GetTruckRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type GetLocationRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetLocationRequest) Reset()         { *m = GetLocationRequest{} }
+func (m *GetLocationRequest) String() string { return proto.CompactTextString(m) }
+func (*GetLocationRequest) ProtoMessage()    {}
+func (*GetLocationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d00842e68e05382a, []int{3}
+}
+
+func (m *GetLocationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetLocationRequest.Unmarshal(m, b)
+}
+func (m *GetLocationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetLocationRequest.Marshal(b, m, deterministic)
+}
+func (m *GetLocationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetLocationRequest.Merge(m, src)
+}
+func (m *GetLocationRequest) XXX_Size() int {
+	return xxx_messageInfo_GetLocationRequest.Size(m)
+}
+func (m *GetLocationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetLocationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetLocationRequest proto.InternalMessageInfo
+
+func (m *GetLocationRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type StreamScheduleChangesRequest struct {
+	// Only changes at these locations, all watched locations when empty.
+	LocationIds          []string `protobuf:"bytes,1,rep,name=location_ids,json=locationIds,proto3" json:"location_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamScheduleChangesRequest) Reset()         { *m = StreamScheduleChangesRequest{} }
+func (m *StreamScheduleChangesRequest) String() string { return proto.CompactTextString(m) }
+func (*StreamScheduleChangesRequest) ProtoMessage()    {}
+func (*StreamScheduleChangesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d00842e68e05382a, []int{4}
+}
+
+func (m *StreamScheduleChangesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamScheduleChangesRequest.Unmarshal(m, b)
+}
+func (m *StreamScheduleChangesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamScheduleChangesRequest.Marshal(b, m, deterministic)
+}
+func (m *StreamScheduleChangesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamScheduleChangesRequest.Merge(m, src)
+}
+func (m *StreamScheduleChangesRequest) XXX_Size() int {
+	return xxx_messageInfo_StreamScheduleChangesRequest.Size(m)
+}
+func (m *StreamScheduleChangesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamScheduleChangesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamScheduleChangesRequest proto.InternalMessageInfo
+
+func (m *StreamScheduleChangesRequest) GetLocationIds() []string {
+	if m != nil {
+		return m.LocationIds
+	}
+	return nil
+}
+
+type Location struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Address              string   `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Latitude             float64  `protobuf:"fixed64,4,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude            float64  `protobuf:"fixed64,5,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Neighborhood         string   `protobuf:"bytes,6,opt,name=neighborhood,proto3" json:"neighborhood,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Location) Reset()         { *m = Location{} }
+func (m *Location) String() string { return proto.CompactTextString(m) }
+func (*Location) ProtoMessage()    {}
+func (*Location) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d00842e68e05382a, []int{5}
+}
+
+func (m *Location) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Location.Unmarshal(m, b)
+}
+func (m *Location) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Location.Marshal(b, m, deterministic)
+}
+func (m *Location) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Location.Merge(m, src)
+}
+func (m *Location) XXX_Size() int {
+	return xxx_messageInfo_Location.Size(m)
+}
+func (m *Location) XXX_DiscardUnknown() {
+	xxx_messageInfo_Location.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Location proto.InternalMessageInfo
+
+func (m *Location) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Location) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Location) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Location) GetLatitude() float64 {
+	if m != nil {
+		return m.Latitude
+	}
+	return 0
+}
+
+func (m *Location) GetLongitude() float64 {
+	if m != nil {
+		return m.Longitude
+	}
+	return 0
+}
+
+func (m *Location) GetNeighborhood() string {
+	if m != nil {
+		return m.Neighborhood
+	}
+	return ""
+}
+
+type Truck struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description          string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Rating               float64  `protobuf:"fixed64,4,opt,name=rating,proto3" json:"rating,omitempty"`
+	RatingCount          int32    `protobuf:"varint,5,opt,name=rating_count,json=ratingCount,proto3" json:"rating_count,omitempty"`
+	Categories           []string `protobuf:"bytes,6,rep,name=categories,proto3" json:"categories,omitempty"`
+	Website              string   `protobuf:"bytes,7,opt,name=website,proto3" json:"website,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Truck) Reset()         { *m = Truck{} }
+func (m *Truck) String() string { return proto.CompactTextString(m) }
+func (*Truck) ProtoMessage()    {}
+func (*Truck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d00842e68e05382a, []int{6}
+}
+
+func (m *Truck) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Truck.Unmarshal(m, b)
+}
+func (m *Truck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Truck.Marshal(b, m, deterministic)
+}
+func (m *Truck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Truck.Merge(m, src)
+}
+func (m *Truck) XXX_Size() int {
+	return xxx_messageInfo_Truck.Size(m)
+}
+func (m *Truck) XXX_DiscardUnknown() {
+	xxx_messageInfo_Truck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Truck proto.InternalMessageInfo
+
+func (m *Truck) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Truck) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Truck) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *Truck) GetRating() float64 {
+	if m != nil {
+		return m.Rating
+	}
+	return 0
+}
+
+func (m *Truck) GetRatingCount() int32 {
+	if m != nil {
+		return m.RatingCount
+	}
+	return 0
+}
+
+func (m *Truck) GetCategories() []string {
+	if m != nil {
+		return m.Categories
+	}
+	return nil
+}
+
+func (m *Truck) GetWebsite() string {
+	if m != nil {
+		return m.Website
+	}
+	return ""
+}
+
+type Booking struct {
+	TruckId              string   `protobuf:"bytes,1,opt,name=truck_id,json=truckId,proto3" json:"truck_id,omitempty"`
+	TruckName            string   `protobuf:"bytes,2,opt,name=truck_name,json=truckName,proto3" json:"truck_name,omitempty"`
+	Categories           []string `protobuf:"bytes,3,rep,name=categories,proto3" json:"categories,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Booking) Reset()         { *m = Booking{} }
+func (m *Booking) String() string { return proto.CompactTextString(m) }
+func (*Booking) ProtoMessage()    {}
+func (*Booking) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d00842e68e05382a, []int{7}
+}
+
+func (m *Booking) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Booking.Unmarshal(m, b)
+}
+func (m *Booking) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Booking.Marshal(b, m, deterministic)
+}
+func (m *Booking) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Booking.Merge(m, src)
+}
+func (m *Booking) XXX_Size() int {
+	return xxx_messageInfo_Booking.Size(m)
+}
+func (m *Booking) XXX_DiscardUnknown() {
+	xxx_messageInfo_Booking.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Booking proto.InternalMessageInfo
+
+func (m *Booking) GetTruckId() string {
+	if m != nil {
+		return m.TruckId
+	}
+	return ""
+}
+
+func (m *Booking) GetTruckName() string {
+	if m != nil {
+		return m.TruckName
+	}
+	return ""
+}
+
+func (m *Booking) GetCategories() []string {
+	if m != nil {
+		return m.Categories
+	}
+	return nil
+}
+
+type Event struct {
+	Id         int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	LocationId string `protobuf:"bytes,2,opt,name=location_id,json=locationId,proto3" json:"location_id,omitempty"`
+	// RFC 3339 times.
+	StartTime            string     `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime              string     `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Bookings             []*Booking `protobuf:"bytes,5,rep,name=bookings,proto3" json:"bookings,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d00842e68e05382a, []int{8}
+}
+
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (m *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(m, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Event) GetLocationId() string {
+	if m != nil {
+		return m.LocationId
+	}
+	return ""
+}
+
+func (m *Event) GetStartTime() string {
+	if m != nil {
+		return m.StartTime
+	}
+	return ""
+}
+
+func (m *Event) GetEndTime() string {
+	if m != nil {
+		return m.EndTime
+	}
+	return ""
+}
+
+func (m *Event) GetBookings() []*Booking {
+	if m != nil {
+		return m.Bookings
+	}
+	return nil
+}
+
+type ScheduleChange struct {
+	// added, cancelled or moved.
+	Kind                 string   `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	LocationId           string   `protobuf:"bytes,2,opt,name=location_id,json=locationId,proto3" json:"location_id,omitempty"`
+	LocationName         string   `protobuf:"bytes,3,opt,name=location_name,json=locationName,proto3" json:"location_name,omitempty"`
+	TruckId              string   `protobuf:"bytes,4,opt,name=truck_id,json=truckId,proto3" json:"truck_id,omitempty"`
+	Truck                string   `protobuf:"bytes,5,opt,name=truck,proto3" json:"truck,omitempty"`
+	StartTime            string   `protobuf:"bytes,6,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime              string   `protobuf:"bytes,7,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ScheduleChange) Reset()         { *m = ScheduleChange{} }
+func (m *ScheduleChange) String() string { return proto.CompactTextString(m) }
+func (*ScheduleChange) ProtoMessage()    {}
+func (*ScheduleChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d00842e68e05382a, []int{9}
+}
+
+func (m *ScheduleChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScheduleChange.Unmarshal(m, b)
+}
+func (m *ScheduleChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScheduleChange.Marshal(b, m, deterministic)
+}
+func (m *ScheduleChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScheduleChange.Merge(m, src)
+}
+func (m *ScheduleChange) XXX_Size() int {
+	return xxx_messageInfo_ScheduleChange.Size(m)
+}
+func (m *ScheduleChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScheduleChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScheduleChange proto.InternalMessageInfo
+
+func (m *ScheduleChange) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *ScheduleChange) GetLocationId() string {
+	if m != nil {
+		return m.LocationId
+	}
+	return ""
+}
+
+func (m *ScheduleChange) GetLocationName() string {
+	if m != nil {
+		return m.LocationName
+	}
+	return ""
+}
+
+func (m *ScheduleChange) GetTruckId() string {
+	if m != nil {
+		return m.TruckId
+	}
+	return ""
+}
+
+func (m *ScheduleChange) GetTruck() string {
+	if m != nil {
+		return m.Truck
+	}
+	return ""
+}
+
+func (m *ScheduleChange) GetStartTime() string {
+	if m != nil {
+		return m.StartTime
+	}
+	return ""
+}
+
+func (m *ScheduleChange) GetEndTime() string {
+	if m != nil {
+		return m.EndTime
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*ListEventsRequest)(nil), "schedulepb.ListEventsRequest")
+	proto.RegisterType((*ListEventsResponse)(nil), "schedulepb.ListEventsResponse")
+	proto.RegisterType((*GetTruckRequest)(nil), "schedulepb.GetTruckRequest")
+	proto.RegisterType((*GetLocationRequest)(nil), "schedulepb.GetLocationRequest")
+	proto.RegisterType((*StreamScheduleChangesRequest)(nil), "schedulepb.StreamScheduleChangesRequest")
+	proto.RegisterType((*Location)(nil), "schedulepb.Location")
+	proto.RegisterType((*Truck)(nil), "schedulepb.Truck")
+	proto.RegisterType((*Booking)(nil), "schedulepb.Booking")
+	proto.RegisterType((*Event)(nil), "schedulepb.Event")
+	proto.RegisterType((*ScheduleChange)(nil), "schedulepb.ScheduleChange")
+}
+
+func init() { proto.RegisterFile("schedule.proto", fileDescriptor_d00842e68e05382a) }
+
+var fileDescriptor_d00842e68e05382a = []byte{
+	// 648 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xcb, 0x6e, 0xd3, 0x40,
+	0x14, 0x95, 0xf3, 0xce, 0x4d, 0x29, 0x74, 0x28, 0xc8, 0x84, 0xb6, 0xa4, 0x86, 0x45, 0x58, 0x90,
+	0xa0, 0x22, 0x21, 0xc4, 0x06, 0xd1, 0x08, 0xaa, 0x8a, 0x8a, 0x85, 0xdb, 0x15, 0x9b, 0x68, 0xec,
+	0xb9, 0x38, 0xa3, 0x24, 0x33, 0xc6, 0x33, 0x01, 0xf5, 0x7b, 0x90, 0xf8, 0x07, 0xb6, 0xfc, 0x03,
+	0xff, 0x83, 0x3c, 0x7e, 0xbb, 0xb4, 0xb0, 0x9b, 0xfb, 0xf0, 0xdc, 0x73, 0xce, 0x5c, 0x1f, 0xd8,
+	0x56, 0xfe, 0x02, 0xd9, 0x66, 0x85, 0x93, 0x30, 0x92, 0x5a, 0x12, 0xc8, 0xe2, 0xd0, 0x73, 0xde,
+	0xc3, 0xce, 0x19, 0x57, 0xfa, 0xdd, 0x57, 0x14, 0x5a, 0xb9, 0xf8, 0x65, 0x83, 0x4a, 0x93, 0x47,
+	0x30, 0x58, 0x49, 0x9f, 0x6a, 0x2e, 0xc5, 0x9c, 0x33, 0xdb, 0x1a, 0x59, 0xe3, 0xbe, 0x0b, 0x59,
+	0xea, 0x94, 0x91, 0x3b, 0xd0, 0x64, 0xf4, 0xd2, 0x6e, 0x98, 0x42, 0x7c, 0x74, 0xde, 0x00, 0x29,
+	0xdf, 0xa3, 0x42, 0x29, 0x14, 0x92, 0xa7, 0xd0, 0x41, 0x93, 0xb1, 0xad, 0x51, 0x73, 0x3c, 0x38,
+	0xda, 0x99, 0x14, 0xa3, 0x27, 0xa6, 0xd7, 0x4d, 0x1b, 0x9c, 0x43, 0xb8, 0x7d, 0x82, 0xfa, 0x22,
+	0xda, 0xf8, 0xcb, 0x0c, 0xc6, 0x36, 0x34, 0xf2, 0xe9, 0x0d, 0xce, 0x9c, 0x27, 0x40, 0x4e, 0x50,
+	0x9f, 0xa5, 0x30, 0xae, 0xeb, 0x7a, 0x0b, 0x7b, 0xe7, 0x3a, 0x42, 0xba, 0x3e, 0x4f, 0x47, 0xcd,
+	0x16, 0x54, 0x04, 0x98, 0x93, 0x3b, 0x84, 0xad, 0x12, 0xb9, 0x04, 0x59, 0xdf, 0x1d, 0x14, 0xec,
+	0x94, 0xf3, 0xc3, 0x82, 0x5e, 0x36, 0xa6, 0x7e, 0x3f, 0x21, 0xd0, 0x12, 0x74, 0x8d, 0x29, 0x79,
+	0x73, 0x26, 0x36, 0x74, 0x29, 0x63, 0x11, 0x2a, 0x65, 0x37, 0x4d, 0x3a, 0x0b, 0xc9, 0x10, 0x7a,
+	0x2b, 0xaa, 0xb9, 0xde, 0x30, 0xb4, 0x5b, 0x23, 0x6b, 0x6c, 0xb9, 0x79, 0x4c, 0xf6, 0xa0, 0xbf,
+	0x92, 0x22, 0x48, 0x8a, 0x6d, 0x53, 0x2c, 0x12, 0xc4, 0x81, 0x2d, 0x81, 0x3c, 0x58, 0x78, 0x32,
+	0x5a, 0x48, 0xc9, 0xec, 0x8e, 0xb9, 0xb8, 0x92, 0x73, 0x7e, 0x59, 0xd0, 0x36, 0x92, 0xfd, 0x17,
+	0xca, 0x11, 0x0c, 0x18, 0x2a, 0x3f, 0xe2, 0x61, 0x4c, 0x2c, 0x45, 0x5a, 0x4e, 0x91, 0xfb, 0xd0,
+	0x89, 0xa8, 0xe6, 0x22, 0x48, 0xb1, 0xa6, 0x51, 0xac, 0x59, 0x72, 0x9a, 0xfb, 0x72, 0x23, 0xb4,
+	0x01, 0xdb, 0x76, 0x07, 0x49, 0x6e, 0x16, 0xa7, 0xc8, 0x01, 0x80, 0x4f, 0x35, 0x06, 0x32, 0xe2,
+	0xa8, 0xec, 0x8e, 0x11, 0xb5, 0x94, 0x89, 0x25, 0xfa, 0x86, 0x9e, 0xe2, 0x1a, 0xed, 0x6e, 0x22,
+	0x51, 0x1a, 0x3a, 0x3e, 0x74, 0x8f, 0xa5, 0x5c, 0xc6, 0x73, 0x1e, 0x40, 0x4f, 0xc7, 0x74, 0x8a,
+	0xad, 0xeb, 0x9a, 0xf8, 0x94, 0x91, 0x7d, 0x80, 0xa4, 0x54, 0xa2, 0xd5, 0x37, 0x99, 0x8f, 0x31,
+	0xb7, 0xea, 0xf8, 0x66, 0x7d, 0xbc, 0xf3, 0xdd, 0x82, 0xb6, 0x59, 0xb8, 0x92, 0x52, 0x4d, 0xa3,
+	0x54, 0x6d, 0xd9, 0x1b, 0x57, 0x96, 0x7d, 0x1f, 0x40, 0x69, 0x1a, 0xe9, 0xb9, 0xe6, 0x6b, 0x4c,
+	0x55, 0xeb, 0x9b, 0xcc, 0x05, 0x5f, 0x63, 0x8c, 0x19, 0x05, 0x4b, 0x8a, 0xad, 0x04, 0x33, 0x0a,
+	0x66, 0x4a, 0x53, 0xe8, 0x79, 0x09, 0x33, 0x65, 0xb7, 0xcd, 0x0f, 0x70, 0xb7, 0xfc, 0x03, 0xa4,
+	0xac, 0xdd, 0xbc, 0xc9, 0xf9, 0x6d, 0xc1, 0x76, 0x75, 0x6d, 0xe3, 0x87, 0x5c, 0x72, 0x91, 0xc9,
+	0x61, 0xce, 0xff, 0x86, 0xfc, 0x18, 0x6e, 0xe5, 0x0d, 0x82, 0xe6, 0xa8, 0xf3, 0xc5, 0x37, 0x92,
+	0x95, 0xc5, 0x6e, 0x55, 0xc5, 0xde, 0x85, 0xb6, 0x39, 0x9a, 0x87, 0xee, 0xbb, 0x49, 0x50, 0x13,
+	0xa2, 0x73, 0x93, 0x10, 0xdd, 0x8a, 0x10, 0x47, 0x3f, 0x1b, 0xd0, 0xcb, 0x78, 0x91, 0x0f, 0x00,
+	0x85, 0x55, 0x90, 0xfd, 0xb2, 0x22, 0x57, 0xac, 0x68, 0x78, 0x70, 0x5d, 0x39, 0x75, 0x98, 0xd7,
+	0xd0, 0xcb, 0x6c, 0x83, 0x3c, 0x2c, 0xf7, 0xd6, 0xcc, 0x64, 0x58, 0xb1, 0x9e, 0xa4, 0x7f, 0x06,
+	0x83, 0x92, 0x9f, 0x90, 0x83, 0xda, 0xe7, 0x35, 0xa3, 0x19, 0xee, 0x56, 0xa0, 0x64, 0x5f, 0xcd,
+	0xe1, 0xde, 0x5f, 0xed, 0x86, 0x8c, 0xcb, 0xed, 0x37, 0x39, 0xd2, 0x70, 0x58, 0xe9, 0xac, 0xf4,
+	0x3c, 0xb7, 0x8e, 0x5f, 0x7d, 0x7a, 0x19, 0x70, 0xbd, 0xd8, 0x78, 0x13, 0x5f, 0xae, 0xa7, 0x34,
+	0x0c, 0x95, 0x77, 0x19, 0xd1, 0xf5, 0x54, 0x21, 0xfd, 0x2c, 0x25, 0x33, 0x4f, 0xf3, 0x4c, 0xad,
+	0xa8, 0xbf, 0x9c, 0x86, 0xcb, 0x60, 0x5a, 0x5c, 0xe5, 0x75, 0x8c, 0xdd, 0xbf, 0xf8, 0x33, 0x00,
+	0x21, 0xc4, 0xb6, 0x5b, 0x00, 0x06, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ScheduleClient is the client API for Schedule service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ScheduleClient interface {
+	// ListEvents returns the events booked at a location on a day.
+	ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error)
+	// GetTruck returns a truck.
+	GetTruck(ctx context.Context, in *GetTruckRequest, opts ...grpc.CallOption) (*Truck, error)
+	// GetLocation returns a location.
+	GetLocation(ctx context.Context, in *GetLocationRequest, opts ...grpc.CallOption) (*Location, error)
+	// StreamScheduleChanges sends the changes to today's bookings found by the watcher.
+	StreamScheduleChanges(ctx context.Context, in *StreamScheduleChangesRequest, opts ...grpc.CallOption) (Schedule_StreamScheduleChangesClient, error)
+}
+
+type scheduleClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewScheduleClient(cc *grpc.ClientConn) ScheduleClient {
+	return &scheduleClient{cc}
+}
+
+func (c *scheduleClient) ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error) {
+	out := new(ListEventsResponse)
+	err := c.cc.Invoke(ctx, "/schedulepb.Schedule/ListEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scheduleClient) GetTruck(ctx context.Context, in *GetTruckRequest, opts ...grpc.CallOption) (*Truck, error) {
+	out := new(Truck)
+	err := c.cc.Invoke(ctx, "/schedulepb.Schedule/GetTruck", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scheduleClient) GetLocation(ctx context.Context, in *GetLocationRequest, opts ...grpc.CallOption) (*Location, error) {
+	out := new(Location)
+	err := c.cc.Invoke(ctx, "/schedulepb.Schedule/GetLocation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *scheduleClient) StreamScheduleChanges(ctx context.Context, in *StreamScheduleChangesRequest, opts ...grpc.CallOption) (Schedule_StreamScheduleChangesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Schedule_serviceDesc.Streams[0], "/schedulepb.Schedule/StreamScheduleChanges", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &scheduleStreamScheduleChangesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Schedule_StreamScheduleChangesClient interface {
+	Recv() (*ScheduleChange, error)
+	grpc.ClientStream
+}
+
+type scheduleStreamScheduleChangesClient struct {
+	grpc.ClientStream
+}
+
+func (x *scheduleStreamScheduleChangesClient) Recv() (*ScheduleChange, error) {
+	m := new(ScheduleChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ScheduleServer is the server API for Schedule service.
+type ScheduleServer interface {
+	// ListEvents returns the events booked at a location on a day.
+	ListEvents(context.Context, *ListEventsRequest) (*ListEventsResponse, error)
+	// GetTruck returns a truck.
+	GetTruck(context.Context, *GetTruckRequest) (*Truck, error)
+	// GetLocation returns a location.
+	GetLocation(context.Context, *GetLocationRequest) (*Location, error)
+	// StreamScheduleChanges sends the changes to today's bookings found by the watcher.
+	StreamScheduleChanges(*StreamScheduleChangesRequest, Schedule_StreamScheduleChangesServer) error
+}
+
+// UnimplementedScheduleServer can be embedded to have forward compatible implementations.
+type UnimplementedScheduleServer struct {
+}
+
+func (*UnimplementedScheduleServer) ListEvents(ctx context.Context, req *ListEventsRequest) (*ListEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEvents not implemented")
+}
+func (*UnimplementedScheduleServer) GetTruck(ctx context.Context, req *GetTruckRequest) (*Truck, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTruck not implemented")
+}
+func (*UnimplementedScheduleServer) GetLocation(ctx context.Context, req *GetLocationRequest) (*Location, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLocation not implemented")
+}
+func (*UnimplementedScheduleServer) StreamScheduleChanges(req *StreamScheduleChangesRequest, srv Schedule_StreamScheduleChangesServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamScheduleChanges not implemented")
+}
+
+func RegisterScheduleServer(s *grpc.Server, srv ScheduleServer) {
+	s.RegisterService(&_Schedule_serviceDesc, srv)
+}
+
+func _Schedule_ListEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScheduleServer).ListEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/schedulepb.Schedule/ListEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScheduleServer).ListEvents(ctx, req.(*ListEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Schedule_GetTruck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTruckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScheduleServer).GetTruck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/schedulepb.Schedule/GetTruck",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScheduleServer).GetTruck(ctx, req.(*GetTruckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Schedule_GetLocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScheduleServer).GetLocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/schedulepb.Schedule/GetLocation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScheduleServer).GetLocation(ctx, req.(*GetLocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Schedule_StreamScheduleChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamScheduleChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ScheduleServer).StreamScheduleChanges(m, &scheduleStreamScheduleChangesServer{stream})
+}
+
+type Schedule_StreamScheduleChangesServer interface {
+	Send(*ScheduleChange) error
+	grpc.ServerStream
+}
+
+type scheduleStreamScheduleChangesServer struct {
+	grpc.ServerStream
+}
+
+func (x *scheduleStreamScheduleChangesServer) Send(m *ScheduleChange) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Schedule_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "schedulepb.Schedule",
+	HandlerType: (*ScheduleServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListEvents",
+			Handler:    _Schedule_ListEvents_Handler,
+		},
+		{
+			MethodName: "GetTruck",
+			Handler:    _Schedule_GetTruck_Handler,
+		},
+		{
+			MethodName: "GetLocation",
+			Handler:    _Schedule_GetLocation_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamScheduleChanges",
+			Handler:       _Schedule_StreamScheduleChanges_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "schedule.proto",
+}
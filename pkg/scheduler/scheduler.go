@@ -0,0 +1,301 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+//parser accepts standard five field specs as well as the six field specs with
+//leading seconds used by earlier releases, plus descriptors such as @daily
+var parser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom |
+	cron.Month | cron.Dow | cron.Descriptor)
+
+//EntryID identifies a job added to a Scheduler
+type EntryID int
+
+//Entry describes a scheduled job
+type Entry struct {
+	ID   EntryID
+	Name string
+	Spec string
+	Next time.Time
+	Prev time.Time
+
+	//Paused entries keep their schedule but skip their runs
+	Paused bool
+
+	schedule cron.Schedule
+	job      func()
+}
+
+//Clock tells the time and waits, it is injected so schedules can be driven deterministically
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+//SystemClock is the wall clock
+var SystemClock Clock = systemClock{}
+
+//Scheduler runs jobs on cron specs
+type Scheduler interface {
+	Add(name, spec string, job func()) (EntryID, error)
+	Remove(id EntryID)
+	Pause(id EntryID) bool
+	Resume(id EntryID) bool
+	Reschedule(id EntryID, spec string) error
+	Entries() []Entry
+	Location() *time.Location
+	Start()
+	//Stop stops scheduling new runs, the returned context is done once running jobs finished
+	Stop() context.Context
+}
+
+//cronScheduler runs its own loop over cron schedules rather than cron.New: cron reads
+//the wall clock itself so runs can't be driven by a Clock in tests, it has no Pause or
+//Reschedule keeping an entry's id, and its Stop doesn't hand back the running jobs.
+type cronScheduler struct {
+	mu      sync.Mutex
+	clock   Clock
+	loc     *time.Location
+	entries map[EntryID]*Entry
+	nextID  EntryID
+	running bool
+	wake    chan struct{}
+	stop    chan struct{}
+	jobs    sync.WaitGroup
+}
+
+//New returns a Scheduler evaluating specs in loc and reading time from clock
+func New(loc *time.Location, clock Clock) Scheduler {
+	if loc == nil {
+		loc = time.Local
+	}
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &cronScheduler{
+		clock:   clock,
+		loc:     loc,
+		entries: make(map[EntryID]*Entry),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+//Parse validates spec and returns its schedule
+func Parse(spec string) (cron.Schedule, error) {
+	return parser.Parse(spec)
+}
+
+func (s *cronScheduler) Add(name, spec string, job func()) (EntryID, error) {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	s.nextID++
+	e := &Entry{
+		ID:       s.nextID,
+		Name:     name,
+		Spec:     spec,
+		Next:     schedule.Next(s.clock.Now().In(s.loc)),
+		schedule: schedule,
+		job:      job,
+	}
+	s.entries[e.ID] = e
+	s.mu.Unlock()
+
+	s.notify()
+	return e.ID, nil
+}
+
+func (s *cronScheduler) Remove(id EntryID) {
+	s.mu.Lock()
+	delete(s.entries, id)
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+//Pause stops an entry from running until it is resumed, it reports whether the entry exists
+func (s *cronScheduler) Pause(id EntryID) bool {
+	return s.setPaused(id, true)
+}
+
+//Resume lets a paused entry run again, it reports whether the entry exists
+func (s *cronScheduler) Resume(id EntryID) bool {
+	return s.setPaused(id, false)
+}
+
+func (s *cronScheduler) setPaused(id EntryID, paused bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[id]
+	if ok {
+		e.Paused = paused
+	}
+	return ok
+}
+
+//Reschedule replaces the spec of an entry
+func (s *cronScheduler) Reschedule(id EntryID, spec string) error {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	e, ok := s.entries[id]
+	if ok {
+		e.Spec = spec
+		e.schedule = schedule
+		e.Next = schedule.Next(s.clock.Now().In(s.loc))
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("entry %v not found", id)
+	}
+	s.notify()
+	return nil
+}
+
+//Entries returns a snapshot of the scheduled jobs ordered by their next run
+func (s *cronScheduler) Entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Next.Equal(entries[j].Next) {
+			return entries[i].ID < entries[j].ID
+		}
+		return entries[i].Next.Before(entries[j].Next)
+	})
+	return entries
+}
+
+func (s *cronScheduler) Location() *time.Location {
+	return s.loc
+}
+
+func (s *cronScheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return
+	}
+	s.running = true
+	s.stop = make(chan struct{})
+	go s.run(s.stop)
+}
+
+func (s *cronScheduler) Stop() context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		s.running = false
+		close(s.stop)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		s.jobs.Wait()
+		cancel()
+	}()
+	return ctx
+}
+
+func (s *cronScheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *cronScheduler) run(stop chan struct{}) {
+	for {
+		var timer <-chan time.Time
+
+		s.mu.Lock()
+		now := s.clock.Now().In(s.loc)
+		var next time.Time
+		for _, e := range s.entries {
+			if !e.Next.IsZero() && (next.IsZero() || e.Next.Before(next)) {
+				next = e.Next
+			}
+		}
+		s.mu.Unlock()
+		if !next.IsZero() {
+			timer = s.clock.After(next.Sub(now))
+		}
+
+		select {
+		case <-timer:
+			s.runDue()
+		case <-s.wake:
+		case <-stop:
+			return
+		}
+	}
+}
+
+//runDue starts every job whose next run has passed and advances its schedule
+func (s *cronScheduler) runDue() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now().In(s.loc)
+	for _, e := range s.entries {
+		if e.Next.IsZero() || e.Next.After(now) {
+			continue
+		}
+		if !e.Paused {
+			s.jobs.Add(1)
+			go func(job func()) {
+				defer s.jobs.Done()
+				job()
+			}(e.job)
+		}
+		e.Prev = e.Next
+		e.Next = nextRun(e.schedule, e.Prev, now)
+	}
+}
+
+//wallClock formats a time of day in its own zone
+const wallClock = "2006-01-02 15:04:05"
+
+//nextRun returns the run of schedule after now, prev being the last one. When clocks
+//fall back, a run repeating the wall clock time of prev sooner than the schedule has it
+//without the change is skipped, so a job at 1:30 runs once that night while hourly
+//jobs keep running every hour.
+func nextRun(schedule cron.Schedule, prev, now time.Time) time.Time {
+	next := schedule.Next(now)
+	if prev.IsZero() || next.Format(wallClock) != prev.Format(wallClock) {
+		return next
+	}
+	_, offset := prev.Zone()
+	if next.Before(schedule.Next(prev.In(time.FixedZone("", offset)))) {
+		return schedule.Next(next)
+	}
+	return next
+}
@@ -0,0 +1,283 @@
+package scheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+//fakeClock is a Clock moved forward by hand, counting the timers it hands out so tests
+//know when the scheduler is waiting on one
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	timers  int
+	waiters []waiter
+}
+
+type waiter struct {
+	at time.Time
+	c  chan time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w := waiter{at: c.now.Add(d), c: make(chan time.Time, 1)}
+	c.timers++
+	if d <= 0 {
+		w.c <- c.now
+	} else {
+		c.waiters = append(c.waiters, w)
+	}
+	return w.c
+}
+
+//Advance moves the clock forward by d, firing the timers due by then
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.c <- c.now
+	}
+	c.waiters = pending
+}
+
+//waitTimers waits until the scheduler asked for n timers in all
+func (c *fakeClock) waitTimers(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		timers := c.timers
+		c.mu.Unlock()
+		if timers >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("the scheduler didn't wait on timer %d", n)
+}
+
+var seattle = mustLoad("America/Los_Angeles")
+
+func mustLoad(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.FixedZone("PST", -8*60*60)
+	}
+	return loc
+}
+
+func TestAddNext(t *testing.T) {
+	//a Thursday
+	now := time.Date(2026, time.October, 15, 9, 0, 0, 0, seattle)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"30 10 * * MON-FRI", time.Date(2026, time.October, 15, 10, 30, 0, 0, seattle)},
+		{"0 30 10 * * MON-FRI", time.Date(2026, time.October, 15, 10, 30, 0, 0, seattle)},
+		{"0 30 8 * * MON-FRI", time.Date(2026, time.October, 16, 8, 30, 0, 0, seattle)},
+		{"0 0 9 * * MON", time.Date(2026, time.October, 19, 9, 0, 0, 0, seattle)},
+		{"@daily", time.Date(2026, time.October, 16, 0, 0, 0, 0, seattle)},
+		{"0 0 * * * *", time.Date(2026, time.October, 15, 10, 0, 0, 0, seattle)},
+	}
+	for _, tt := range tests {
+		s := New(seattle, &fakeClock{now: now.UTC()})
+		id, err := s.Add("posts", tt.spec, func() {})
+		if err != nil {
+			t.Fatalf("Add(%q): %v", tt.spec, err)
+		}
+		e := s.Entries()[0]
+		if e.ID != id || !e.Next.Equal(tt.want) || e.Next.Location() != seattle {
+			t.Errorf("Add(%q) next run %v, want %v", tt.spec, e.Next, tt.want)
+		}
+	}
+}
+
+func TestAddInvalid(t *testing.T) {
+	s := New(seattle, &fakeClock{})
+	for _, spec := range []string{"", "every day", "0 61 10 * * *", "* * * * * * *"} {
+		if _, err := s.Add("posts", spec, func() {}); err == nil {
+			t.Errorf("Add(%q) took an invalid spec", spec)
+		}
+	}
+	if len(s.Entries()) != 0 {
+		t.Errorf("invalid specs were scheduled: %+v", s.Entries())
+	}
+}
+
+func TestRun(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, time.October, 15, 10, 0, 0, 0, seattle)}
+	s := New(seattle, clock)
+	ran := make(chan string, 2)
+	s.Add("posts", "0 30 10 * * *", func() { ran <- "posts" })
+	paused, _ := s.Add("poll", "0 15 10 * * *", func() { ran <- "poll" })
+	s.Pause(paused)
+	s.Start()
+	defer s.Stop()
+	//Add left a wake-up pending, so the scheduler takes a second timer before waiting
+	clock.waitTimers(t, 2)
+
+	clock.Advance(15 * time.Minute)
+	clock.waitTimers(t, 3)
+	clock.Advance(15 * time.Minute)
+	select {
+	case job := <-ran:
+		if job != "posts" {
+			t.Fatalf("%s ran, paused", job)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("posts didn't run at 10:30")
+	}
+	clock.waitTimers(t, 4)
+
+	for _, e := range s.Entries() {
+		wantPrev := time.Date(2026, time.October, 15, 10, 30, 0, 0, seattle)
+		if e.ID == paused {
+			wantPrev = time.Date(2026, time.October, 15, 10, 15, 0, 0, seattle)
+		}
+		if !e.Prev.Equal(wantPrev) || !e.Next.Equal(wantPrev.AddDate(0, 0, 1)) {
+			t.Errorf("%s ran at %v, next at %v, want %v and the day after", e.Name, e.Prev, e.Next, wantPrev)
+		}
+	}
+	select {
+	case job := <-ran:
+		t.Errorf("%s ran again", job)
+	default:
+	}
+}
+
+func TestReschedule(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, time.October, 15, 10, 0, 0, 0, seattle)}
+	s := New(seattle, clock)
+	id, _ := s.Add("posts", "0 30 10 * * *", func() {})
+	if err := s.Reschedule(id, "0 0 11 * * *"); err != nil {
+		t.Fatal(err)
+	}
+	if e := s.Entries()[0]; e.Spec != "0 0 11 * * *" || !e.Next.Equal(time.Date(2026, time.October, 15, 11, 0, 0, 0, seattle)) {
+		t.Errorf("rescheduled entry %+v, want the next run at 11:00", e)
+	}
+	if err := s.Reschedule(id+1, "0 0 11 * * *"); err == nil {
+		t.Error("rescheduled an entry that doesn't exist")
+	}
+	if err := s.Reschedule(id, "not a spec"); err == nil {
+		t.Error("rescheduled to an invalid spec")
+	}
+}
+
+func TestStopWaitsForJobs(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, time.October, 15, 10, 0, 0, 0, seattle)}
+	s := New(seattle, clock)
+	started, release := make(chan struct{}), make(chan struct{})
+	s.Add("posts", "0 30 10 * * *", func() {
+		close(started)
+		<-release
+	})
+	s.Start()
+	clock.waitTimers(t, 2)
+	clock.Advance(30 * time.Minute)
+	<-started
+
+	ctx := s.Stop()
+	select {
+	case <-ctx.Done():
+		t.Fatal("Stop was done while a job was running")
+	default:
+	}
+	close(release)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Stop wasn't done once the job finished")
+	}
+}
+
+func TestDST(t *testing.T) {
+	//clocks go forward at 2:00 on March 8 2026 and back at 2:00 on November 1
+	pdt, pst := time.FixedZone("PDT", -7*60*60), time.FixedZone("PST", -8*60*60)
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want []time.Time
+	}{
+		{"daily keeps its time when clocks go forward", "30 8 * * *", time.Date(2026, time.March, 7, 9, 0, 0, 0, pst),
+			[]time.Time{time.Date(2026, time.March, 8, 8, 30, 0, 0, pdt), time.Date(2026, time.March, 9, 8, 30, 0, 0, pdt)}},
+		{"daily keeps its time when clocks go back", "30 8 * * *", time.Date(2026, time.October, 31, 9, 0, 0, 0, pdt),
+			[]time.Time{time.Date(2026, time.November, 1, 8, 30, 0, 0, pst), time.Date(2026, time.November, 2, 8, 30, 0, 0, pst)}},
+		{"a time skipped going forward doesn't run that day", "30 2 * * *", time.Date(2026, time.March, 7, 12, 0, 0, 0, pst),
+			[]time.Time{time.Date(2026, time.March, 9, 2, 30, 0, 0, pdt)}},
+		{"a time repeated going back runs once", "30 1 * * *", time.Date(2026, time.October, 31, 12, 0, 0, 0, pdt),
+			[]time.Time{time.Date(2026, time.November, 1, 1, 30, 0, 0, pdt), time.Date(2026, time.November, 2, 1, 30, 0, 0, pst)}},
+		{"hourly runs every hour going back", "0 * * * *", time.Date(2026, time.November, 1, 0, 30, 0, 0, pdt),
+			[]time.Time{time.Date(2026, time.November, 1, 1, 0, 0, 0, pdt), time.Date(2026, time.November, 1, 1, 0, 0, 0, pst), time.Date(2026, time.November, 1, 2, 0, 0, 0, pst)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{now: tt.from}
+			s := New(seattle, clock).(*cronScheduler)
+			if _, err := s.Add("posts", tt.spec, func() {}); err != nil {
+				t.Fatal(err)
+			}
+			for i, want := range tt.want {
+				next := s.Entries()[0].Next
+				if !next.Equal(want) {
+					t.Fatalf("run %d at %v, want %v", i+1, next, want.In(seattle))
+				}
+				clock.Advance(next.Sub(clock.Now()))
+				s.runDue()
+			}
+		})
+	}
+}
+
+func TestRemoveRunning(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, time.October, 15, 10, 0, 0, 0, seattle)}
+	s := New(seattle, clock)
+	started, release := make(chan struct{}, 2), make(chan struct{})
+	id, _ := s.Add("posts", "0 30 10 * * *", func() {
+		started <- struct{}{}
+		<-release
+	})
+	s.Start()
+	clock.waitTimers(t, 2)
+	clock.Advance(30 * time.Minute)
+	<-started
+
+	s.Remove(id)
+	if len(s.Entries()) != 0 {
+		t.Errorf("entries after removing the running one: %+v", s.Entries())
+	}
+	ctx := s.Stop()
+	select {
+	case <-ctx.Done():
+		t.Fatal("Stop was done while the removed job was running")
+	default:
+	}
+	close(release)
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Stop wasn't done once the removed job finished")
+	}
+	clock.Advance(24 * time.Hour)
+	select {
+	case <-started:
+		t.Error("the removed job ran again")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
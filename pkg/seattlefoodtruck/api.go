@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	ws "github.com/appsbyram/pkg/http"
@@ -14,6 +15,8 @@ import (
 	"go.uber.org/zap"
 )
 
+//go:generate go run gen.go -spec openapi.yaml -out openapi_gen.go
+
 const (
 	//Today for today
 	Today = "today"
@@ -24,18 +27,42 @@ const (
 	//EventsResourcePath represents path to retrieve a collection of event resources
 	EventsResourcePath = "events"
 
+	//LocationsResourcePath represents path to retrieve a collection of location resources
+	LocationsResourcePath = "locations"
+
 	//LocationResourcePath represents path to retrieve a location resource
 	LocationResourcePath = "locations/%s"
 
+	//TrucksResourcePath represents path to retrieve a collection of truck resources
+	TrucksResourcePath = "trucks"
+
 	//TruckResourcePath represents path to retrieve truck
 	TruckResourcePath = "trucks/%s"
+
+	//NeighborhoodsResourcePath represents path to retrieve a collection of neighborhood resources
+	NeighborhoodsResourcePath = "neighborhoods"
+
+	//FoodCategoriesResourcePath represents path to retrieve a collection of food category resources
+	FoodCategoriesResourcePath = "food_categories"
 )
 
 //FoodTruckClient represents generic interface for Seattle FoodTruck API client
 type FoodTruckClient interface {
 	GetEvents(id string, onDay string) ([]Event, error)
+	GetEventsOn(ctx context.Context, id string, t time.Time) ([]Event, error)
+	GetEventsBetween(ctx context.Context, id string, from, to time.Time) ([]Event, error)
+	NextEvents(ctx context.Context, id string, t time.Time, days int) ([]Event, time.Time, error)
 	GetLocation(id string) (Location, error)
+	FindLocations(ctx context.Context, query string, neighborhoodID int) ([]Location, error)
 	GetTruck(id string) (Truck, error)
+	FoodCategories(ctx context.Context) ([]FoodCategory, error)
+	TrucksByCategory(ctx context.Context, category string) ([]Truck, error)
+	GetNeighborhoods() ([]Neighborhood, error)
+	RefreshNeighborhoods() error
+	LookupNeighborhood(name string) (Neighborhood, error)
+	IterateEvents(ctx context.Context, filter map[string]string) *EventsIterator
+	IterateTrucks(ctx context.Context, filter map[string]string) *TrucksIterator
+	SetDebug(debug bool)
 }
 
 type foodTruckClient struct {
@@ -43,58 +70,129 @@ type foodTruckClient struct {
 	scheme   string
 	basePath string
 
+	userAgent     string
+	defaultHeader map[string]string
+	location      *time.Location
+
 	client *http.Client
 	logger *zap.SugaredLogger
+
+	mu            sync.RWMutex
+	neighborhoods map[string]Neighborhood
 }
 
 //NewFoodTruckClient returns a new instance of Food Truck Client
 func NewFoodTruckClient(ctx context.Context, host, scheme, basePath string) FoodTruckClient {
-	logger := l.LoggerFromContext(ctx)
+	cfg := NewConfiguration()
+	cfg.Host = host
+	cfg.Scheme = scheme
+	cfg.BasePath = basePath
 
-	return &foodTruckClient{
-		host:     host,
-		scheme:   scheme,
-		basePath: basePath,
+	return NewFoodTruckClientFromConfig(ctx, cfg)
+}
+
+//NewFoodTruckClientFromConfig returns a new instance of Food Truck Client built from Configuration
+func NewFoodTruckClientFromConfig(ctx context.Context, cfg *Configuration) FoodTruckClient {
+	logger := l.LoggerFromContext(ctx)
 
-		client: http.DefaultClient,
+	client := cfg.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	header := make(map[string]string, len(cfg.DefaultHeader))
+	for k, v := range cfg.DefaultHeader {
+		header[k] = v
+	}
+	location := cfg.Location
+	if location == nil {
+		location = LoadLocation(DefaultTimeZone)
+	}
+	c := &foodTruckClient{
+		host:          cfg.Host,
+		scheme:        cfg.Scheme,
+		basePath:      cfg.BasePath,
+		userAgent:     cfg.UserAgent,
+		defaultHeader: header,
+		location:      location,
+
+		client: withDebug(withGzip(client), logger),
 		logger: logger,
 	}
+	c.SetDebug(cfg.Debug)
+	return c
 }
 
 func (c *foodTruckClient) GetEvents(id string, on string) ([]Event, error) {
-	var onDay string
-	var evr EventsResponse
+	//compute the day in the configured zone, not the server's
+	n := time.Now().In(c.location)
+	if on == Tomorrow {
+		n = n.AddDate(0, 0, 1)
+	}
+	return c.GetEventsOn(context.TODO(), id, n)
+}
 
+//GetEventsOn returns events booked at the location on the calendar day of t
+func (c *foodTruckClient) GetEventsOn(ctx context.Context, id string, t time.Time) ([]Event, error) {
 	if len(id) == 0 {
 		return nil, errors.New("Location ID is missing")
 	}
-
-	switch on {
-	case Tomorrow:
-		t := time.Now().AddDate(0, 0, 1)
-		onDay = fmt.Sprintf("%v-%v-%v", t.Year(), t.Month(), t.Day())
-		break
-	default:
-		n := time.Now()
-		onDay = fmt.Sprintf("%v-%v-%v", n.Year(), n.Month(), n.Day())
-		break
-	}
+	onDay := formatDay(t.In(c.location))
 
 	c.logger.Infof("On day: %s", onDay)
 
-	qs := map[string]string{
-		"include_bookings":    "true",
-		"with_active_trucks":  "true",
-		"with_booking_status": "approved",
-		"on_day":              onDay,
-		"for_locations":       id,
+	evr, err := c.listEvents(ctx, ListEventsParams{
+		IncludeBookings:   true,
+		WithActiveTrucks:  true,
+		WithBookingStatus: "approved",
+		OnDay:             onDay,
+		ForLocations:      id,
+	})
+	if err != nil {
+		return nil, err
 	}
-	endpoint := fmt.Sprintf("%s://%s%s/%s", c.scheme, c.host, c.basePath, EventsResourcePath)
-	c.logger.Infof("Endpoint: %s", endpoint)
+	return evr.Events, nil
+}
 
-	callAPI(endpoint, qs, c.client, &evr)
+//GetEventsBetween returns events booked at the location from the calendar day of from
+//through the calendar day of to, walking every page of the date range
+func (c *foodTruckClient) GetEventsBetween(ctx context.Context, id string, from, to time.Time) ([]Event, error) {
+	var events []Event
 
-	return evr.Events, nil
+	if len(id) == 0 {
+		return nil, errors.New("Location ID is missing")
+	}
+	params := ListEventsParams{
+		IncludeBookings:   true,
+		WithActiveTrucks:  true,
+		WithBookingStatus: "approved",
+		StartDate:         formatDay(from.In(c.location)),
+		EndDate:           formatDay(to.In(c.location)),
+		ForLocations:      id,
+	}
+	it := c.IterateEvents(ctx, params.values())
+	for it.Next() {
+		events = append(events, it.Event())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+//NextEvents looks ahead up to days calendar days after t and returns the events
+//of the first day with bookings at the location along with that day
+func (c *foodTruckClient) NextEvents(ctx context.Context, id string, t time.Time, days int) ([]Event, time.Time, error) {
+	for i := 1; i <= days; i++ {
+		day := t.AddDate(0, 0, i)
+		events, err := c.GetEventsOn(ctx, id, day)
+		if err != nil {
+			return nil, day, err
+		}
+		if len(events) > 0 {
+			return events, day, nil
+		}
+	}
+	return nil, time.Time{}, nil
 }
 
 func (c *foodTruckClient) GetLocation(id string) (Location, error) {
@@ -103,12 +201,7 @@ func (c *foodTruckClient) GetLocation(id string) (Location, error) {
 	if len(id) == 0 {
 		return l, errors.New("Location ID is missing")
 	}
-	endpoint := fmt.Sprintf("%s://%s%s/%s", c.scheme, c.host, c.basePath, fmt.Sprintf(LocationResourcePath, id))
-	c.logger.Infof("Endpoint: %s", endpoint)
-
-	callAPI(endpoint, nil, c.client, &l)
-
-	return l, nil
+	return c.getLocation(context.TODO(), id)
 }
 
 func (c *foodTruckClient) GetTruck(id string) (Truck, error) {
@@ -116,15 +209,10 @@ func (c *foodTruckClient) GetTruck(id string) (Truck, error) {
 	if len(id) == 0 {
 		return t, errors.New("Truck ID is required")
 	}
-	endpoint := fmt.Sprintf("%s://%s%s/%s", c.scheme, c.host, c.basePath, fmt.Sprintf(TruckResourcePath, id))
-	c.logger.Infof("Endpoint: %s", endpoint)
-
-	callAPI(endpoint, nil, c.client, &t)
-
-	return t, nil
+	return c.getTruck(context.TODO(), id)
 }
 
-func callAPI(endPoint string, qs map[string]string, client *http.Client, data interface{}) error {
+func (c *foodTruckClient) callAPI(ctx context.Context, endPoint string, qs map[string]string, data interface{}) error {
 	url, err := url.Parse(endPoint)
 	if err != nil {
 		return err
@@ -142,168 +230,21 @@ func callAPI(endPoint string, qs map[string]string, client *http.Client, data in
 	if err != nil {
 		return err
 	}
+	req = req.WithContext(ctx)
+	c.setHeaders(req)
 
 	//call api
-	resp, err := client.Do(req)
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return err
 	}
 
 	p := ws.NewPayload()
-	p.ReadResponse(ws.ContentTypeJSON, &data, resp)
-
-	return nil
-}
-
-//EventsResponse is response from events api
-type EventsResponse struct {
-	Pagination struct {
-		Page       int `json:"page"`
-		TotalPages int `json:"total_pages"`
-		TotalCount int `json:"total_count"`
-	} `json:"pagination"`
-	Events []Event `json:"events"`
-}
-
-//Event represent an event
-type Event struct {
-	ID          int    `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	StartTime   string `json:"start_time"`
-	EndTime     string `json:"end_time"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
-	EventID     int    `json:"event_id"`
-	Bookings    []struct {
-		ID     int    `json:"id"`
-		Status string `json:"status"`
-		Paid   bool   `json:"paid"`
-		Truck  struct {
-			Name           string   `json:"name"`
-			Trailer        bool     `json:"trailer"`
-			FoodCategories []string `json:"food_categories"`
-			ID             string   `json:"id"`
-			UID            int      `json:"uid"`
-			FeaturedPhoto  string   `json:"featured_photo"`
-		} `json:"truck"`
-	} `json:"bookings"`
-	WaitlistEntries []struct {
-		ID         int         `json:"id"`
-		Expiration interface{} `json:"expiration"`
-		Position   int         `json:"position"`
-		Truck      struct {
-			Slug string `json:"slug"`
-		} `json:"truck"`
-	} `json:"waitlist_entries"`
-}
-
-//Location represents a location where you can find truck
-type Location struct {
-	Name            string  `json:"name"`
-	Longitude       float64 `json:"longitude"`
-	Latitude        float64 `json:"latitude"`
-	Address         string  `json:"address"`
-	Photo           string  `json:"photo"`
-	GooglePlaceID   string  `json:"google_place_id"`
-	CreatedAt       string  `json:"created_at"`
-	NeighborhoodID  int     `json:"neighborhood_id"`
-	Slug            string  `json:"slug"`
-	FilteredAddress string  `json:"filtered_address"`
-	ID              string  `json:"id"`
-	UID             int     `json:"uid"`
-	Neighborhood    struct {
-		Name string `json:"name"`
-		ID   int    `json:"id"`
-	} `json:"neighborhood"`
-	Pod struct {
-		Name                    string      `json:"name"`
-		Slug                    string      `json:"slug"`
-		Description             string      `json:"description"`
-		LoadInSheet             string      `json:"load_in_sheet"`
-		W9Required              bool        `json:"w9_required"`
-		CoiRequired             bool        `json:"coi_required"`
-		HealthRequired          bool        `json:"health_required"`
-		HealthSnohomishRequired interface{} `json:"health_snohomish_required"`
-	} `json:"pod"`
+	return p.ReadResponse(ws.ContentTypeJSON, &data, resp)
 }
 
-//Truck represents a food truck
-type Truck struct {
-	Name                      string      `json:"name"`
-	Rating                    float64     `json:"rating"`
-	UserID                    int         `json:"user_id"`
-	Featured                  bool        `json:"featured"`
-	RatingCount               int         `json:"rating_count"`
-	ID                        string      `json:"id"`
-	UID                       int         `json:"uid"`
-	FeaturedPhoto             string      `json:"featured_photo"`
-	Facebook                  string      `json:"facebook"`
-	CreatedAt                 string      `json:"created_at"`
-	UpdatedAt                 string      `json:"updated_at"`
-	Twitter                   string      `json:"twitter"`
-	Instagram                 string      `json:"instagram"`
-	Yelp                      string      `json:"yelp"`
-	Description               string      `json:"description"`
-	Phone                     string      `json:"phone"`
-	Email                     string      `json:"email"`
-	Website                   string      `json:"website"`
-	Active                    bool        `json:"active"`
-	ContactName               string      `json:"contact_name"`
-	TruckLength               int         `json:"truck_length"`
-	TruckWidth                int         `json:"truck_width"`
-	Trailer                   bool        `json:"trailer"`
-	AcceptsCreditCards        bool        `json:"accepts_credit_cards"`
-	GlutenFree                bool        `json:"gluten_free"`
-	Vegetarian                bool        `json:"vegetarian"`
-	Vegan                     bool        `json:"vegan"`
-	Paleo                     bool        `json:"paleo"`
-	FutureBookings            int         `json:"future_bookings"`
-	FuturePodEvents           int         `json:"future_pod_events"`
-	Coi                       string      `json:"coi"`
-	CoiExpiration             string      `json:"coi_expiration"`
-	CoiStatus                 string      `json:"coi_status"`
-	CoiApproved               bool        `json:"coi_approved"`
-	Health                    string      `json:"health"`
-	HealthExpiration          string      `json:"health_expiration"`
-	HealthStatus              string      `json:"health_status"`
-	HealthApproved            bool        `json:"health_approved"`
-	W9                        string      `json:"w9"`
-	W9Expiration              interface{} `json:"w9_expiration"`
-	W9Status                  string      `json:"w9_status"`
-	W9Approved                bool        `json:"w9_approved"`
-	HealthSnohomish           string      `json:"health_snohomish"`
-	HealthSnohomishExpiration string      `json:"health_snohomish_expiration"`
-	HealthSnohomishStatus     string      `json:"health_snohomish_status"`
-	HealthSnohomishApproved   bool        `json:"health_snohomish_approved"`
-	MenuItems                 []struct {
-		Name        string  `json:"name"`
-		Description string  `json:"description"`
-		Price       float64 `json:"price"`
-		ID          int     `json:"id"`
-	} `json:"menu_items"`
-	Photos []struct {
-		ID       int    `json:"id"`
-		File     string `json:"file"`
-		Position int    `json:"position"`
-	} `json:"photos"`
-	RelatedTrucks []struct {
-		Name           string  `json:"name"`
-		Rating         float64 `json:"rating"`
-		RatingCount    int     `json:"rating_count"`
-		ID             string  `json:"id"`
-		FeaturedPhoto  string  `json:"featured_photo"`
-		FoodCategories []struct {
-			Name string `json:"name"`
-			ID   string `json:"id"`
-			UID  int    `json:"uid"`
-		} `json:"food_categories"`
-	} `json:"related_trucks"`
-	FoodCategories []struct {
-		Name string `json:"name"`
-		ID   string `json:"id"`
-		UID  int    `json:"uid"`
-	} `json:"food_categories"`
+func formatDay(t time.Time) string {
+	return fmt.Sprintf("%v-%v-%v", t.Year(), t.Month(), t.Day())
 }
 
 func trimSpaceAndLower(s string) string {
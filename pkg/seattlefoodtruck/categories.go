@@ -0,0 +1,66 @@
+package seattlefoodtruck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+func (c *foodTruckClient) FoodCategories(ctx context.Context) ([]FoodCategory, error) {
+	fcr, err := c.listFoodCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return fcr.FoodCategories, nil
+}
+
+//TrucksByCategory returns active trucks listed under the given category name or ID.
+func (c *foodTruckClient) TrucksByCategory(ctx context.Context, category string) ([]Truck, error) {
+	var trucks []Truck
+
+	fc, err := c.lookupFoodCategory(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+	it := c.IterateTrucks(ctx, map[string]string{
+		"food_category": fc.ID,
+		"active":        "true",
+	})
+	for it.Next() {
+		if t := it.Truck(); hasFoodCategory(t, fc) {
+			trucks = append(trucks, t)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return trucks, nil
+}
+
+func (c *foodTruckClient) lookupFoodCategory(ctx context.Context, category string) (FoodCategory, error) {
+	var fc FoodCategory
+
+	key := trimSpaceAndLower(category)
+	if len(key) == 0 {
+		return fc, errors.New("Food category is missing")
+	}
+	categories, err := c.FoodCategories(ctx)
+	if err != nil {
+		return fc, err
+	}
+	for _, fc = range categories {
+		if trimSpaceAndLower(fc.Name) == key || trimSpaceAndLower(fc.ID) == key {
+			return fc, nil
+		}
+	}
+	return FoodCategory{}, fmt.Errorf("Food category %s not found", category)
+}
+
+func hasFoodCategory(t Truck, fc FoodCategory) bool {
+	for _, tfc := range t.FoodCategories {
+		if tfc.ID == fc.ID || tfc.Name == fc.Name {
+			return true
+		}
+	}
+	return false
+}
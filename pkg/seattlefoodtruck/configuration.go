@@ -0,0 +1,74 @@
+package seattlefoodtruck
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	//DefaultTimeZone is the zone events are scheduled in
+	DefaultTimeZone = "America/Los_Angeles"
+
+	defaultHost      = "www.seattlefoodtruck.com"
+	defaultScheme    = "https"
+	defaultBasePath  = "/api"
+	defaultUserAgent = "seafoodtruck-slack"
+
+	acceptHeader    = "Accept"
+	userAgentHeader = "User-Agent"
+)
+
+//Configuration holds the settings used to build a Food Truck Client
+type Configuration struct {
+	Host          string            `json:"host,omitempty"`
+	Scheme        string            `json:"scheme,omitempty"`
+	BasePath      string            `json:"basePath,omitempty"`
+	DefaultHeader map[string]string `json:"defaultHeader,omitempty"`
+	UserAgent     string            `json:"userAgent,omitempty"`
+	Debug         bool              `json:"debug,omitempty"`
+	HTTPClient    *http.Client      `json:"-"`
+	Location      *time.Location    `json:"-"`
+}
+
+//NewConfiguration returns a Configuration pointing at the public Seattle Food Truck API
+func NewConfiguration() *Configuration {
+	return &Configuration{
+		Host:     defaultHost,
+		Scheme:   defaultScheme,
+		BasePath: defaultBasePath,
+		DefaultHeader: map[string]string{
+			acceptHeader: "application/json",
+		},
+		UserAgent: defaultUserAgent,
+		Location:  LoadLocation(DefaultTimeZone),
+	}
+}
+
+//LoadLocation loads the named time zone, falling back to UTC when the zone database is unavailable
+func LoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+//AddDefaultHeader adds a header sent with every outbound request
+func (c *Configuration) AddDefaultHeader(key string, value string) {
+	if c.DefaultHeader == nil {
+		c.DefaultHeader = make(map[string]string)
+	}
+	c.DefaultHeader[key] = value
+}
+
+func (c *foodTruckClient) setHeaders(req *http.Request) {
+	for k, v := range c.defaultHeader {
+		req.Header.Set(k, v)
+	}
+	if len(req.Header.Get(acceptHeader)) == 0 {
+		req.Header.Set(acceptHeader, "application/json")
+	}
+	if len(c.userAgent) > 0 {
+		req.Header.Set(userAgentHeader, c.userAgent)
+	}
+}
@@ -0,0 +1,90 @@
+package seattlefoodtruck
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	maxDebugBodyLength = 1024
+	redacted           = "REDACTED"
+)
+
+//debugTransport logs every upstream request and response passing through it while enabled
+type debugTransport struct {
+	next    http.RoundTripper
+	logger  *zap.SugaredLogger
+	enabled int32
+}
+
+func (t *debugTransport) setEnabled(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&t.enabled, v)
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if atomic.LoadInt32(&t.enabled) == 0 {
+		return t.next.RoundTrip(req)
+	}
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	latency := time.Since(start)
+
+	u := redactQuery(req.URL)
+	if err != nil {
+		t.logger.Debugw("Upstream request failed", "method", req.Method, "url", u,
+			"latency", latency, zap.Error(err))
+		return resp, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return resp, err
+	}
+	if len(body) > maxDebugBodyLength {
+		body = body[:maxDebugBodyLength]
+	}
+	t.logger.Debugw("Upstream request", "method", req.Method, "url", u,
+		"status", resp.StatusCode, "latency", latency, "body", string(body))
+	return resp, nil
+}
+
+func redactQuery(u *url.URL) string {
+	r := *u
+	query := r.Query()
+	for k := range query {
+		query.Set(k, redacted)
+	}
+	r.RawQuery = query.Encode()
+	return r.String()
+}
+
+//SetDebug turns logging upstream requests on or off. The client's transport is wrapped
+//once at construction, so this only flips a flag and is safe while requests are in flight.
+func (c *foodTruckClient) SetDebug(debug bool) {
+	if dt, ok := c.client.Transport.(*debugTransport); ok {
+		dt.setEnabled(debug)
+	}
+}
+
+//withDebug wraps the transport of client, already a copy of the configured one, in a
+//debugTransport logging to logger while enabled
+func withDebug(client *http.Client, logger *zap.SugaredLogger) *http.Client {
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	client.Transport = &debugTransport{next: next, logger: logger}
+	return client
+}
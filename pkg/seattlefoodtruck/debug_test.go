@@ -0,0 +1,60 @@
+package seattlefoodtruck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+func TestSetDebug(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"marination","name":"Marination"}`))
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errNoRedirects := errors.New("no redirects")
+	cfg := NewConfiguration()
+	cfg.Host, cfg.Scheme, cfg.Debug = u.Host, u.Scheme, true
+	cfg.HTTPClient = &http.Client{
+		Jar:           jar,
+		CheckRedirect: func(*http.Request, []*http.Request) error { return errNoRedirects },
+	}
+	c := NewFoodTruckClientFromConfig(context.Background(), cfg).(*foodTruckClient)
+	client := c.client
+
+	//toggling debug while requests are in flight must not race, run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(debug bool) {
+			defer wg.Done()
+			c.SetDebug(debug)
+			if _, err := c.GetTruck("marination"); err != nil {
+				t.Error(err)
+			}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+
+	if c.client != client {
+		t.Error("SetDebug replaced the http client")
+	}
+	if c.client.Jar != jar {
+		t.Error("the cookie jar was dropped")
+	}
+	if c.client.CheckRedirect == nil || c.client.CheckRedirect(nil, nil) != errNoRedirects {
+		t.Error("the redirect policy was dropped")
+	}
+}
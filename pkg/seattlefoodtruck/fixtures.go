@@ -0,0 +1,210 @@
+package seattlefoodtruck
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//DefaultFixtureDir is where the record command saves upstream responses
+const DefaultFixtureDir = "testdata/seattlefoodtruck"
+
+//sanitizedFields are the fields of upstream payloads holding contact details or
+//documents of the truck owners, blanked before a response is saved as a fixture
+var sanitizedFields = map[string]bool{
+	"email":             true,
+	"phone":             true,
+	"contact_name":      true,
+	"user_id":           true,
+	"coi":               true,
+	"health":            true,
+	"w9":                true,
+	"business_license":  true,
+	"stripe_account_id": true,
+}
+
+//dayLayout parses the days formatDay formats
+const dayLayout = "2006-January-2"
+
+//dateParams are the query parameters of the events api holding a calendar day
+var dateParams = []string{"on_day", "start_date", "end_date"}
+
+//FixtureFile returns the file under dir the response to r is saved in.
+//The name is built from the path and the sorted query, so the same call always maps
+//to the same fixture. Days in the query are named by their offset from today, day0 is
+//today and day1 tomorrow, so fixtures recorded one day answer for the same days
+//relative to when they are replayed.
+func FixtureFile(dir string, r *http.Request, today time.Time) string {
+	name := strings.Trim(r.URL.Path, "/")
+	if query := r.URL.Query(); len(query) > 0 {
+		for _, param := range dateParams {
+			if day := query.Get(param); len(day) > 0 {
+				query.Set(param, relativeDay(day, today))
+			}
+		}
+		name += "?" + query.Encode()
+	}
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, name)
+	return filepath.Join(dir, name+".json")
+}
+
+//relativeDay returns day, formatted like formatDay, as its offset from the calendar
+//day of today, or day itself when it isn't a date
+func relativeDay(day string, today time.Time) string {
+	t, err := time.Parse(dayLayout, day)
+	if err != nil {
+		return day
+	}
+	y, m, d := today.Date()
+	offset := t.Sub(time.Date(y, m, d, 0, 0, 0, 0, time.UTC)).Hours() / 24
+	return "day" + strconv.Itoa(int(offset))
+}
+
+//Sanitize blanks the contact details and documents in the JSON payload and indents it,
+//keys sorted, so fixtures diff cleanly between recordings
+func Sanitize(payload []byte) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(payload, &v); err != nil {
+		return nil, err
+	}
+	v = sanitize(v)
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+func sanitize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if sanitizedFields[k] && field != nil {
+				v[k] = zeroOf(field)
+				continue
+			}
+			v[k] = sanitize(field)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = sanitize(v[i])
+		}
+	}
+	return v
+}
+
+func zeroOf(v interface{}) interface{} {
+	switch v.(type) {
+	case string:
+		return ""
+	case float64:
+		return 0
+	}
+	return nil
+}
+
+//RecordingTransport saves the sanitized body of every successful response passing
+//through it as a fixture under Dir, days named relative to today in Location,
+//the local zone when nil
+type RecordingTransport struct {
+	Dir      string
+	Location *time.Location
+	Next     http.RoundTripper
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	var reader io.Reader = resp.Body
+	if strings.EqualFold(resp.Header.Get(contentEncodingHeader), gzipEncoding) {
+		//fixtures are saved decoded, the gzip transport leaves uncompressed responses alone
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		reader = gz
+		resp.Header.Del(contentEncodingHeader)
+		resp.Uncompressed = true
+	}
+	body, err := ioutil.ReadAll(reader)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	sanitized, err := Sanitize(body)
+	if err != nil {
+		return nil, fmt.Errorf("recording %s: %v", req.URL.Path, err)
+	}
+	file := FixtureFile(t.Dir, req, today(t.Location))
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(file, sanitized, 0644); err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(sanitized))
+	resp.ContentLength = int64(len(sanitized))
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+//FixtureTransport answers requests with the fixtures saved under Dir instead of
+//calling upstream, and with 404 Not Found for requests that weren't recorded. Days are
+//relative to today in Location, the local zone when nil.
+type FixtureTransport struct {
+	Dir      string
+	Location *time.Location
+}
+
+func (t *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	status, contentType := http.StatusOK, "application/json"
+	body, err := ioutil.ReadFile(FixtureFile(t.Dir, req, today(t.Location)))
+	switch {
+	case os.IsNotExist(err):
+		status, contentType = http.StatusNotFound, "text/plain; charset=utf-8"
+		body = []byte("no fixture recorded for " + req.URL.String() + "\n")
+	case err != nil:
+		return nil, err
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {contentType}},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func today(loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.Local
+	}
+	return time.Now().In(loc)
+}
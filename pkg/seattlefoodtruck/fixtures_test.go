@@ -0,0 +1,70 @@
+package seattlefoodtruck
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFixtureFile(t *testing.T) {
+	today := time.Date(2026, time.October, 15, 23, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"no query", "https://www.seattlefoodtruck.com/api/locations/69", "api_locations_69.json"},
+		{"sorted query", "https://www.seattlefoodtruck.com/api/trucks?page=2&for_category=bbq", "api_trucks_for_category_bbq_page_2.json"},
+		{"today", "/api/events?on_day=2026-October-15&for_locations=69", "api_events_for_locations_69_on_day_day0.json"},
+		{"weeks ahead", "/api/events?on_day=2026-November-1&for_locations=69", "api_events_for_locations_69_on_day_day17.json"},
+		{"yesterday", "/api/events?on_day=2026-October-14", "api_events_on_day_day-1.json"},
+		{"range", "/api/events?start_date=2026-October-15&end_date=2026-October-21", "api_events_end_date_day6_start_date_day0.json"},
+		{"not a date", "/api/events?on_day=today", "api_events_on_day_today.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := FixtureFile("testdata", r, today); got != filepath.Join("testdata", tt.want) {
+				t.Errorf("FixtureFile(%s) = %s, want %s", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFixtureFileRecordedAnotherDay(t *testing.T) {
+	recorded, _ := http.NewRequest(http.MethodGet, "/api/events?on_day=2026-October-15", nil)
+	replayed, _ := http.NewRequest(http.MethodGet, "/api/events?on_day=2026-October-20", nil)
+	a := FixtureFile("", recorded, time.Date(2026, time.October, 14, 9, 0, 0, 0, time.UTC))
+	b := FixtureFile("", replayed, time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC))
+	if a != b {
+		t.Errorf("tomorrow recorded as %s is replayed from %s", a, b)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	got, err := Sanitize([]byte(`{"name":"Marination","email":"owner@example.com","phone":"2065550100",` +
+		`"trucks":[{"user_id":7,"coi":null,"id":"marination"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "email": "",
+  "name": "Marination",
+  "phone": "",
+  "trucks": [
+    {
+      "coi": null,
+      "id": "marination",
+      "user_id": 0
+    }
+  ]
+}
+`
+	if string(got) != want {
+		t.Errorf("Sanitize = %s, want %s", got, want)
+	}
+}
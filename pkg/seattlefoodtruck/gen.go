@@ -0,0 +1,261 @@
+//go:build ignore
+// +build ignore
+
+//gen generates models and operations of the food truck client from openapi.yaml.
+//Usage: go run gen.go -spec openapi.yaml -out openapi_gen.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+var initialisms = map[string]string{
+	"id":  "ID",
+	"uid": "UID",
+	"url": "URL",
+}
+
+type generator struct {
+	buf     bytes.Buffer
+	emitted map[string]bool
+}
+
+func main() {
+	var spec, out, pkg string
+	flag.StringVar(&spec, "spec", "openapi.yaml", "OpenAPI document to generate from")
+	flag.StringVar(&out, "out", "openapi_gen.go", "Go file to write")
+	flag.StringVar(&pkg, "package", "seattlefoodtruck", "Go package name")
+	flag.Parse()
+
+	data, err := ioutil.ReadFile(spec)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		log.Fatal(err)
+	}
+
+	g := &generator{emitted: map[string]bool{}}
+	g.printf("// Code generated by gen.go from %s; DO NOT EDIT.\n\n", spec)
+	g.printf("package %s\n\n", pkg)
+	g.printf("import (\n\"context\"\n\"fmt\"\n\"net/url\"\n\"strconv\"\n)\n\n")
+
+	g.operations(get(doc, "paths"))
+	schemas := get(get(doc, "components"), "schemas")
+	for _, item := range schemas {
+		g.schema(item.Key.(string), item.Value.(yaml.MapSlice))
+	}
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		log.Fatalf("formatting generated code: %v\n%s", err, g.buf.String())
+	}
+	if err := ioutil.WriteFile(out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (g *generator) printf(f string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, f, args...)
+}
+
+func (g *generator) operations(paths yaml.MapSlice) {
+	for _, p := range paths {
+		path := p.Key.(string)
+		op := get(p.Value.(yaml.MapSlice), "get")
+		if op == nil {
+			continue
+		}
+		id := str(op, "operationId")
+		result := refName(str(get(get(get(get(get(op, "responses"), "200"), "content"), "application/json"), "schema"), "$ref"))
+
+		var pathParams []string
+		var query []yaml.MapSlice
+		for _, item := range list(op, "parameters") {
+			param := item.(yaml.MapSlice)
+			switch str(param, "in") {
+			case "path":
+				pathParams = append(pathParams, str(param, "name"))
+			case "query":
+				query = append(query, param)
+			}
+		}
+
+		params := ""
+		if len(query) > 0 {
+			params = exported(id) + "Params"
+			g.printf("// %s holds the query parameters of %s, zero values are not sent.\n", params, id)
+			g.printf("type %s struct {\n", params)
+			for _, q := range query {
+				g.printf("%s %s\n", goName(str(q, "name")), goType(get(q, "schema")))
+			}
+			g.printf("}\n\n")
+			g.printf("func (p %s) values() map[string]string {\n", params)
+			g.printf("qs := map[string]string{}\n")
+			for _, q := range query {
+				name, field := str(q, "name"), "p."+goName(str(q, "name"))
+				switch str(get(q, "schema"), "type") {
+				case "boolean":
+					g.printf("if %s {\nqs[%q] = strconv.FormatBool(%s)\n}\n", field, name, field)
+				case "integer":
+					g.printf("if %s != 0 {\nqs[%q] = strconv.Itoa(%s)\n}\n", field, name, field)
+				default:
+					g.printf("if len(%s) > 0 {\nqs[%q] = %s\n}\n", field, name, field)
+				}
+			}
+			g.printf("return qs\n}\n\n")
+		}
+
+		args := []string{"ctx context.Context"}
+		pattern := path
+		var formatArgs []string
+		for _, pp := range pathParams {
+			args = append(args, goParam(pp)+" string")
+			pattern = strings.Replace(pattern, "{"+pp+"}", "%s", 1)
+			formatArgs = append(formatArgs, "url.PathEscape("+goParam(pp)+")")
+		}
+		qs := "nil"
+		if len(params) > 0 {
+			args = append(args, "params "+params)
+			qs = "params.values()"
+		}
+		resourcePath := fmt.Sprintf("%q", pattern)
+		if len(formatArgs) > 0 {
+			resourcePath = fmt.Sprintf("fmt.Sprintf(%q, %s)", pattern, strings.Join(formatArgs, ", "))
+		}
+
+		g.printf("// %s %s.\n", id, str(op, "description"))
+		g.printf("func (c *foodTruckClient) %s(%s) (%s, error) {\n", id, strings.Join(args, ", "), result)
+		g.printf("var out %s\n\n", result)
+		g.printf("endpoint := fmt.Sprintf(\"%%s://%%s%%s%%s\", c.scheme, c.host, c.basePath, %s)\n", resourcePath)
+		g.printf("c.logger.Infof(\"Endpoint: %%s\", endpoint)\n\n")
+		g.printf("err := c.callAPI(ctx, endpoint, %s, &out)\n", qs)
+		g.printf("return out, err\n}\n\n")
+	}
+}
+
+func (g *generator) schema(name string, s yaml.MapSlice) {
+	if g.emitted[name] {
+		return
+	}
+	g.emitted[name] = true
+
+	var nested []yaml.MapSlice
+	var nestedNames []string
+
+	g.printf("// %s %s\n", name, str(s, "description"))
+	g.printf("type %s struct {\n", name)
+	for _, p := range get(s, "properties") {
+		prop := p.Key.(string)
+		ps := p.Value.(yaml.MapSlice)
+		typ := goType(ps)
+		if str(ps, "type") == "object" && len(get(ps, "properties")) > 0 {
+			typ = str(ps, "x-go-type-name")
+			nested = append(nested, ps)
+			nestedNames = append(nestedNames, typ)
+		}
+		g.printf("%s %s `json:\"%s\"`\n", goName(prop), typ, prop)
+	}
+	g.printf("}\n\n")
+
+	for i, n := range nested {
+		g.schema(nestedNames[i], n)
+	}
+}
+
+func goType(s yaml.MapSlice) string {
+	if ref := str(s, "$ref"); len(ref) > 0 {
+		return refName(ref)
+	}
+	switch str(s, "type") {
+	case "string":
+		return "string"
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + goType(get(s, "items"))
+	case "object":
+		if n := str(s, "x-go-type-name"); len(n) > 0 {
+			return n
+		}
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+func goName(snake string) string {
+	var sb strings.Builder
+	for _, part := range strings.Split(snake, "_") {
+		if i, ok := initialisms[part]; ok {
+			sb.WriteString(i)
+			continue
+		}
+		sb.WriteString(exported(part))
+	}
+	return sb.String()
+}
+
+func goParam(snake string) string {
+	n := goName(snake)
+	if i, ok := initialisms[snake]; ok && i == n {
+		return snake
+	}
+	return strings.ToLower(n[:1]) + n[1:]
+}
+
+func exported(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func get(m yaml.MapSlice, key string) yaml.MapSlice {
+	for _, item := range m {
+		if k, ok := item.Key.(string); ok && k == key {
+			if v, ok := item.Value.(yaml.MapSlice); ok {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+func str(m yaml.MapSlice, key string) string {
+	for _, item := range m {
+		if k, ok := item.Key.(string); ok && k == key {
+			if v, ok := item.Value.(string); ok {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+func list(m yaml.MapSlice, key string) []interface{} {
+	for _, item := range m {
+		if k, ok := item.Key.(string); ok && k == key {
+			if v, ok := item.Value.([]interface{}); ok {
+				return v
+			}
+		}
+	}
+	return nil
+}
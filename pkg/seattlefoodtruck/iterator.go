@@ -0,0 +1,120 @@
+package seattlefoodtruck
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+//pager walks the pages of a paginated collection resource one page at a time
+type pager struct {
+	c        *foodTruckClient
+	ctx      context.Context
+	endpoint string
+	qs       map[string]string
+
+	page       int
+	totalPages int
+	err        error
+}
+
+func (c *foodTruckClient) newPager(ctx context.Context, resourcePath string, filter map[string]string) pager {
+	qs := make(map[string]string, len(filter)+1)
+	for k, v := range filter {
+		qs[k] = v
+	}
+	return pager{
+		c:        c,
+		ctx:      ctx,
+		endpoint: fmt.Sprintf("%s://%s%s/%s", c.scheme, c.host, c.basePath, resourcePath),
+		qs:       qs,
+	}
+}
+
+//fetch loads the next page into data and reports whether there was a page to load
+func (p *pager) fetch(data interface{}, totalPages func() int) bool {
+	if p.err != nil || (p.page > 0 && p.page >= p.totalPages) {
+		return false
+	}
+	p.page++
+	p.qs["page"] = strconv.Itoa(p.page)
+	p.c.logger.Infof("Endpoint: %s page %v", p.endpoint, p.page)
+
+	if p.err = p.c.callAPI(p.ctx, p.endpoint, p.qs, data); p.err != nil {
+		return false
+	}
+	p.totalPages = totalPages()
+	return true
+}
+
+//EventsIterator streams events across pages of the events api
+type EventsIterator struct {
+	p      pager
+	buffer []Event
+	event  Event
+}
+
+//IterateEvents returns an iterator over all events matching filter, which holds
+//query string parameters of the events api such as for_locations or on_day
+func (c *foodTruckClient) IterateEvents(ctx context.Context, filter map[string]string) *EventsIterator {
+	return &EventsIterator{p: c.newPager(ctx, EventsResourcePath, filter)}
+}
+
+//Next advances to the next event, fetching another page when required
+func (it *EventsIterator) Next() bool {
+	for len(it.buffer) == 0 {
+		var evr EventsResponse
+		if !it.p.fetch(&evr, func() int { return evr.Pagination.TotalPages }) {
+			return false
+		}
+		it.buffer = evr.Events
+	}
+	it.event, it.buffer = it.buffer[0], it.buffer[1:]
+	return true
+}
+
+//Event returns the current event
+func (it *EventsIterator) Event() Event {
+	return it.event
+}
+
+//Err returns the error that stopped the iteration, if any
+func (it *EventsIterator) Err() error {
+	return it.p.err
+}
+
+//TrucksIterator streams trucks across pages of the trucks api
+type TrucksIterator struct {
+	p      pager
+	buffer []Truck
+	truck  Truck
+}
+
+//IterateTrucks returns an iterator over all trucks matching filter, which holds
+//query string parameters of the trucks api such as food_category
+func (c *foodTruckClient) IterateTrucks(ctx context.Context, filter map[string]string) *TrucksIterator {
+	return &TrucksIterator{p: c.newPager(ctx, TrucksResourcePath, filter)}
+}
+
+//Next advances to the next truck, fetching another page when required
+func (it *TrucksIterator) Next() bool {
+	for len(it.buffer) == 0 {
+		var tr TrucksResponse
+		if !it.p.fetch(&tr, func() int { return tr.Pagination.TotalPages }) {
+			return false
+		}
+		it.buffer = tr.Trucks
+	}
+	it.truck, it.buffer = it.buffer[0], it.buffer[1:]
+	return true
+}
+
+//Truck returns the current truck
+func (it *TrucksIterator) Truck() Truck {
+	return it.truck
+}
+
+//Err returns the error that stopped the iteration, if any
+func (it *TrucksIterator) Err() error {
+	return it.p.err
+}
@@ -0,0 +1,41 @@
+package seattlefoodtruck
+
+import (
+	"context"
+	"strings"
+)
+
+//FindLocations searches locations by name or address, optionally limited to a neighborhood.
+//A neighborhoodID of 0 searches all neighborhoods. Upstream filtering is best effort so
+//candidates are matched again locally against name and address.
+func (c *foodTruckClient) FindLocations(ctx context.Context, query string, neighborhoodID int) ([]Location, error) {
+	q := trimSpaceAndLower(query)
+	lr, err := c.listLocations(ctx, ListLocationsParams{
+		Query:        q,
+		Neighborhood: neighborhoodID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []Location
+	for _, l := range lr.Locations {
+		if neighborhoodID > 0 && l.NeighborhoodID != neighborhoodID {
+			continue
+		}
+		if len(q) > 0 && !matchesLocation(l, q) {
+			continue
+		}
+		candidates = append(candidates, l)
+	}
+	return candidates, nil
+}
+
+func matchesLocation(l Location, q string) bool {
+	for _, f := range []string{l.Name, l.Address, l.FilteredAddress, l.Slug} {
+		if strings.Contains(strings.ToLower(f), q) {
+			return true
+		}
+	}
+	return false
+}
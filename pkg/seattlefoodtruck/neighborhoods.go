@@ -0,0 +1,67 @@
+package seattlefoodtruck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+func (c *foodTruckClient) GetNeighborhoods() ([]Neighborhood, error) {
+	nr, err := c.listNeighborhoods(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	return nr.Neighborhoods, nil
+}
+
+func (c *foodTruckClient) RefreshNeighborhoods() error {
+	neighborhoods, err := c.GetNeighborhoods()
+	if err != nil {
+		return err
+	}
+	catalog := make(map[string]Neighborhood, len(neighborhoods)*3)
+	for _, n := range neighborhoods {
+		catalog[trimSpaceAndLower(n.Name)] = n
+		catalog[strconv.Itoa(n.ID)] = n
+		if len(n.Slug) > 0 {
+			catalog[trimSpaceAndLower(n.Slug)] = n
+		}
+	}
+
+	c.mu.Lock()
+	c.neighborhoods = catalog
+	c.mu.Unlock()
+
+	c.logger.Infof("Cached %v neighborhoods", len(neighborhoods))
+	return nil
+}
+
+//LookupNeighborhood finds a neighborhood by name, slug or ID using the cached catalog.
+//The catalog is loaded on first use if it has not been refreshed yet.
+func (c *foodTruckClient) LookupNeighborhood(name string) (Neighborhood, error) {
+	var n Neighborhood
+
+	key := trimSpaceAndLower(name)
+	if len(key) == 0 {
+		return n, errors.New("Neighborhood name is missing")
+	}
+
+	c.mu.RLock()
+	loaded := c.neighborhoods != nil
+	n, ok := c.neighborhoods[key]
+	c.mu.RUnlock()
+
+	if !loaded {
+		if err := c.RefreshNeighborhoods(); err != nil {
+			return n, err
+		}
+		c.mu.RLock()
+		n, ok = c.neighborhoods[key]
+		c.mu.RUnlock()
+	}
+	if !ok {
+		return n, fmt.Errorf("Neighborhood %s not found", name)
+	}
+	return n, nil
+}
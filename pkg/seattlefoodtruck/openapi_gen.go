@@ -0,0 +1,386 @@
+// Code generated by gen.go from openapi.yaml; DO NOT EDIT.
+
+package seattlefoodtruck
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// ListEventsParams holds the query parameters of listEvents, zero values are not sent.
+type ListEventsParams struct {
+	IncludeBookings   bool
+	WithActiveTrucks  bool
+	WithBookingStatus string
+	OnDay             string
+	StartDate         string
+	EndDate           string
+	ForLocations      string
+	Page              int
+}
+
+func (p ListEventsParams) values() map[string]string {
+	qs := map[string]string{}
+	if p.IncludeBookings {
+		qs["include_bookings"] = strconv.FormatBool(p.IncludeBookings)
+	}
+	if p.WithActiveTrucks {
+		qs["with_active_trucks"] = strconv.FormatBool(p.WithActiveTrucks)
+	}
+	if len(p.WithBookingStatus) > 0 {
+		qs["with_booking_status"] = p.WithBookingStatus
+	}
+	if len(p.OnDay) > 0 {
+		qs["on_day"] = p.OnDay
+	}
+	if len(p.StartDate) > 0 {
+		qs["start_date"] = p.StartDate
+	}
+	if len(p.EndDate) > 0 {
+		qs["end_date"] = p.EndDate
+	}
+	if len(p.ForLocations) > 0 {
+		qs["for_locations"] = p.ForLocations
+	}
+	if p.Page != 0 {
+		qs["page"] = strconv.Itoa(p.Page)
+	}
+	return qs
+}
+
+// listEvents lists events with their bookings.
+func (c *foodTruckClient) listEvents(ctx context.Context, params ListEventsParams) (EventsResponse, error) {
+	var out EventsResponse
+
+	endpoint := fmt.Sprintf("%s://%s%s%s", c.scheme, c.host, c.basePath, "/events")
+	c.logger.Infof("Endpoint: %s", endpoint)
+
+	err := c.callAPI(ctx, endpoint, params.values(), &out)
+	return out, err
+}
+
+// ListLocationsParams holds the query parameters of listLocations, zero values are not sent.
+type ListLocationsParams struct {
+	Query          string
+	Neighborhood   int
+	OnlyWithEvents bool
+	Page           int
+}
+
+func (p ListLocationsParams) values() map[string]string {
+	qs := map[string]string{}
+	if len(p.Query) > 0 {
+		qs["query"] = p.Query
+	}
+	if p.Neighborhood != 0 {
+		qs["neighborhood"] = strconv.Itoa(p.Neighborhood)
+	}
+	if p.OnlyWithEvents {
+		qs["only_with_events"] = strconv.FormatBool(p.OnlyWithEvents)
+	}
+	if p.Page != 0 {
+		qs["page"] = strconv.Itoa(p.Page)
+	}
+	return qs
+}
+
+// listLocations lists locations matching the given filters.
+func (c *foodTruckClient) listLocations(ctx context.Context, params ListLocationsParams) (LocationsResponse, error) {
+	var out LocationsResponse
+
+	endpoint := fmt.Sprintf("%s://%s%s%s", c.scheme, c.host, c.basePath, "/locations")
+	c.logger.Infof("Endpoint: %s", endpoint)
+
+	err := c.callAPI(ctx, endpoint, params.values(), &out)
+	return out, err
+}
+
+// getLocation gets a single location.
+func (c *foodTruckClient) getLocation(ctx context.Context, id string) (Location, error) {
+	var out Location
+
+	endpoint := fmt.Sprintf("%s://%s%s%s", c.scheme, c.host, c.basePath, fmt.Sprintf("/locations/%s", url.PathEscape(id)))
+	c.logger.Infof("Endpoint: %s", endpoint)
+
+	err := c.callAPI(ctx, endpoint, nil, &out)
+	return out, err
+}
+
+// ListTrucksParams holds the query parameters of listTrucks, zero values are not sent.
+type ListTrucksParams struct {
+	FoodCategory string
+	Active       bool
+	Page         int
+}
+
+func (p ListTrucksParams) values() map[string]string {
+	qs := map[string]string{}
+	if len(p.FoodCategory) > 0 {
+		qs["food_category"] = p.FoodCategory
+	}
+	if p.Active {
+		qs["active"] = strconv.FormatBool(p.Active)
+	}
+	if p.Page != 0 {
+		qs["page"] = strconv.Itoa(p.Page)
+	}
+	return qs
+}
+
+// listTrucks lists trucks matching the given filters.
+func (c *foodTruckClient) listTrucks(ctx context.Context, params ListTrucksParams) (TrucksResponse, error) {
+	var out TrucksResponse
+
+	endpoint := fmt.Sprintf("%s://%s%s%s", c.scheme, c.host, c.basePath, "/trucks")
+	c.logger.Infof("Endpoint: %s", endpoint)
+
+	err := c.callAPI(ctx, endpoint, params.values(), &out)
+	return out, err
+}
+
+// getTruck gets a single truck with menu, photos and ratings.
+func (c *foodTruckClient) getTruck(ctx context.Context, id string) (Truck, error) {
+	var out Truck
+
+	endpoint := fmt.Sprintf("%s://%s%s%s", c.scheme, c.host, c.basePath, fmt.Sprintf("/trucks/%s", url.PathEscape(id)))
+	c.logger.Infof("Endpoint: %s", endpoint)
+
+	err := c.callAPI(ctx, endpoint, nil, &out)
+	return out, err
+}
+
+// listNeighborhoods lists all neighborhoods.
+func (c *foodTruckClient) listNeighborhoods(ctx context.Context) (NeighborhoodsResponse, error) {
+	var out NeighborhoodsResponse
+
+	endpoint := fmt.Sprintf("%s://%s%s%s", c.scheme, c.host, c.basePath, "/neighborhoods")
+	c.logger.Infof("Endpoint: %s", endpoint)
+
+	err := c.callAPI(ctx, endpoint, nil, &out)
+	return out, err
+}
+
+// listFoodCategories lists all food categories.
+func (c *foodTruckClient) listFoodCategories(ctx context.Context) (FoodCategoriesResponse, error) {
+	var out FoodCategoriesResponse
+
+	endpoint := fmt.Sprintf("%s://%s%s%s", c.scheme, c.host, c.basePath, "/food_categories")
+	c.logger.Infof("Endpoint: %s", endpoint)
+
+	err := c.callAPI(ctx, endpoint, nil, &out)
+	return out, err
+}
+
+// Pagination describes the page returned by a collection resource
+type Pagination struct {
+	Page       int `json:"page"`
+	TotalPages int `json:"total_pages"`
+	TotalCount int `json:"total_count"`
+}
+
+// EventsResponse is response from events api
+type EventsResponse struct {
+	Pagination Pagination `json:"pagination"`
+	Events     []Event    `json:"events"`
+}
+
+// Event represent an event
+type Event struct {
+	ID              int             `json:"id"`
+	Name            string          `json:"name"`
+	Description     string          `json:"description"`
+	StartTime       string          `json:"start_time"`
+	EndTime         string          `json:"end_time"`
+	CreatedAt       string          `json:"created_at"`
+	UpdatedAt       string          `json:"updated_at"`
+	EventID         int             `json:"event_id"`
+	Bookings        []Booking       `json:"bookings"`
+	WaitlistEntries []WaitlistEntry `json:"waitlist_entries"`
+}
+
+// Booking represents a truck booked for an event
+type Booking struct {
+	ID     int          `json:"id"`
+	Status string       `json:"status"`
+	Paid   bool         `json:"paid"`
+	Truck  BookingTruck `json:"truck"`
+}
+
+// BookingTruck is the summary of a truck embedded in a booking, food categories are names only
+type BookingTruck struct {
+	Name           string   `json:"name"`
+	Trailer        bool     `json:"trailer"`
+	FoodCategories []string `json:"food_categories"`
+	ID             string   `json:"id"`
+	UID            int      `json:"uid"`
+	FeaturedPhoto  string   `json:"featured_photo"`
+}
+
+// WaitlistEntry represents a truck waiting for a spot at an event
+type WaitlistEntry struct {
+	ID         int           `json:"id"`
+	Expiration interface{}   `json:"expiration"`
+	Position   int           `json:"position"`
+	Truck      WaitlistTruck `json:"truck"`
+}
+
+// WaitlistTruck is the truck waiting for a spot
+type WaitlistTruck struct {
+	Slug string `json:"slug"`
+}
+
+// LocationsResponse is response from locations api
+type LocationsResponse struct {
+	Pagination Pagination `json:"pagination"`
+	Locations  []Location `json:"locations"`
+}
+
+// Location represents a location where you can find truck
+type Location struct {
+	Name            string               `json:"name"`
+	Longitude       float64              `json:"longitude"`
+	Latitude        float64              `json:"latitude"`
+	Address         string               `json:"address"`
+	Photo           string               `json:"photo"`
+	GooglePlaceID   string               `json:"google_place_id"`
+	CreatedAt       string               `json:"created_at"`
+	NeighborhoodID  int                  `json:"neighborhood_id"`
+	Slug            string               `json:"slug"`
+	FilteredAddress string               `json:"filtered_address"`
+	ID              string               `json:"id"`
+	UID             int                  `json:"uid"`
+	Neighborhood    LocationNeighborhood `json:"neighborhood"`
+	Pod             Pod                  `json:"pod"`
+}
+
+// LocationNeighborhood is the neighborhood summary embedded in a location
+type LocationNeighborhood struct {
+	Name string `json:"name"`
+	ID   int    `json:"id"`
+}
+
+// Pod represents a group of trucks sharing a location
+type Pod struct {
+	Name                    string      `json:"name"`
+	Slug                    string      `json:"slug"`
+	Description             string      `json:"description"`
+	LoadInSheet             string      `json:"load_in_sheet"`
+	W9Required              bool        `json:"w9_required"`
+	CoiRequired             bool        `json:"coi_required"`
+	HealthRequired          bool        `json:"health_required"`
+	HealthSnohomishRequired interface{} `json:"health_snohomish_required"`
+}
+
+// TrucksResponse is response from trucks api
+type TrucksResponse struct {
+	Pagination Pagination `json:"pagination"`
+	Trucks     []Truck    `json:"trucks"`
+}
+
+// Truck represents a food truck
+type Truck struct {
+	Name                      string         `json:"name"`
+	Rating                    float64        `json:"rating"`
+	UserID                    int            `json:"user_id"`
+	Featured                  bool           `json:"featured"`
+	RatingCount               int            `json:"rating_count"`
+	ID                        string         `json:"id"`
+	UID                       int            `json:"uid"`
+	FeaturedPhoto             string         `json:"featured_photo"`
+	Facebook                  string         `json:"facebook"`
+	CreatedAt                 string         `json:"created_at"`
+	UpdatedAt                 string         `json:"updated_at"`
+	Twitter                   string         `json:"twitter"`
+	Instagram                 string         `json:"instagram"`
+	Yelp                      string         `json:"yelp"`
+	Description               string         `json:"description"`
+	Phone                     string         `json:"phone"`
+	Email                     string         `json:"email"`
+	Website                   string         `json:"website"`
+	Active                    bool           `json:"active"`
+	ContactName               string         `json:"contact_name"`
+	TruckLength               int            `json:"truck_length"`
+	TruckWidth                int            `json:"truck_width"`
+	Trailer                   bool           `json:"trailer"`
+	AcceptsCreditCards        bool           `json:"accepts_credit_cards"`
+	GlutenFree                bool           `json:"gluten_free"`
+	Vegetarian                bool           `json:"vegetarian"`
+	Vegan                     bool           `json:"vegan"`
+	Paleo                     bool           `json:"paleo"`
+	FutureBookings            int            `json:"future_bookings"`
+	FuturePodEvents           int            `json:"future_pod_events"`
+	Coi                       string         `json:"coi"`
+	CoiExpiration             string         `json:"coi_expiration"`
+	CoiStatus                 string         `json:"coi_status"`
+	CoiApproved               bool           `json:"coi_approved"`
+	Health                    string         `json:"health"`
+	HealthExpiration          string         `json:"health_expiration"`
+	HealthStatus              string         `json:"health_status"`
+	HealthApproved            bool           `json:"health_approved"`
+	W9                        string         `json:"w9"`
+	W9Expiration              interface{}    `json:"w9_expiration"`
+	W9Status                  string         `json:"w9_status"`
+	W9Approved                bool           `json:"w9_approved"`
+	HealthSnohomish           string         `json:"health_snohomish"`
+	HealthSnohomishExpiration string         `json:"health_snohomish_expiration"`
+	HealthSnohomishStatus     string         `json:"health_snohomish_status"`
+	HealthSnohomishApproved   bool           `json:"health_snohomish_approved"`
+	MenuItems                 []MenuItem     `json:"menu_items"`
+	Photos                    []Photo        `json:"photos"`
+	RelatedTrucks             []RelatedTruck `json:"related_trucks"`
+	FoodCategories            []FoodCategory `json:"food_categories"`
+}
+
+// MenuItem represents an item on a truck menu
+type MenuItem struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Price       float64 `json:"price"`
+	ID          int     `json:"id"`
+}
+
+// Photo represents a photo uploaded by a truck
+type Photo struct {
+	ID       int    `json:"id"`
+	File     string `json:"file"`
+	Position int    `json:"position"`
+}
+
+// RelatedTruck is a truck similar to another truck
+type RelatedTruck struct {
+	Name           string         `json:"name"`
+	Rating         float64        `json:"rating"`
+	RatingCount    int            `json:"rating_count"`
+	ID             string         `json:"id"`
+	FeaturedPhoto  string         `json:"featured_photo"`
+	FoodCategories []FoodCategory `json:"food_categories"`
+}
+
+// FoodCategoriesResponse is response from food categories api
+type FoodCategoriesResponse struct {
+	FoodCategories []FoodCategory `json:"food_categories"`
+}
+
+// FoodCategory represents a cuisine a truck can be listed under
+type FoodCategory struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+	UID  int    `json:"uid"`
+}
+
+// NeighborhoodsResponse is response from neighborhoods api
+type NeighborhoodsResponse struct {
+	Pagination    Pagination     `json:"pagination"`
+	Neighborhoods []Neighborhood `json:"neighborhoods"`
+}
+
+// Neighborhood represents a neighborhood that groups locations
+type Neighborhood struct {
+	Name      string  `json:"name"`
+	ID        int     `json:"id"`
+	Slug      string  `json:"slug"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
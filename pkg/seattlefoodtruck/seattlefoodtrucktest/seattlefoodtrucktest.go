@@ -0,0 +1,40 @@
+//Package seattlefoodtrucktest answers the Seattle Food Truck API from recorded fixtures
+//in tests, like net/http/httptest it is meant for tests only.
+package seattlefoodtrucktest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+)
+
+//NewFixtureServer returns a server answering like upstream with the fixtures saved
+//under dir, for clients that can only be pointed at a URL. Days are relative to today
+//in the local zone.
+func NewFixtureServer(dir string) *httptest.Server {
+	fixtures := &seattlefoodtruck.FixtureTransport{Dir: dir}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := fixtures.RoundTrip(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer resp.Body.Close()
+		for k, v := range resp.Header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(resp.StatusCode)
+		io.Copy(w, resp.Body)
+	}))
+}
+
+//NewFakeClient returns a Food Truck Client answered from the fixtures saved under dir,
+//without any network access
+func NewFakeClient(ctx context.Context, dir string) seattlefoodtruck.FoodTruckClient {
+	cfg := seattlefoodtruck.NewConfiguration()
+	cfg.HTTPClient = &http.Client{Transport: &seattlefoodtruck.FixtureTransport{Dir: dir, Location: cfg.Location}}
+	return seattlefoodtruck.NewFoodTruckClientFromConfig(ctx, cfg)
+}
@@ -0,0 +1,117 @@
+package seattlefoodtrucktest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/appsbyram/seafoodtruck-slack/pkg/seattlefoodtruck"
+)
+
+const eventsPayload = `{"pagination":{"page":1,"total_pages":1},"events":[{"id":1,"name":"Lunch",` +
+	`"start_time":"2026-10-16T11:00:00.000-07:00","end_time":"2026-10-16T14:00:00.000-07:00",` +
+	`"bookings":[{"id":10,"status":"approved","truck":{"name":"Marination","id":"marination"}}]}]}`
+
+//record saves the responses of a fake upstream to the calls the bot makes as fixtures
+//under dir, going through the recording transport like the record command
+func record(t *testing.T, dir string, day time.Time) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/locations/69":
+			fmt.Fprint(w, `{"name":"South Lake Union","address":"400 Fairview Ave N","email":"owner@example.com"}`)
+		case "/api/events":
+			fmt.Fprint(w, eventsPayload)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer upstream.Close()
+
+	u, _ := url.Parse(upstream.URL)
+	cfg := seattlefoodtruck.NewConfiguration()
+	cfg.Scheme, cfg.Host = u.Scheme, u.Host
+	cfg.HTTPClient = &http.Client{Transport: &seattlefoodtruck.RecordingTransport{Dir: dir, Location: cfg.Location}}
+	recorder := seattlefoodtruck.NewFoodTruckClientFromConfig(context.Background(), cfg)
+	if _, err := recorder.GetLocation("69"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recorder.GetEventsOn(context.Background(), "69", day); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func tempDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestNewFakeClient(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	record(t, dir, tomorrow)
+
+	client := NewFakeClient(context.Background(), dir)
+	loc, err := client.GetLocation("69")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc.Name != "South Lake Union" || loc.Address != "400 Fairview Ave N" {
+		t.Errorf("GetLocation = %+v, want the recorded location", loc)
+	}
+	events, err := client.GetEvents("69", seattlefoodtruck.Tomorrow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want seattlefoodtruck.EventsResponse
+	if err := json.Unmarshal([]byte(eventsPayload), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(events, want.Events) {
+		t.Errorf("GetEvents = %+v, want %+v", events, want.Events)
+	}
+	if events, err := client.GetEvents("69", seattlefoodtruck.Today); err == nil {
+		t.Errorf("GetEvents of a day that wasn't recorded = %+v, want an error", events)
+	}
+}
+
+func TestNewFixtureServer(t *testing.T) {
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	record(t, dir, time.Now())
+
+	srv := NewFixtureServer(dir)
+	defer srv.Close()
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/api/locations/69", http.StatusOK, `"email": ""`},
+		{"/api/locations/123", http.StatusNotFound, "no fixture recorded for /api/locations/123"},
+	}
+	for _, tt := range tests {
+		resp, err := http.Get(srv.URL + tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.status || !strings.Contains(string(body), tt.body) {
+			t.Errorf("GET %s = %d %s, want %d with %q", tt.path, resp.StatusCode, body, tt.status, tt.body)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+func init() {
+	Register("secretsmanager", &secretsManager{})
+	Register("ssm", &parameterStore{})
+}
+
+var (
+	sessionOnce sync.Once
+	awsSession  *session.Session
+	sessionErr  error
+)
+
+//awsSessionFor returns the session shared by the aws backends, configured from the
+//usual AWS_* environment, shared config and instance roles
+func awsSessionFor() (*session.Session, error) {
+	sessionOnce.Do(func() {
+		awsSession, sessionErr = session.NewSessionWithOptions(session.Options{
+			SharedConfigState: session.SharedConfigEnable,
+		})
+	})
+	return awsSession, sessionErr
+}
+
+//secretsManager resolves secretsmanager://<secret id>, the current version of the secret
+type secretsManager struct{}
+
+func (secretsManager) Fetch(ctx context.Context, ref *url.URL) (string, error) {
+	sess, err := awsSessionFor()
+	if err != nil {
+		return "", err
+	}
+	out, err := secretsmanager.New(sess).GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(name(ref)),
+	})
+	if err != nil {
+		return "", err
+	}
+	if out.SecretString != nil {
+		return *out.SecretString, nil
+	}
+	if out.SecretBinary != nil {
+		return string(out.SecretBinary), nil
+	}
+	return "", errors.New("secret " + name(ref) + " has no value")
+}
+
+//parameterStore resolves ssm://<parameter name>, decrypting SecureString parameters.
+//Names starting with a slash take three of them, e.g. ssm:///slack/token.
+type parameterStore struct{}
+
+func (parameterStore) Fetch(ctx context.Context, ref *url.URL) (string, error) {
+	sess, err := awsSessionFor()
+	if err != nil {
+		return "", err
+	}
+	out, err := ssm.New(sess).GetParameterWithContext(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(name(ref)),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(out.Parameter.Value), nil
+}
@@ -0,0 +1,141 @@
+//Package secrets resolves references such as secretsmanager://slack/bot or
+//vault://secret/data/slack#token into the secrets they point to, so secrets don't have
+//to live in plain environment variables. Values that aren't references are secrets
+//themselves and returned unchanged.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+//Backend fetches the secret a reference points to. The fragment of a reference selects
+//a key of a json secret and is handled by the package, backends only see the rest.
+type Backend interface {
+	Fetch(ctx context.Context, ref *url.URL) (string, error)
+}
+
+var (
+	backendsMu sync.RWMutex
+	backends   = make(map[string]Backend)
+)
+
+//Register makes b resolve the references with scheme
+func Register(scheme string, b Backend) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	backends[scheme] = b
+}
+
+func backendOf(value string) (Backend, *url.URL, bool) {
+	i := strings.Index(value, "://")
+	if i <= 0 {
+		return nil, nil, false
+	}
+	backendsMu.RLock()
+	b, ok := backends[value[:i]]
+	backendsMu.RUnlock()
+	if !ok {
+		return nil, nil, false
+	}
+	ref, err := url.Parse(value)
+	if err != nil {
+		return nil, nil, false
+	}
+	return b, ref, true
+}
+
+//IsReference reports whether value points to a secret of a registered backend
+func IsReference(value string) bool {
+	_, _, ok := backendOf(value)
+	return ok
+}
+
+//Resolve returns the secret value refers to, or value itself when it isn't a reference
+func Resolve(ctx context.Context, value string) (string, error) {
+	b, ref, ok := backendOf(value)
+	if !ok {
+		return value, nil
+	}
+	key := ref.Fragment
+	ref.Fragment = ""
+	secret, err := b.Fetch(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	if len(key) == 0 {
+		return secret, nil
+	}
+	return field(secret, key)
+}
+
+//field returns key of the json object secret
+func field(secret, key string) (string, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", errors.New("secret isn't a json object, can't select " + key)
+	}
+	v, ok := fields[key]
+	if !ok {
+		return "", errors.New("secret has no key " + key)
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", errors.New("key " + key + " of secret isn't a string")
+	}
+	return s, nil
+}
+
+//name returns the secret name of ref, host and path joined
+func name(ref *url.URL) string {
+	return ref.Host + ref.Path
+}
+
+type cacheEntry struct {
+	value   string
+	expires time.Time
+}
+
+//Cache resolves references, fetching each again once ttl passed so rotated secrets
+//are picked up. A ttl of zero caches forever.
+type Cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+//NewCache returns a cache keeping secrets for ttl
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+//Resolve returns the cached secret of value, resolving it when missing or expired.
+//A failed refresh keeps serving the previous value.
+func (c *Cache) Resolve(ctx context.Context, value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	c.mu.Lock()
+	e, ok := c.entries[value]
+	c.mu.Unlock()
+	if ok && (c.ttl <= 0 || time.Now().Before(e.expires)) {
+		return e.value, nil
+	}
+
+	secret, err := Resolve(ctx, value)
+	if err != nil {
+		if ok {
+			return e.value, err
+		}
+		return "", err
+	}
+	c.mu.Lock()
+	c.entries[value] = cacheEntry{value: secret, expires: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+	return secret, nil
+}
@@ -0,0 +1,65 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+func init() {
+	Register("vault", &vault{client: http.DefaultClient})
+}
+
+//vault resolves vault://<path> against the server at VAULT_ADDR with VAULT_TOKEN. The
+//data of kv version 2 engines is unwrapped, so vault://secret/data/slack#token reads
+//the token key of the slack secret.
+type vault struct {
+	client *http.Client
+}
+
+func (v *vault) Fetch(ctx context.Context, ref *url.URL) (string, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if len(addr) == 0 {
+		return "", errors.New("VAULT_ADDR is not set")
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(addr, "/")+"/v1/"+name(ref), nil)
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("X-Vault-Token", os.Getenv("VAULT_TOKEN"))
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault answered %s for %s", resp.Status, name(ref))
+	}
+
+	var body struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	data := body.Data
+	if nested, ok := data["data"]; ok {
+		if _, versioned := data["metadata"]; versioned {
+			data = nil
+			if err := json.Unmarshal(nested, &data); err != nil {
+				return "", err
+			}
+		}
+	}
+	secret, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
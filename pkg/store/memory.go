@@ -0,0 +1,106 @@
+package store
+
+import (
+	"sync"
+	"time"
+)
+
+type entry struct {
+	value   []byte
+	expires time.Time
+}
+
+func (e entry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+//MemoryStore keeps state in memory, it is lost on restart
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]map[string]entry
+	now     func() time.Time
+}
+
+//NewMemoryStore returns an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]map[string]entry), now: time.Now}
+}
+
+//Get returns the value of key in bucket or ErrNotFound
+func (m *MemoryStore) Get(bucket, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.buckets[bucket][key]
+	if !ok || e.expired(m.now()) {
+		return nil, ErrNotFound
+	}
+	return e.value, nil
+}
+
+//Put sets key in bucket to value
+func (m *MemoryStore) Put(bucket, key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.bucket(bucket)[key] = entry{value: value}
+	return nil
+}
+
+//PutIfAbsent sets key unless it is already present and reports whether it did
+func (m *MemoryStore) PutIfAbsent(bucket, key string, value []byte, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	b := m.bucket(bucket)
+	if e, ok := b[key]; ok && !e.expired(now) {
+		return false, nil
+	}
+	e := entry{value: value}
+	if ttl > 0 {
+		e.expires = now.Add(ttl)
+	}
+	b[key] = e
+	return true, nil
+}
+
+//Delete removes key from bucket
+func (m *MemoryStore) Delete(bucket, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.buckets[bucket], key)
+	return nil
+}
+
+//List returns all keys of bucket with their values
+func (m *MemoryStore) List(bucket string) (map[string][]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	result := make(map[string][]byte)
+	for k, e := range m.buckets[bucket] {
+		if e.expired(now) {
+			delete(m.buckets[bucket], k)
+			continue
+		}
+		result[k] = e.value
+	}
+	return result, nil
+}
+
+//Close is a no-op
+func (m *MemoryStore) Close() error {
+	return nil
+}
+
+func (m *MemoryStore) bucket(name string) map[string]entry {
+	b, ok := m.buckets[name]
+	if !ok {
+		b = make(map[string]entry)
+		m.buckets[name] = b
+	}
+	return b
+}
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+const redisKeyPrefix = "seafoodtruck-slack:"
+
+//redisScanCount is how many keys List asks Redis for per SCAN
+const redisScanCount = 500
+
+//RedisStore keeps each entry under its own key, prefixed with the bucket, so entries
+//written by Put and PutIfAbsent, with a ttl or not, are stored alike and Redis expires
+//those with a ttl. Buckets kept in a hash by earlier releases are still read.
+type RedisStore struct {
+	client *redis.Client
+}
+
+//NewRedisStore connects to the Redis server at redisURL
+func NewRedisStore(redisURL string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisStore{client: redis.NewClient(opts)}, nil
+}
+
+//hashKey is the hash earlier releases kept the entries of bucket without a ttl in
+func hashKey(bucket string) string {
+	return redisKeyPrefix + bucket
+}
+
+func entryKey(bucket, key string) string {
+	return redisKeyPrefix + bucket + ":" + key
+}
+
+//Get returns the value of key in bucket or ErrNotFound
+func (r *RedisStore) Get(bucket, key string) ([]byte, error) {
+	value, err := r.client.Get(entryKey(bucket, key)).Bytes()
+	if err == redis.Nil {
+		value, err = r.client.HGet(hashKey(bucket), key).Bytes()
+	}
+	if err == redis.Nil {
+		return nil, ErrNotFound
+	}
+	return value, err
+}
+
+//Put sets key in bucket to value
+func (r *RedisStore) Put(bucket, key string, value []byte) error {
+	return r.client.Set(entryKey(bucket, key), value, 0).Err()
+}
+
+//PutIfAbsent sets key unless it is already present and reports whether it did
+func (r *RedisStore) PutIfAbsent(bucket, key string, value []byte, ttl time.Duration) (bool, error) {
+	if legacy, err := r.client.HExists(hashKey(bucket), key).Result(); err != nil || legacy {
+		return false, err
+	}
+	return r.client.SetNX(entryKey(bucket, key), value, ttl).Result()
+}
+
+//Delete removes key from bucket
+func (r *RedisStore) Delete(bucket, key string) error {
+	if err := r.client.HDel(hashKey(bucket), key).Err(); err != nil {
+		return err
+	}
+	return r.client.Del(entryKey(bucket, key)).Err()
+}
+
+//List returns all keys of bucket with their values
+func (r *RedisStore) List(bucket string) (map[string][]byte, error) {
+	legacy, err := r.client.HGetAll(hashKey(bucket)).Result()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]byte, len(legacy))
+	for k, v := range legacy {
+		result[k] = []byte(v)
+	}
+
+	prefix := entryKey(bucket, "")
+	var cursor uint64
+	for {
+		var keys []string
+		keys, cursor, err = r.client.Scan(cursor, escapeGlob(prefix)+"*", redisScanCount).Result()
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) > 0 {
+			values, err := r.client.MGet(keys...).Result()
+			if err != nil {
+				return nil, err
+			}
+			for i, v := range values {
+				//keys expiring between SCAN and MGET come back nil
+				if s, ok := v.(string); ok {
+					result[strings.TrimPrefix(keys[i], prefix)] = []byte(s)
+				}
+			}
+		}
+		if cursor == 0 {
+			return result, nil
+		}
+	}
+}
+
+//Close closes the connection pool
+func (r *RedisStore) Close() error {
+	return r.client.Close()
+}
+
+//escapeGlob escapes the characters SCAN MATCH patterns give a meaning to
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		if strings.ContainsRune(`*?[]\`, c) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,120 @@
+//go:build cgo
+// +build cgo
+
+package store
+
+import (
+	"database/sql"
+	"time"
+
+	//register the sqlite3 driver
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const schema = `CREATE TABLE IF NOT EXISTS kv (
+	bucket  TEXT NOT NULL,
+	key     TEXT NOT NULL,
+	value   BLOB NOT NULL,
+	expires INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (bucket, key)
+)`
+
+//SQLiteStore keeps state in a single table of a SQLite database
+type SQLiteStore struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+//NewSQLiteStore opens or creates the database at path
+func NewSQLiteStore(path string) (Store, error) {
+	db, err := sql.Open("sqlite3", path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, err
+	}
+	//sqlite allows a single writer
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLiteStore{db: db, now: time.Now}, nil
+}
+
+//Get returns the value of key in bucket or ErrNotFound
+func (s *SQLiteStore) Get(bucket, key string) ([]byte, error) {
+	var value []byte
+	err := s.db.QueryRow(`SELECT value FROM kv WHERE bucket = ? AND key = ? AND (expires = 0 OR expires > ?)`,
+		bucket, key, s.now().Unix()).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return value, err
+}
+
+//Put sets key in bucket to value
+func (s *SQLiteStore) Put(bucket, key string, value []byte) error {
+	_, err := s.db.Exec(`INSERT OR REPLACE INTO kv (bucket, key, value, expires) VALUES (?, ?, ?, 0)`,
+		bucket, key, value)
+	return err
+}
+
+//PutIfAbsent sets key unless it is already present and reports whether it did
+func (s *SQLiteStore) PutIfAbsent(bucket, key string, value []byte, ttl time.Duration) (bool, error) {
+	now := s.now()
+	var expires int64
+	if ttl > 0 {
+		expires = now.Add(ttl).Unix()
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM kv WHERE bucket = ? AND key = ? AND expires > 0 AND expires <= ?`,
+		bucket, key, now.Unix()); err != nil {
+		return false, err
+	}
+	res, err := tx.Exec(`INSERT OR IGNORE INTO kv (bucket, key, value, expires) VALUES (?, ?, ?, ?)`,
+		bucket, key, value, expires)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, tx.Commit()
+}
+
+//Delete removes key from bucket
+func (s *SQLiteStore) Delete(bucket, key string) error {
+	_, err := s.db.Exec(`DELETE FROM kv WHERE bucket = ? AND key = ?`, bucket, key)
+	return err
+}
+
+//List returns all keys of bucket with their values
+func (s *SQLiteStore) List(bucket string) (map[string][]byte, error) {
+	rows, err := s.db.Query(`SELECT key, value FROM kv WHERE bucket = ? AND (expires = 0 OR expires > ?)`,
+		bucket, s.now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string][]byte)
+	for rows.Next() {
+		var key string
+		var value []byte
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+	return result, rows.Err()
+}
+
+//Close closes the database
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
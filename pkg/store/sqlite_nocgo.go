@@ -0,0 +1,11 @@
+//go:build !cgo
+// +build !cgo
+
+package store
+
+import "errors"
+
+//NewSQLiteStore fails in binaries built without cgo, which the sqlite driver requires
+func NewSQLiteStore(path string) (Store, error) {
+	return nil, errors.New("store: sqlite needs a binary built with CGO_ENABLED=1")
+}
@@ -0,0 +1,29 @@
+//go:build cgo
+// +build cgo
+
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSQLiteStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	db, err := NewSQLiteStore(filepath.Join(dir, "bot.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	s := db.(*SQLiteStore)
+	var advance func(time.Duration)
+	s.now, advance = fakeNow()
+	testStore(t, s, advance)
+}
@@ -0,0 +1,96 @@
+//Package store persists the state of the bot, such as subscriptions, preferences,
+//favorites, posted message timestamps and dedup keys, behind one key value interface
+//with in-memory, SQLite and Redis backends.
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+//Buckets used by the bot
+const (
+	Subscriptions = "subscriptions"
+	Preferences   = "preferences"
+	Styles        = "styles"
+	Locales       = "locales"
+	Favorites     = "favorites"
+	Messages      = "messages"
+	Dedup         = "dedup"
+	Archive       = "archive"
+	Polls         = "polls"
+	RSVPs         = "rsvps"
+	Groups        = "groups"
+	SMS           = "sms"
+	SMSLocations  = "sms_locations"
+	Calendar      = "calendar"
+	OptOuts       = "optouts"
+)
+
+//ErrNotFound is returned by Get when a key is missing or expired
+var ErrNotFound = errors.New("store: key not found")
+
+//Store is a key value store partitioned into buckets
+type Store interface {
+	//Get returns the value of key in bucket or ErrNotFound
+	Get(bucket, key string) ([]byte, error)
+
+	//Put sets key in bucket to value
+	Put(bucket, key string, value []byte) error
+
+	//PutIfAbsent sets key unless it is already present and reports whether it did,
+	//the key expires after ttl unless ttl is zero
+	PutIfAbsent(bucket, key string, value []byte, ttl time.Duration) (bool, error)
+
+	//Delete removes key from bucket, missing keys are not an error
+	Delete(bucket, key string) error
+
+	//List returns all keys of bucket with their values
+	List(bucket string) (map[string][]byte, error)
+
+	Close() error
+}
+
+//Open returns the store described by rawURL:
+//empty or memory:// keeps state in memory, sqlite:///path/to/bot.db opens a SQLite
+//database and redis://host:6379/0 connects to Redis
+func Open(rawURL string) (Store, error) {
+	if len(rawURL) == 0 {
+		return NewMemoryStore(), nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "memory":
+		return NewMemoryStore(), nil
+	case "sqlite", "sqlite3":
+		return NewSQLiteStore(u.Host + u.Path)
+	case "redis", "rediss":
+		return NewRedisStore(rawURL)
+	}
+	return nil, fmt.Errorf("store: unsupported scheme %q", u.Scheme)
+}
+
+//GetJSON decodes the value of key in bucket into v
+func GetJSON(s Store, bucket, key string, v interface{}) error {
+	data, err := s.Get(bucket, key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+//PutJSON stores v encoded as json under key in bucket
+func PutJSON(s Store, bucket, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.Put(bucket, key, data)
+}
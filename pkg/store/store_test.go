@@ -0,0 +1,166 @@
+package store
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+//testStore runs the contract every backend must meet against s, advance moving the
+//clock s expires keys by
+func testStore(t *testing.T, s Store, advance func(time.Duration)) {
+	t.Run("get missing", func(t *testing.T) {
+		if _, err := s.Get("b", "missing"); err != ErrNotFound {
+			t.Errorf("Get of a missing key = %v, want ErrNotFound", err)
+		}
+	})
+	t.Run("put get delete", func(t *testing.T) {
+		if err := s.Put("b", "k", []byte("v1")); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Put("b", "k", []byte("v2")); err != nil {
+			t.Fatal(err)
+		}
+		if v, err := s.Get("b", "k"); err != nil || string(v) != "v2" {
+			t.Errorf("Get = %q, %v, want v2", v, err)
+		}
+		if _, err := s.Get("other", "k"); err != ErrNotFound {
+			t.Errorf("Get from another bucket = %v, want ErrNotFound", err)
+		}
+		if err := s.Delete("b", "k"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Get("b", "k"); err != ErrNotFound {
+			t.Errorf("Get of a deleted key = %v, want ErrNotFound", err)
+		}
+		if err := s.Delete("b", "k"); err != nil {
+			t.Errorf("Delete of a missing key = %v", err)
+		}
+	})
+	t.Run("put if absent", func(t *testing.T) {
+		for _, ttl := range []time.Duration{0, time.Hour} {
+			key := "absent:" + ttl.String()
+			if ok, err := s.PutIfAbsent("b", key, []byte("first"), ttl); err != nil || !ok {
+				t.Errorf("PutIfAbsent ttl %v = %v, %v, want it set", ttl, ok, err)
+			}
+			if ok, err := s.PutIfAbsent("b", key, []byte("second"), ttl); err != nil || ok {
+				t.Errorf("PutIfAbsent ttl %v of a present key = %v, %v, want it kept", ttl, ok, err)
+			}
+			if v, err := s.Get("b", key); err != nil || string(v) != "first" {
+				t.Errorf("Get = %q, %v, want first", v, err)
+			}
+		}
+		s.Put("b", "put", []byte("v"))
+		if ok, _ := s.PutIfAbsent("b", "put", []byte("other"), time.Minute); ok {
+			t.Error("PutIfAbsent replaced a key set by Put")
+		}
+	})
+	t.Run("list", func(t *testing.T) {
+		s.Put("list", "a", []byte("1"))
+		s.PutIfAbsent("list", "b", []byte("2"), 0)
+		s.PutIfAbsent("list", "c", []byte("3"), time.Hour)
+		s.Put("listed", "d", []byte("4"))
+		got, err := s.List("list")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[string][]byte{"a": []byte("1"), "b": []byte("2"), "c": []byte("3")}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("List = %v, want %v", keys(got), keys(want))
+		}
+		if got, _ := s.List("empty"); len(got) != 0 {
+			t.Errorf("List of an empty bucket = %v", keys(got))
+		}
+	})
+	t.Run("ttl", func(t *testing.T) {
+		s.PutIfAbsent("ttl", "short", []byte("v"), time.Minute)
+		s.PutIfAbsent("ttl", "long", []byte("v"), time.Hour)
+		s.Put("ttl", "forever", []byte("v"))
+		advance(2 * time.Minute)
+		if _, err := s.Get("ttl", "short"); err != ErrNotFound {
+			t.Errorf("Get of an expired key = %v, want ErrNotFound", err)
+		}
+		if _, err := s.Get("ttl", "long"); err != nil {
+			t.Errorf("Get of an unexpired key = %v", err)
+		}
+		if got, _ := s.List("ttl"); !reflect.DeepEqual(keys(got), []string{"forever", "long"}) {
+			t.Errorf("List = %v, want forever and long", keys(got))
+		}
+		if ok, err := s.PutIfAbsent("ttl", "short", []byte("again"), time.Minute); err != nil || !ok {
+			t.Errorf("PutIfAbsent of an expired key = %v, %v, want it set", ok, err)
+		}
+	})
+}
+
+func keys(m map[string][]byte) []string {
+	var ks []string
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
+
+//fakeNow returns a clock for the memory and sqlite stores and the func advancing it
+func fakeNow() (func() time.Time, func(time.Duration)) {
+	now := time.Date(2026, time.October, 15, 12, 0, 0, 0, time.UTC)
+	return func() time.Time { return now }, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+	var advance func(time.Duration)
+	s.now, advance = fakeNow()
+	testStore(t, s, advance)
+}
+
+func newMiniredis(t *testing.T) (*miniredis.Miniredis, *RedisStore) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewRedisStore("redis://" + mr.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mr, s
+}
+
+func TestRedisStore(t *testing.T) {
+	mr, s := newMiniredis(t)
+	defer mr.Close()
+	defer s.Close()
+	testStore(t, s, mr.FastForward)
+}
+
+func TestRedisStoreLegacyHash(t *testing.T) {
+	mr, s := newMiniredis(t)
+	defer mr.Close()
+	defer s.Close()
+	//buckets were kept in a hash before entries got keys of their own
+	mr.HSet(hashKey("subscriptions"), "C1", "old")
+	s.Put("subscriptions", "C2", []byte("new"))
+
+	if v, err := s.Get("subscriptions", "C1"); err != nil || string(v) != "old" {
+		t.Errorf("Get of a legacy entry = %q, %v", v, err)
+	}
+	if got, _ := s.List("subscriptions"); !reflect.DeepEqual(keys(got), []string{"C1", "C2"}) {
+		t.Errorf("List = %v, want the legacy and the new entry", keys(got))
+	}
+	if ok, _ := s.PutIfAbsent("subscriptions", "C1", []byte("v"), time.Minute); ok {
+		t.Error("PutIfAbsent replaced a legacy entry")
+	}
+	s.Delete("subscriptions", "C1")
+	if _, err := s.Get("subscriptions", "C1"); err != ErrNotFound {
+		t.Errorf("Get of a deleted legacy entry = %v, want ErrNotFound", err)
+	}
+}
+
+func TestEscapeGlob(t *testing.T) {
+	if got := escapeGlob(`a*b?[c]\`); got != `a\*b\?\[c\]\\` {
+		t.Errorf("escapeGlob = %s", got)
+	}
+}
@@ -0,0 +1,140 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	openWeatherMapURL = "https://api.openweathermap.org/data/2.5/forecast"
+	//forecasts are made every 3 hours, there's no point asking more often
+	forecastTTL = 30 * time.Minute
+)
+
+//openWeatherMap forecasts with the 5 day / 3 hour forecast of OpenWeatherMap,
+//https://openweathermap.org/forecast5
+type openWeatherMap struct {
+	key    string
+	client *http.Client
+
+	mu sync.Mutex
+	//cached are the forecasts by coordinates, guarded by mu
+	cached map[string]cachedForecasts
+}
+
+type cachedForecasts struct {
+	forecasts []Forecast
+	expires   time.Time
+}
+
+//NewOpenWeatherMap returns a Client of OpenWeatherMap authenticated with the api key.
+//Forecasts are cached a while, posts to several channels ask for the same places.
+func NewOpenWeatherMap(key string, client *http.Client) Client {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &openWeatherMap{
+		key:    key,
+		client: client,
+		cached: make(map[string]cachedForecasts),
+	}
+}
+
+func (o *openWeatherMap) Forecast(ctx context.Context, lat, lon float64, at time.Time) (Forecast, error) {
+	forecasts, err := o.forecasts(ctx, lat, lon)
+	if err != nil {
+		return Forecast{}, err
+	}
+	if len(forecasts) == 0 {
+		return Forecast{}, errors.New("no forecast")
+	}
+	closest := forecasts[0]
+	for _, f := range forecasts[1:] {
+		if abs(f.Time.Sub(at)) < abs(closest.Time.Sub(at)) {
+			closest = f
+		}
+	}
+	//the 5 day forecast doesn't reach further out
+	if abs(closest.Time.Sub(at)) > 3*time.Hour {
+		return Forecast{}, fmt.Errorf("no forecast for %s", at.Format(time.RFC3339))
+	}
+	return closest, nil
+}
+
+func (o *openWeatherMap) forecasts(ctx context.Context, lat, lon float64) ([]Forecast, error) {
+	key := fmt.Sprintf("%.3f,%.3f", lat, lon)
+	o.mu.Lock()
+	cached, ok := o.cached[key]
+	o.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.forecasts, nil
+	}
+
+	q := url.Values{
+		"lat":   {fmt.Sprintf("%f", lat)},
+		"lon":   {fmt.Sprintf("%f", lon)},
+		"units": {"imperial"},
+		"appid": {o.key},
+	}
+	req, err := http.NewRequest(http.MethodGet, openWeatherMapURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Message interface{} `json:"message"`
+		List    []struct {
+			Dt   int64 `json:"dt"`
+			Main struct {
+				Temp float64 `json:"temp"`
+			} `json:"main"`
+			Weather []struct {
+				Main        string `json:"main"`
+				Description string `json:"description"`
+			} `json:"weather"`
+			Pop float64 `json:"pop"`
+		} `json:"list"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openweathermap answered %s: %v", resp.Status, body.Message)
+	}
+
+	forecasts := make([]Forecast, 0, len(body.List))
+	for _, item := range body.List {
+		f := Forecast{
+			Time:                time.Unix(item.Dt, 0),
+			Temperature:         item.Main.Temp,
+			PrecipitationChance: item.Pop,
+		}
+		if len(item.Weather) > 0 {
+			f.Condition = item.Weather[0].Main
+			f.Description = item.Weather[0].Description
+		}
+		forecasts = append(forecasts, f)
+	}
+	o.mu.Lock()
+	o.cached[key] = cachedForecasts{forecasts: forecasts, expires: time.Now().Add(forecastTTL)}
+	o.mu.Unlock()
+	return forecasts, nil
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,44 @@
+//Package weather forecasts the weather at a place, for the lunch line of schedule posts
+package weather
+
+import (
+	"context"
+	"time"
+)
+
+//Conditions of a forecast, the groups providers report
+const (
+	Clear        = "Clear"
+	Clouds       = "Clouds"
+	Rain         = "Rain"
+	Drizzle      = "Drizzle"
+	Thunderstorm = "Thunderstorm"
+	Snow         = "Snow"
+)
+
+//Forecast is the weather expected at a time
+type Forecast struct {
+	Time time.Time
+	//Condition is one of the conditions above, or another group the provider reports
+	Condition   string
+	Description string
+	//Temperature is in degrees Fahrenheit
+	Temperature float64
+	//PrecipitationChance is the probability of rain or snow, from 0 to 1
+	PrecipitationChance float64
+}
+
+//Wet reports whether f expects rain or snow
+func (f Forecast) Wet() bool {
+	switch f.Condition {
+	case Rain, Drizzle, Thunderstorm, Snow:
+		return true
+	}
+	return false
+}
+
+//Client forecasts the weather
+type Client interface {
+	//Forecast returns the forecast at the coordinates closest to at
+	Forecast(ctx context.Context, lat, lon float64, at time.Time) (Forecast, error)
+}